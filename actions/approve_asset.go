@@ -0,0 +1,111 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/storage"
+)
+
+var _ chain.Action = (*ApproveAsset)(nil)
+
+type ApproveAsset struct {
+	// Spender is allowed to transfer [Value] out of the balance of the actor.
+	Spender codec.Address `json:"spender"`
+
+	// Asset the allowance is for.
+	Asset ids.ID `json:"asset"`
+
+	// Value replaces any previous allowance of [Spender]. 0 revokes it.
+	Value uint64 `json:"value"`
+
+	// ExpiryBlock is the block height from which the allowance can no longer
+	// be used. 0 means the allowance does not expire.
+	ExpiryBlock uint64 `json:"expiryBlock"`
+}
+
+func (*ApproveAsset) GetTypeID() uint8 {
+	return nconsts.ApproveAssetID
+}
+
+func (a *ApproveAsset) StateKeys(actor codec.Address, _ ids.ID) []string {
+	return []string{
+		string(storage.AllowanceKey(actor, a.Spender, a.Asset)),
+		heightStateKey(),
+	}
+}
+
+func (*ApproveAsset) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.AllowanceChunks, chain.HeightKeyChunks}
+}
+
+func (*ApproveAsset) OutputsWarpMessage() bool {
+	return false
+}
+
+func (a *ApproveAsset) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	if a.Spender == actor {
+		return false, ApproveAssetComputeUnits, OutputApproveSelf, nil, nil
+	}
+	if a.Value > 0 && a.ExpiryBlock != 0 {
+		parentHeight, err := getParentHeight(ctx, mu)
+		if err != nil {
+			return false, ApproveAssetComputeUnits, utils.ErrBytes(err), nil, nil
+		}
+		if a.ExpiryBlock <= parentHeight+1 {
+			return false, ApproveAssetComputeUnits, OutputInvalidExpiryBlock, nil, nil
+		}
+	}
+	if err := storage.SetAllowance(ctx, mu, actor, a.Spender, a.Asset, a.Value, a.ExpiryBlock); err != nil {
+		return false, ApproveAssetComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, ApproveAssetComputeUnits, nil, nil, nil
+}
+
+func (*ApproveAsset) MaxComputeUnits(chain.Rules) uint64 {
+	return ApproveAssetComputeUnits
+}
+
+func (*ApproveAsset) Size() int {
+	return codec.AddressLen + hconsts.IDLen + 2*hconsts.Uint64Len
+}
+
+func (a *ApproveAsset) Marshal(p *codec.Packer) {
+	p.PackAddress(a.Spender)
+	p.PackID(a.Asset)
+	p.PackUint64(a.Value)
+	p.PackUint64(a.ExpiryBlock)
+}
+
+func UnmarshalApproveAsset(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var approve ApproveAsset
+	p.UnpackAddress(&approve.Spender)
+	p.UnpackID(false, &approve.Asset) // empty ID is the native asset
+	approve.Value = p.UnpackUint64(false)
+	approve.ExpiryBlock = p.UnpackUint64(false)
+	return &approve, p.Err()
+}
+
+func (*ApproveAsset) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
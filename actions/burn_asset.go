@@ -64,7 +64,7 @@ func (b *BurnAsset) Execute(
 	if err := storage.SubBalance(ctx, mu, actor, b.Asset, b.Value); err != nil {
 		return false, BurnAssetComputeUnits, utils.ErrBytes(err), nil, nil
 	}
-	exists, symbol, decimals, metadata, supply, owner, warp, err := storage.GetAsset(ctx, mu, b.Asset)
+	exists, symbol, decimals, metadata, supply, maxSupply, owner, frozen, warp, err := storage.GetAsset(ctx, mu, b.Asset)
 	if err != nil {
 		return false, BurnAssetComputeUnits, utils.ErrBytes(err), nil, nil
 	}
@@ -75,7 +75,7 @@ func (b *BurnAsset) Execute(
 	if err != nil {
 		return false, BurnAssetComputeUnits, utils.ErrBytes(err), nil, nil
 	}
-	if err := storage.SetAsset(ctx, mu, b.Asset, symbol, decimals, metadata, newSupply, owner, warp); err != nil {
+	if err := storage.SetAsset(ctx, mu, b.Asset, symbol, decimals, metadata, newSupply, maxSupply, owner, frozen, warp); err != nil {
 		return false, BurnAssetComputeUnits, utils.ErrBytes(err), nil, nil
 	}
 	return true, BurnAssetComputeUnits, nil, nil, nil
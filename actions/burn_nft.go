@@ -0,0 +1,118 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	smath "github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/storage"
+)
+
+var _ chain.Action = (*BurnNFT)(nil)
+
+type BurnNFT struct {
+	// Collection is the [TxID] that created the collection.
+	Collection ids.ID `json:"collection"`
+
+	// ID of the NFT within its collection.
+	ID uint64 `json:"id"`
+}
+
+func (*BurnNFT) GetTypeID() uint8 {
+	return nconsts.BurnNFTID
+}
+
+func (b *BurnNFT) StateKeys(actor codec.Address, _ ids.ID) []string {
+	return []string{
+		string(storage.NFTCollectionKey(b.Collection)),
+		string(storage.NFTKey(b.Collection, b.ID)),
+		string(storage.NFTOwnerKey(actor, b.Collection, b.ID)),
+	}
+}
+
+func (*BurnNFT) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.NFTCollectionChunks, storage.NFTChunks, storage.NFTOwnerChunks}
+}
+
+func (*BurnNFT) OutputsWarpMessage() bool {
+	return false
+}
+
+func (b *BurnNFT) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	exists, owner, _, err := storage.GetNFT(ctx, mu, b.Collection, b.ID)
+	if err != nil {
+		return false, BurnNFTComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, BurnNFTComputeUnits, OutputNFTMissing, nil, nil
+	}
+	if owner != actor {
+		return false, BurnNFTComputeUnits, OutputWrongOwner, nil, nil
+	}
+	exists, symbol, metadata, supply, collectionOwner, err := storage.GetNFTCollection(ctx, mu, b.Collection)
+	if err != nil {
+		return false, BurnNFTComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, BurnNFTComputeUnits, OutputNFTCollectionMissing, nil, nil
+	}
+	newSupply, err := smath.Sub(supply, 1)
+	if err != nil {
+		return false, BurnNFTComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.SetNFTCollection(ctx, mu, b.Collection, symbol, metadata, newSupply, collectionOwner); err != nil {
+		return false, BurnNFTComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.DeleteNFT(ctx, mu, b.Collection, b.ID); err != nil {
+		return false, BurnNFTComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.RemoveNFTFromOwner(ctx, mu, actor, b.Collection, b.ID); err != nil {
+		return false, BurnNFTComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, BurnNFTComputeUnits, nil, nil, nil
+}
+
+func (*BurnNFT) MaxComputeUnits(chain.Rules) uint64 {
+	return BurnNFTComputeUnits
+}
+
+func (*BurnNFT) Size() int {
+	return hconsts.IDLen + hconsts.Uint64Len
+}
+
+func (b *BurnNFT) Marshal(p *codec.Packer) {
+	p.PackID(b.Collection)
+	p.PackUint64(b.ID)
+}
+
+func UnmarshalBurnNFT(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var burn BurnNFT
+	p.UnpackID(true, &burn.Collection)
+	burn.ID = p.UnpackUint64(false)
+	return &burn, p.Err()
+}
+
+func (*BurnNFT) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -30,17 +30,20 @@ func (*ClaimDelegationStakeRewards) GetTypeID() uint8 {
 	return nconsts.ClaimDelegationStakeRewards
 }
 
-func (c *ClaimDelegationStakeRewards) StateKeys(actor codec.Address, _ ids.ID) []string {
+func (c *ClaimDelegationStakeRewards) StateKeys(actor codec.Address, txID ids.ID) []string {
 	// TODO: How to better handle a case where the NodeID is invalid?
 	nodeID, _ := ids.ToNodeID(c.NodeID)
-	return []string{
+	return append([]string{
 		string(storage.BalanceKey(actor, ids.Empty)),
-		string(storage.DelegateUserStakeKey(actor, nodeID)),
-	}
+		string(storage.DelegateUserStakeKey(c.UserStakeAddress, nodeID)),
+		string(storage.RegisterValidatorStakeKey(nodeID)),
+		string(storage.ValidatorRecordKey(nodeID)),
+		string(storage.ValidatorUnbondingKey(nodeID)),
+	}, emissionStateKeys(txID)...)
 }
 
 func (*ClaimDelegationStakeRewards) StateKeysMaxChunks() []uint16 {
-	return []uint16{storage.BalanceChunks, storage.DelegateUserStakeChunks}
+	return append([]uint16{storage.BalanceChunks, storage.DelegateUserStakeChunks, storage.RegisterValidatorStakeChunks, storage.ValidatorRecordChunks, storage.ValidatorUnbondingChunks}, emissionStateKeysMaxChunks()...)
 }
 
 func (*ClaimDelegationStakeRewards) OutputsWarpMessage() bool {
@@ -49,11 +52,11 @@ func (*ClaimDelegationStakeRewards) OutputsWarpMessage() bool {
 
 func (c *ClaimDelegationStakeRewards) Execute(
 	ctx context.Context,
-	_ chain.Rules,
+	rules chain.Rules,
 	mu state.Mutable,
-	_ int64,
+	timestamp int64,
 	actor codec.Address,
-	_ ids.ID,
+	txID ids.ID,
 	_ bool,
 ) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
 	nodeID, err := ids.ToNodeID(c.NodeID)
@@ -61,7 +64,7 @@ func (c *ClaimDelegationStakeRewards) Execute(
 		return false, ClaimStakingRewardComputeUnits, OutputInvalidNodeID, nil, nil
 	}
 
-	exists, stakeStartBlock, _, _, rewardAddress, _, _ := storage.GetDelegateUserStake(ctx, mu, c.UserStakeAddress, nodeID)
+	exists, stakeStartBlock, stakeEndBlock, stakedAmount, rewardAddress, _, rewardWeight, rewardIndex, _, _ := storage.GetDelegateUserStake(ctx, mu, c.UserStakeAddress, nodeID)
 	if !exists {
 		return false, ClaimStakingRewardComputeUnits, OutputStakeMissing, nil, nil
 	}
@@ -69,21 +72,40 @@ func (c *ClaimDelegationStakeRewards) Execute(
 		return false, ClaimStakingRewardComputeUnits, OutputUnauthorized, nil, nil
 	}
 
-	// Get the emission instance
-	emissionInstance := emission.GetEmission()
+	e, err := loadEmission(ctx, rules, mu, timestamp, txID)
+	if err != nil {
+		return false, ClaimStakingRewardComputeUnits, utils.ErrBytes(err), nil, nil
+	}
 
 	// Check that lastBlockHeight is after stakeStartBlock
-	if emissionInstance.GetLastAcceptedBlockHeight() < stakeStartBlock {
+	if e.parentHeight < stakeStartBlock {
 		return false, ClaimStakingRewardComputeUnits, OutputStakeNotEnded, nil, nil
 	}
 
 	// Claim rewards in Emission Balancer
-	rewardAmount, err := emissionInstance.ClaimStakingRewards(nodeID, c.UserStakeAddress)
+	stake, err := getStake(ctx, mu, nodeID)
 	if err != nil {
 		return false, ClaimStakingRewardComputeUnits, utils.ErrBytes(err), nil, nil
 	}
+	record, exists, err := e.getValidator(nodeID, stake)
+	if err != nil {
+		return false, ClaimStakingRewardComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, ClaimStakingRewardComputeUnits, utils.ErrBytes(emission.ErrValidatorNotFound), nil, nil
+	}
+	rewardAmount, newRewardIndex, newRewardHeight := record.SettleDelegation(e.header, rewardWeight, rewardIndex, stakeStartBlock, e.epochTracker.EpochLength)
+	if err := e.setValidator(nodeID, record); err != nil {
+		return false, ClaimStakingRewardComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := e.store(); err != nil {
+		return false, ClaimStakingRewardComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.SetDelegateUserStake(ctx, mu, c.UserStakeAddress, nodeID, stakeStartBlock, stakeEndBlock, stakedAmount, rewardAddress, rewardWeight, newRewardIndex, newRewardHeight); err != nil {
+		return false, ClaimStakingRewardComputeUnits, utils.ErrBytes(err), nil, nil
+	}
 
-	if err := storage.AddBalance(ctx, mu, rewardAddress, ids.Empty, rewardAmount, true); err != nil {
+	if err := storage.AddNAI(ctx, mu, rewardAddress, rewardAmount); err != nil {
 		return false, ClaimStakingRewardComputeUnits, utils.ErrBytes(err), nil, nil
 	}
 
@@ -92,6 +114,10 @@ func (c *ClaimDelegationStakeRewards) Execute(
 	if err != nil {
 		return false, ClaimStakingRewardComputeUnits, utils.ErrBytes(err), nil, nil
 	}
+	output, err = e.output(output)
+	if err != nil {
+		return false, ClaimStakingRewardComputeUnits, utils.ErrBytes(err), nil, nil
+	}
 	return true, ClaimStakingRewardComputeUnits, output, nil, nil
 }
 
@@ -0,0 +1,106 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/storage"
+)
+
+var _ chain.Action = (*ClaimEmissionFees)(nil)
+
+// ClaimEmissionFees pays the fees accumulated in the emission account out to
+// the emission address.
+type ClaimEmissionFees struct{}
+
+func (*ClaimEmissionFees) GetTypeID() uint8 {
+	return nconsts.ClaimEmissionFeesID
+}
+
+func (*ClaimEmissionFees) StateKeys(actor codec.Address, txID ids.ID) []string {
+	return append([]string{
+		string(storage.BalanceKey(actor, ids.Empty)),
+	}, emissionStateKeys(txID)...)
+}
+
+func (*ClaimEmissionFees) StateKeysMaxChunks() []uint16 {
+	return append([]uint16{storage.BalanceChunks}, emissionStateKeysMaxChunks()...)
+}
+
+func (*ClaimEmissionFees) OutputsWarpMessage() bool {
+	return false
+}
+
+func (*ClaimEmissionFees) Execute(
+	ctx context.Context,
+	rules chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	txID ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	e, err := loadEmission(ctx, rules, mu, timestamp, txID)
+	if err != nil {
+		return false, ClaimEmissionFeesComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	// Only the emission address can claim the fees
+	if actor != e.header.EmissionAccount.Address {
+		return false, ClaimEmissionFeesComputeUnits, OutputUnauthorized, nil, nil
+	}
+
+	// Claim fees in Emission Balancer
+	feeAmount := e.header.ClaimEmissionFees()
+	if feeAmount == 0 {
+		return false, ClaimEmissionFeesComputeUnits, OutputNoFeesToClaim, nil, nil
+	}
+	if err := e.store(); err != nil {
+		return false, ClaimEmissionFeesComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	if err := storage.AddNAI(ctx, mu, actor, feeAmount); err != nil {
+		return false, ClaimEmissionFeesComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	sr := &ClaimRewardsResult{feeAmount}
+	output, err := sr.Marshal()
+	if err != nil {
+		return false, ClaimEmissionFeesComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	output, err = e.output(output)
+	if err != nil {
+		return false, ClaimEmissionFeesComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, ClaimEmissionFeesComputeUnits, output, nil, nil
+}
+
+func (*ClaimEmissionFees) MaxComputeUnits(chain.Rules) uint64 {
+	return ClaimEmissionFeesComputeUnits
+}
+
+func (*ClaimEmissionFees) Size() int {
+	return 0
+}
+
+func (*ClaimEmissionFees) Marshal(*codec.Packer) {}
+
+func UnmarshalClaimEmissionFees(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var claimFees ClaimEmissionFees
+	return &claimFees, p.Err()
+}
+
+func (*ClaimEmissionFees) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -29,17 +29,19 @@ func (*ClaimValidatorStakeRewards) GetTypeID() uint8 {
 	return nconsts.ClaimValidatorStakeRewardsID
 }
 
-func (c *ClaimValidatorStakeRewards) StateKeys(actor codec.Address, _ ids.ID) []string {
+func (c *ClaimValidatorStakeRewards) StateKeys(actor codec.Address, txID ids.ID) []string {
 	// TODO: How to better handle a case where the NodeID is invalid?
 	nodeID, _ := ids.ToNodeID(c.NodeID)
-	return []string{
+	return append([]string{
 		string(storage.BalanceKey(actor, ids.Empty)),
 		string(storage.RegisterValidatorStakeKey(nodeID)),
-	}
+		string(storage.ValidatorRecordKey(nodeID)),
+		string(storage.ValidatorUnbondingKey(nodeID)),
+	}, emissionStateKeys(txID)...)
 }
 
 func (*ClaimValidatorStakeRewards) StateKeysMaxChunks() []uint16 {
-	return []uint16{storage.BalanceChunks, storage.RegisterValidatorStakeChunks}
+	return append([]uint16{storage.BalanceChunks, storage.RegisterValidatorStakeChunks, storage.ValidatorRecordChunks, storage.ValidatorUnbondingChunks}, emissionStateKeysMaxChunks()...)
 }
 
 func (*ClaimValidatorStakeRewards) OutputsWarpMessage() bool {
@@ -48,11 +50,11 @@ func (*ClaimValidatorStakeRewards) OutputsWarpMessage() bool {
 
 func (c *ClaimValidatorStakeRewards) Execute(
 	ctx context.Context,
-	_ chain.Rules,
+	rules chain.Rules,
 	mu state.Mutable,
-	_ int64,
+	timestamp int64,
 	actor codec.Address,
-	_ ids.ID,
+	txID ids.ID,
 	_ bool,
 ) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
 	nodeID, err := ids.ToNodeID(c.NodeID)
@@ -61,7 +63,7 @@ func (c *ClaimValidatorStakeRewards) Execute(
 	}
 
 	// Check whether a validator is trying to claim its reward
-	exists, _, stakeEndBlock, _, _, rewardAddress, _, _ := storage.GetRegisterValidatorStake(ctx, mu, nodeID)
+	exists, _, stakeEndBlock, _, _, _, _, rewardAddress, _, _ := storage.GetRegisterValidatorStake(ctx, mu, nodeID)
 	if !exists {
 		return false, ClaimStakingRewardComputeUnits, OutputStakeMissing, nil, nil
 	}
@@ -69,21 +71,37 @@ func (c *ClaimValidatorStakeRewards) Execute(
 		return false, ClaimStakingRewardComputeUnits, OutputUnauthorized, nil, nil
 	}
 
-	// Get the emission instance
-	emissionInstance := emission.GetEmission()
+	e, err := loadEmission(ctx, rules, mu, timestamp, txID)
+	if err != nil {
+		return false, ClaimStakingRewardComputeUnits, utils.ErrBytes(err), nil, nil
+	}
 
 	// Check that lastBlockHeight is after stakeEndBlock
-	if emissionInstance.GetLastAcceptedBlockHeight() < stakeEndBlock {
+	if e.parentHeight < stakeEndBlock {
 		return false, ClaimStakingRewardComputeUnits, OutputStakeNotEnded, nil, nil
 	}
 
 	// Claim rewards in Emission Balancer
-	rewardAmount, err := emissionInstance.ClaimStakingRewards(nodeID, codec.EmptyAddress)
+	stake, err := getStake(ctx, mu, nodeID)
 	if err != nil {
 		return false, ClaimStakingRewardComputeUnits, utils.ErrBytes(err), nil, nil
 	}
+	record, exists, err := e.getValidator(nodeID, stake)
+	if err != nil {
+		return false, ClaimStakingRewardComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, ClaimStakingRewardComputeUnits, utils.ErrBytes(emission.ErrValidatorNotFound), nil, nil
+	}
+	rewardAmount := record.ClaimRewards(e.header)
+	if err := e.setValidator(nodeID, record); err != nil {
+		return false, ClaimStakingRewardComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := e.store(); err != nil {
+		return false, ClaimStakingRewardComputeUnits, utils.ErrBytes(err), nil, nil
+	}
 
-	if err := storage.AddBalance(ctx, mu, rewardAddress, ids.Empty, rewardAmount, true); err != nil {
+	if err := storage.AddNAI(ctx, mu, rewardAddress, rewardAmount); err != nil {
 		return false, ClaimStakingRewardComputeUnits, utils.ErrBytes(err), nil, nil
 	}
 
@@ -92,6 +110,10 @@ func (c *ClaimValidatorStakeRewards) Execute(
 	if err != nil {
 		return false, ClaimStakingRewardComputeUnits, utils.ErrBytes(err), nil, nil
 	}
+	output, err = e.output(output)
+	if err != nil {
+		return false, ClaimStakingRewardComputeUnits, utils.ErrBytes(err), nil, nil
+	}
 	return true, ClaimStakingRewardComputeUnits, output, nil, nil
 }
 
@@ -6,15 +6,35 @@ package actions
 const (
 	TransferComputeUnits = 1
 
+	MultiTransferComputeUnitsPerEntry = 1
+
 	CreateAssetComputeUnits = 5
 	ExportAssetComputeUnits = 5
 	ImportAssetComputeUnits = 5
 	MintAssetComputeUnits   = 5
 	BurnAssetComputeUnits   = 1
+	UpdateAssetComputeUnits = 5
+
+	CreateNFTCollectionComputeUnits = 5
+	MintNFTComputeUnits             = 5
+	TransferNFTComputeUnits         = 2
+	BurnNFTComputeUnits             = 2
+
+	ApproveAssetComputeUnits = 1
+	TransferFromComputeUnits = 1
 
 	RegisterValidatorStakeComputeUnits = 5
 	WithdrawValidatorStakeComputeUnits = 1
 	DelegateUserStakeComputeUnits      = 5
 	UndelegateUserStakeComputeUnits    = 1
 	ClaimStakingRewardComputeUnits     = 2
+	ClaimEmissionFeesComputeUnits      = 2
+	ValidatorHeartbeatComputeUnits     = 1
+	ReleaseUnbondedStakeComputeUnits   = 1
+	RedelegateUserStakeComputeUnits    = 5
+	IncreaseDelegationComputeUnits     = 5
+	DecreaseDelegationComputeUnits     = 5
+	IncreaseValidatorStakeComputeUnits = 5
+	DecreaseValidatorStakeComputeUnits = 5
+	UpdateValidatorStakeComputeUnits   = 1
 )
@@ -29,6 +29,11 @@ type CreateAsset struct {
 	Symbol   []byte `json:"symbol"`
 	Decimals uint8  `json:"decimals"`
 	Metadata []byte `json:"metadata"`
+
+	// MaxSupply caps the circulating supply of the asset. Burning reduces
+	// the supply, so it makes room to mint again. It is unlimited if set to
+	// 0.
+	MaxSupply uint64 `json:"maxSupply"`
 }
 
 func (*CreateAsset) GetTypeID() uint8 {
@@ -75,7 +80,7 @@ func (c *CreateAsset) Execute(
 	}
 	// It should only be possible to overwrite an existing asset if there is
 	// a hash collision.
-	if err := storage.SetAsset(ctx, mu, txID, c.Symbol, c.Decimals, c.Metadata, 0, actor, false); err != nil {
+	if err := storage.SetAsset(ctx, mu, txID, c.Symbol, c.Decimals, c.Metadata, 0, c.MaxSupply, actor, false, false); err != nil {
 		return false, CreateAssetComputeUnits, utils.ErrBytes(err), nil, nil
 	}
 	return true, CreateAssetComputeUnits, nil, nil, nil
@@ -87,13 +92,14 @@ func (*CreateAsset) MaxComputeUnits(chain.Rules) uint64 {
 
 func (c *CreateAsset) Size() int {
 	// TODO: add small bytes (smaller int prefix)
-	return codec.BytesLen(c.Symbol) + hconsts.Uint8Len + codec.BytesLen(c.Metadata)
+	return codec.BytesLen(c.Symbol) + hconsts.Uint8Len + codec.BytesLen(c.Metadata) + hconsts.Uint64Len
 }
 
 func (c *CreateAsset) Marshal(p *codec.Packer) {
 	p.PackBytes(c.Symbol)
 	p.PackByte(c.Decimals)
 	p.PackBytes(c.Metadata)
+	p.PackUint64(c.MaxSupply)
 }
 
 func UnmarshalCreateAsset(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
@@ -101,6 +107,7 @@ func UnmarshalCreateAsset(p *codec.Packer, _ *warp.Message) (chain.Action, error
 	p.UnpackBytes(MaxSymbolSize, true, &create.Symbol)
 	create.Decimals = p.UnpackByte()
 	p.UnpackBytes(MaxMetadataSize, true, &create.Metadata)
+	create.MaxSupply = p.UnpackUint64(false)
 	return &create, p.Err()
 }
 
@@ -0,0 +1,98 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/storage"
+)
+
+var _ chain.Action = (*CreateNFTCollection)(nil)
+
+// CreateNFTCollection creates a collection of NFTs that only its creator can
+// mint into. The collection is identified by the [TxID] that created it.
+type CreateNFTCollection struct {
+	Symbol   []byte `json:"symbol"`
+	Metadata []byte `json:"metadata"`
+}
+
+func (*CreateNFTCollection) GetTypeID() uint8 {
+	return nconsts.CreateNFTCollectionID
+}
+
+func (*CreateNFTCollection) StateKeys(_ codec.Address, txID ids.ID) []string {
+	return []string{
+		string(storage.NFTCollectionKey(txID)),
+	}
+}
+
+func (*CreateNFTCollection) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.NFTCollectionChunks}
+}
+
+func (*CreateNFTCollection) OutputsWarpMessage() bool {
+	return false
+}
+
+func (c *CreateNFTCollection) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	txID ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	if len(c.Symbol) == 0 {
+		return false, CreateNFTCollectionComputeUnits, OutputSymbolEmpty, nil, nil
+	}
+	if len(c.Symbol) > MaxSymbolSize {
+		return false, CreateNFTCollectionComputeUnits, OutputSymbolTooLarge, nil, nil
+	}
+	if len(c.Metadata) == 0 {
+		return false, CreateNFTCollectionComputeUnits, OutputMetadataEmpty, nil, nil
+	}
+	if len(c.Metadata) > MaxMetadataSize {
+		return false, CreateNFTCollectionComputeUnits, OutputMetadataTooLarge, nil, nil
+	}
+	if err := storage.SetNFTCollection(ctx, mu, txID, c.Symbol, c.Metadata, 0, actor); err != nil {
+		return false, CreateNFTCollectionComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, CreateNFTCollectionComputeUnits, nil, nil, nil
+}
+
+func (*CreateNFTCollection) MaxComputeUnits(chain.Rules) uint64 {
+	return CreateNFTCollectionComputeUnits
+}
+
+func (c *CreateNFTCollection) Size() int {
+	return codec.BytesLen(c.Symbol) + codec.BytesLen(c.Metadata)
+}
+
+func (c *CreateNFTCollection) Marshal(p *codec.Packer) {
+	p.PackBytes(c.Symbol)
+	p.PackBytes(c.Metadata)
+}
+
+func UnmarshalCreateNFTCollection(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var create CreateNFTCollection
+	p.UnpackBytes(MaxSymbolSize, true, &create.Symbol)
+	p.UnpackBytes(MaxMetadataSize, true, &create.Metadata)
+	return &create, p.Err()
+}
+
+func (*CreateNFTCollection) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
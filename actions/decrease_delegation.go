@@ -0,0 +1,176 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/emission"
+	"github.com/nuklai/nuklaivm/storage"
+)
+
+var _ chain.Action = (*DecreaseDelegation)(nil)
+
+// DecreaseDelegation takes part of an existing delegation out through
+// unbonding. The rewards accrued so far are paid out to RewardAddress first.
+type DecreaseDelegation struct {
+	NodeID        []byte        `json:"nodeID"`        // Node ID of the validator where NAI is staked
+	Amount        uint64        `json:"amount"`        // Amount of NAI to take off the stake
+	RewardAddress codec.Address `json:"rewardAddress"` // Address to receive rewards
+}
+
+func (*DecreaseDelegation) GetTypeID() uint8 {
+	return nconsts.DecreaseDelegationID
+}
+
+func (d *DecreaseDelegation) StateKeys(actor codec.Address, txID ids.ID) []string {
+	// TODO: How to better handle a case where the NodeID is invalid?
+	nodeID, _ := ids.ToNodeID(d.NodeID)
+	return append([]string{
+		string(storage.BalanceKey(actor, ids.Empty)),
+		string(storage.BalanceKey(d.RewardAddress, ids.Empty)),
+		string(storage.DelegateUserStakeKey(actor, nodeID)),
+		string(storage.UnbondingKey(actor)),
+		string(storage.RegisterValidatorStakeKey(nodeID)),
+	}, append(validatorStateKeys(nodeID), emissionStateKeys(txID)...)...)
+}
+
+func (*DecreaseDelegation) StateKeysMaxChunks() []uint16 {
+	return append([]uint16{storage.BalanceChunks, storage.BalanceChunks, storage.DelegateUserStakeChunks, storage.UnbondingChunks, storage.RegisterValidatorStakeChunks}, append(validatorStateKeysMaxChunks(), emissionStateKeysMaxChunks()...)...)
+}
+
+func (*DecreaseDelegation) OutputsWarpMessage() bool {
+	return false
+}
+
+func (d *DecreaseDelegation) Execute(
+	ctx context.Context,
+	rules chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	txID ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	nodeID, err := ids.ToNodeID(d.NodeID)
+	if err != nil {
+		return false, DecreaseDelegationComputeUnits, OutputInvalidNodeID, nil, nil
+	}
+	if d.Amount == 0 {
+		return false, DecreaseDelegationComputeUnits, OutputValueZero, nil, nil
+	}
+
+	exists, stakeStartBlock, stakeEndBlock, stakedAmount, rewardAddress, ownerAddress, rewardWeight, rewardIndex, _, _ := storage.GetDelegateUserStake(ctx, mu, actor, nodeID)
+	if !exists {
+		return false, DecreaseDelegationComputeUnits, OutputStakeMissing, nil, nil
+	}
+	if ownerAddress != actor {
+		return false, DecreaseDelegationComputeUnits, OutputUnauthorized, nil, nil
+	}
+
+	// The remaining stake must still meet the minimum, otherwise the whole
+	// stake should be undelegated instead
+	stakingConfig := emission.GetStakingConfig(rules)
+	if d.Amount > stakedAmount || stakedAmount-d.Amount < stakingConfig.MinDelegatorStake {
+		return false, DecreaseDelegationComputeUnits, OutputDelegateStakedAmountInvalid, nil, nil
+	}
+
+	e, err := loadEmission(ctx, rules, mu, timestamp, txID)
+	if err != nil {
+		return false, DecreaseDelegationComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	lastBlockHeight := e.parentHeight
+
+	stake, err := getStake(ctx, mu, nodeID)
+	if err != nil {
+		return false, DecreaseDelegationComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	record, exists, err := e.getValidator(nodeID, stake)
+	if err != nil {
+		return false, DecreaseDelegationComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, DecreaseDelegationComputeUnits, utils.ErrBytes(emission.ErrValidatorNotFound), nil, nil
+	}
+	// Delegations to a stake the validator withdrew can only be undelegated
+	if record.IsWithdrawn(stakeStartBlock) {
+		return false, DecreaseDelegationComputeUnits, OutputValidatorStakeEnded, nil, nil
+	}
+
+	// Settle the rewards and decrease the stake in Emission Balancer
+	rewardAmount, newRewardIndex, newRewardHeight := record.SettleDelegation(e.header, rewardWeight, rewardIndex, stakeStartBlock, e.epochTracker.EpochLength)
+	newRewardWeight := stakingConfig.StakeWeight(stakedAmount-d.Amount, stakeStartBlock, stakeEndBlock, stakingConfig.MaxDelegatorStakeDuration)
+	record.ChangeDelegation(stakedAmount, rewardWeight, stakedAmount-d.Amount, newRewardWeight)
+	if err := e.updateRegistration(nodeID, record, stake); err != nil {
+		return false, DecreaseDelegationComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := e.setValidator(nodeID, record); err != nil {
+		return false, DecreaseDelegationComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := e.store(); err != nil {
+		return false, DecreaseDelegationComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	if err := storage.AddNAI(ctx, mu, d.RewardAddress, rewardAmount); err != nil {
+		return false, DecreaseDelegationComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	stakedAmount -= d.Amount
+	if err := storage.SetDelegateUserStake(ctx, mu, actor, nodeID, stakeStartBlock, stakeEndBlock, stakedAmount, rewardAddress, newRewardWeight, newRewardIndex, newRewardHeight); err != nil {
+		return false, DecreaseDelegationComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := unbondStake(ctx, rules, mu, actor, nodeID, d.Amount, lastBlockHeight+1); err != nil {
+		if errors.Is(err, storage.ErrUnbondingQueueFull) {
+			return false, DecreaseDelegationComputeUnits, OutputUnbondingQueueFull, nil, nil
+		}
+		return false, DecreaseDelegationComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	sr := &ChangeStakeResult{stakedAmount, rewardAmount}
+	output, err := sr.Marshal()
+	if err != nil {
+		return false, DecreaseDelegationComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	output, err = e.output(output)
+	if err != nil {
+		return false, DecreaseDelegationComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, DecreaseDelegationComputeUnits, output, nil, nil
+}
+
+func (*DecreaseDelegation) MaxComputeUnits(chain.Rules) uint64 {
+	return DecreaseDelegationComputeUnits
+}
+
+func (*DecreaseDelegation) Size() int {
+	return hconsts.NodeIDLen + hconsts.Uint64Len + codec.AddressLen
+}
+
+func (d *DecreaseDelegation) Marshal(p *codec.Packer) {
+	p.PackBytes(d.NodeID)
+	p.PackUint64(d.Amount)
+	p.PackAddress(d.RewardAddress)
+}
+
+func UnmarshalDecreaseDelegation(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var decrease DecreaseDelegation
+	p.UnpackBytes(hconsts.NodeIDLen, true, &decrease.NodeID)
+	decrease.Amount = p.UnpackUint64(true)
+	p.UnpackAddress(&decrease.RewardAddress)
+	return &decrease, p.Err()
+}
+
+func (*DecreaseDelegation) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
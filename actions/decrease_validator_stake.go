@@ -0,0 +1,174 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/emission"
+	"github.com/nuklai/nuklaivm/storage"
+)
+
+var _ chain.Action = (*DecreaseValidatorStake)(nil)
+
+// DecreaseValidatorStake takes part of the stake of a registered validator out
+// through unbonding. The rewards accrued so far are paid out to RewardAddress
+// first.
+type DecreaseValidatorStake struct {
+	NodeID        []byte        `json:"nodeID"`        // Node ID of the validator
+	Amount        uint64        `json:"amount"`        // Amount of NAI to take off the stake
+	RewardAddress codec.Address `json:"rewardAddress"` // Address to receive rewards
+}
+
+func (*DecreaseValidatorStake) GetTypeID() uint8 {
+	return nconsts.DecreaseValidatorStakeID
+}
+
+func (d *DecreaseValidatorStake) StateKeys(actor codec.Address, txID ids.ID) []string {
+	// TODO: How to better handle a case where the NodeID is invalid?
+	nodeID, _ := ids.ToNodeID(d.NodeID)
+	return append([]string{
+		string(storage.BalanceKey(actor, ids.Empty)),
+		string(storage.BalanceKey(d.RewardAddress, ids.Empty)),
+		string(storage.RegisterValidatorStakeKey(nodeID)),
+	}, append(validatorStateKeys(nodeID), emissionStateKeys(txID)...)...)
+}
+
+func (*DecreaseValidatorStake) StateKeysMaxChunks() []uint16 {
+	return append([]uint16{storage.BalanceChunks, storage.BalanceChunks, storage.RegisterValidatorStakeChunks}, append(validatorStateKeysMaxChunks(), emissionStateKeysMaxChunks()...)...)
+}
+
+func (*DecreaseValidatorStake) OutputsWarpMessage() bool {
+	return false
+}
+
+func (d *DecreaseValidatorStake) Execute(
+	ctx context.Context,
+	rules chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	txID ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	nodeID, err := ids.ToNodeID(d.NodeID)
+	if err != nil {
+		return false, DecreaseValidatorStakeComputeUnits, OutputInvalidNodeID, nil, nil
+	}
+	if d.Amount == 0 {
+		return false, DecreaseValidatorStakeComputeUnits, OutputValueZero, nil, nil
+	}
+
+	exists, stakeStartBlock, stakeEndBlock, _, delegationFeeRate, pendingDelegationFeeRate, feeRateEffectiveBlock, rewardAddress, ownerAddress, _ := storage.GetRegisterValidatorStake(ctx, mu, nodeID)
+	if !exists {
+		return false, DecreaseValidatorStakeComputeUnits, OutputValidatorNotYetRegistered, nil, nil
+	}
+	if ownerAddress != actor {
+		return false, DecreaseValidatorStakeComputeUnits, OutputUnauthorized, nil, nil
+	}
+
+	e, err := loadEmission(ctx, rules, mu, timestamp, txID)
+	if err != nil {
+		return false, DecreaseValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	lastBlockHeight := e.parentHeight
+
+	// The remaining stake must still meet the minimum, otherwise the whole stake should be withdrawn instead
+	stakingConfig := emission.GetStakingConfig(rules)
+	stake, err := getStake(ctx, mu, nodeID)
+	if err != nil {
+		return false, DecreaseValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	record, found, err := e.getValidator(nodeID, stake)
+	if err != nil {
+		return false, DecreaseValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !found {
+		return false, DecreaseValidatorStakeComputeUnits, utils.ErrBytes(emission.ErrValidatorNotFound), nil, nil
+	}
+	validatorStakedAmount := stake.StakedAmount
+	if d.Amount > validatorStakedAmount || validatorStakedAmount-d.Amount < stakingConfig.MinValidatorStake {
+		return false, DecreaseValidatorStakeComputeUnits, OutputValidatorStakedAmountInvalid, nil, nil
+	}
+
+	// The stake that is delegated to the validator must still fit under the
+	// delegation limits of the remaining stake
+	if stakingConfig.DelegationCapacity(validatorStakedAmount-d.Amount, 0) < record.DelegatedAmount {
+		return false, DecreaseValidatorStakeComputeUnits, OutputDelegationCapacityExceeded, nil, nil
+	}
+
+	// Settle the rewards and decrease the stake in Emission Balancer
+	rewardAmount := record.ClaimRewards(e.header)
+	stake.StakedAmount -= d.Amount
+	if err := e.updateRegistration(nodeID, record, stake); err != nil {
+		return false, DecreaseValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := e.setValidator(nodeID, record); err != nil {
+		return false, DecreaseValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := e.store(); err != nil {
+		return false, DecreaseValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	if err := storage.AddNAI(ctx, mu, d.RewardAddress, rewardAmount); err != nil {
+		return false, DecreaseValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.SetRegisterValidatorStake(ctx, mu, nodeID, stakeStartBlock, stakeEndBlock, stake.StakedAmount, delegationFeeRate, pendingDelegationFeeRate, feeRateEffectiveBlock, rewardAddress, ownerAddress); err != nil {
+		return false, DecreaseValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := unbondValidatorStake(ctx, rules, mu, actor, nodeID, d.Amount, lastBlockHeight+1); err != nil {
+		if errors.Is(err, storage.ErrUnbondingQueueFull) {
+			return false, DecreaseValidatorStakeComputeUnits, OutputUnbondingQueueFull, nil, nil
+		}
+		return false, DecreaseValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	sr := &ChangeStakeResult{validatorStakedAmount - d.Amount, rewardAmount}
+	output, err := sr.Marshal()
+	if err != nil {
+		return false, DecreaseValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	output, err = e.output(output)
+	if err != nil {
+		return false, DecreaseValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, DecreaseValidatorStakeComputeUnits, output, nil, nil
+}
+
+func (*DecreaseValidatorStake) MaxComputeUnits(chain.Rules) uint64 {
+	return DecreaseValidatorStakeComputeUnits
+}
+
+func (*DecreaseValidatorStake) Size() int {
+	return hconsts.NodeIDLen + hconsts.Uint64Len + codec.AddressLen
+}
+
+func (d *DecreaseValidatorStake) Marshal(p *codec.Packer) {
+	p.PackBytes(d.NodeID)
+	p.PackUint64(d.Amount)
+	p.PackAddress(d.RewardAddress)
+}
+
+func UnmarshalDecreaseValidatorStake(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var decrease DecreaseValidatorStake
+	p.UnpackBytes(hconsts.NodeIDLen, true, &decrease.NodeID)
+	decrease.Amount = p.UnpackUint64(true)
+	p.UnpackAddress(&decrease.RewardAddress)
+	return &decrease, p.Err()
+}
+
+func (*DecreaseValidatorStake) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
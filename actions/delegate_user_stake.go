@@ -33,17 +33,17 @@ func (*DelegateUserStake) GetTypeID() uint8 {
 	return nconsts.DelegateUserStakeID
 }
 
-func (s *DelegateUserStake) StateKeys(actor codec.Address, _ ids.ID) []string {
+func (s *DelegateUserStake) StateKeys(actor codec.Address, txID ids.ID) []string {
 	nodeID, _ := ids.ToNodeID(s.NodeID)
-	return []string{
+	return append([]string{
 		string(storage.BalanceKey(actor, ids.Empty)),
 		string(storage.DelegateUserStakeKey(actor, nodeID)),
 		string(storage.RegisterValidatorStakeKey(nodeID)),
-	}
+	}, append(validatorStateKeys(nodeID), emissionStateKeys(txID)...)...)
 }
 
 func (*DelegateUserStake) StateKeysMaxChunks() []uint16 {
-	return []uint16{storage.BalanceChunks, storage.DelegateUserStakeChunks, storage.RegisterValidatorStakeChunks}
+	return append([]uint16{storage.BalanceChunks, storage.DelegateUserStakeChunks, storage.RegisterValidatorStakeChunks}, append(validatorStateKeysMaxChunks(), emissionStateKeysMaxChunks()...)...)
 }
 
 func (*DelegateUserStake) OutputsWarpMessage() bool {
@@ -52,11 +52,11 @@ func (*DelegateUserStake) OutputsWarpMessage() bool {
 
 func (s *DelegateUserStake) Execute(
 	ctx context.Context,
-	_ chain.Rules,
+	r chain.Rules,
 	mu state.Mutable,
-	_ int64,
+	timestamp int64,
 	actor codec.Address,
-	_ ids.ID,
+	txID ids.ID,
 	_ bool,
 ) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
 	nodeID, err := ids.ToNodeID(s.NodeID)
@@ -65,18 +65,18 @@ func (s *DelegateUserStake) Execute(
 	}
 
 	// Check if the validator the user is trying to delegate to is registered for staking
-	exists, _, _, _, _, _, _, _ := storage.GetRegisterValidatorStake(ctx, mu, nodeID)
+	exists, _, validatorStakeEndBlock, _, _, _, _, _, _, _ := storage.GetRegisterValidatorStake(ctx, mu, nodeID)
 	if !exists {
 		return false, RegisterValidatorStakeComputeUnits, OutputValidatorNotYetRegistered, nil, nil
 	}
 
 	// Check if the user has already delegated to this validator node before
-	exists, _, _, _, _, _, _ = storage.GetDelegateUserStake(ctx, mu, actor, nodeID)
+	exists, _, _, _, _, _, _, _, _, _ = storage.GetDelegateUserStake(ctx, mu, actor, nodeID)
 	if exists {
 		return false, DelegateUserStakeComputeUnits, OutputUserAlreadyStaked, nil, nil
 	}
 
-	stakingConfig := emission.GetStakingConfig()
+	stakingConfig := emission.GetStakingConfig(r)
 
 	// Check if the staked amount is a valid amount
 	if s.StakedAmount < stakingConfig.MinDelegatorStake {
@@ -87,28 +87,72 @@ func (s *DelegateUserStake) Execute(
 	if s.StakeEndBlock <= s.StakeStartBlock {
 		return false, DelegateUserStakeComputeUnits, OutputInvalidStakeEndBlock, nil, nil
 	}
+	// Check that the delegation does not outlive the validator stake
+	if s.StakeEndBlock > validatorStakeEndBlock {
+		return false, DelegateUserStakeComputeUnits, OutputDelegationOutlivesValidator, nil, nil
+	}
+	stakeDuration := s.StakeEndBlock - s.StakeStartBlock
+	if stakeDuration < stakingConfig.MinDelegatorStakeDuration || stakeDuration > stakingConfig.MaxDelegatorStakeDuration {
+		return false, DelegateUserStakeComputeUnits, OutputInvalidStakeDuration, nil, nil
+	}
+	// Check that the lockup period is covered by the lockup tiers
+	if _, ok := stakingConfig.LockupMultiplier(stakeDuration, stakingConfig.MaxDelegatorStakeDuration); !ok {
+		return false, DelegateUserStakeComputeUnits, OutputLockupPeriodInvalid, nil, nil
+	}
 
-	// Get the emission instance
-	emissionInstance := emission.GetEmission()
+	e, err := loadEmission(ctx, r, mu, timestamp, txID)
+	if err != nil {
+		return false, DelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
 
-	// Check if stakeStartBlock is smaller than the current block height
-	if s.StakeStartBlock < emissionInstance.GetLastAcceptedBlockHeight() {
+	// Check if stakeStartBlock is smaller than the current block height, or
+	// after the next epoch boundary: the weight of the stake is added to the
+	// validator right away, so it must be staked at every boundary it earns at
+	if s.StakeStartBlock < e.parentHeight || s.StakeStartBlock > e.header.NextBoundary(e.epochTracker.EpochLength) {
 		return false, DelegateUserStakeComputeUnits, OutputInvalidStakeStartBlock, nil, nil
 	}
 
-	// Delegate in Emission Balancer
-	err = emissionInstance.DelegateUserStake(nodeID, actor, s.StakeStartBlock, s.StakeEndBlock, s.StakedAmount)
+	stake, err := getStake(ctx, mu, nodeID)
+	if err != nil {
+		return false, DelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	record, exists, err := e.getValidator(nodeID, stake)
 	if err != nil {
 		return false, DelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
 	}
+	if !exists {
+		return false, DelegateUserStakeComputeUnits, utils.ErrBytes(emission.ErrValidatorNotFound), nil, nil
+	}
+
+	// Check that the validator can take the stake
+	if s.StakedAmount > record.DelegationCapacity(stake, stakingConfig) {
+		return false, DelegateUserStakeComputeUnits, OutputDelegationCapacityExceeded, nil, nil
+	}
+
+	// Delegate in Emission Balancer
+	rewardWeight := stakingConfig.StakeWeight(s.StakedAmount, s.StakeStartBlock, s.StakeEndBlock, stakingConfig.MaxDelegatorStakeDuration)
+	rewardIndex, rewardHeight := record.Delegate(s.StakedAmount, rewardWeight, e.epochTracker.EpochLength)
+	if err := e.updateRegistration(nodeID, record, stake); err != nil {
+		return false, DelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := e.setValidator(nodeID, record); err != nil {
+		return false, DelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := e.store(); err != nil {
+		return false, DelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
 
 	if err := storage.SubBalance(ctx, mu, actor, ids.Empty, s.StakedAmount); err != nil {
 		return false, DelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
 	}
-	if err := storage.SetDelegateUserStake(ctx, mu, actor, nodeID, s.StakeStartBlock, s.StakeEndBlock, s.StakedAmount, s.RewardAddress); err != nil {
+	if err := storage.SetDelegateUserStake(ctx, mu, actor, nodeID, s.StakeStartBlock, s.StakeEndBlock, s.StakedAmount, s.RewardAddress, rewardWeight, rewardIndex, rewardHeight); err != nil {
+		return false, DelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	output, err := e.output(nil)
+	if err != nil {
 		return false, DelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
 	}
-	return true, DelegateUserStakeComputeUnits, nil, nil, nil
+	return true, DelegateUserStakeComputeUnits, output, nil, nil
 }
 
 func (*DelegateUserStake) MaxComputeUnits(chain.Rules) uint64 {
@@ -0,0 +1,314 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+
+	"github.com/nuklai/nuklaivm/emission"
+	"github.com/nuklai/nuklaivm/storage"
+)
+
+// Actions that change the emission balancer declare the keys of its header,
+// of the height of the parent block, of the NAI asset and of one fee shard,
+// picked by the transaction ID, so that they can bring the header up to their
+// own block. The transaction that processes an epoch boundary sweeps the fees
+// collected in its shard into the header. Actions that change a validator
+// also declare the keys of its record, of its unbonding queue and of the
+// active set.
+
+func emissionStateKeys(txID ids.ID) []string {
+	return []string{
+		string(storage.EmissionKey()),
+		heightStateKey(),
+		string(storage.AssetKey(ids.Empty)),
+		string(storage.FeeShardKey(emissionFeeShard(txID))),
+	}
+}
+
+func emissionStateKeysMaxChunks() []uint16 {
+	return []uint16{storage.EmissionChunks, chain.HeightKeyChunks, storage.AssetChunks, storage.FeeShardChunks}
+}
+
+func validatorStateKeys(nodeID ids.NodeID) []string {
+	return []string{
+		string(storage.ValidatorRecordKey(nodeID)),
+		string(storage.ValidatorUnbondingKey(nodeID)),
+		string(storage.ActiveSetKey()),
+	}
+}
+
+func validatorStateKeysMaxChunks() []uint16 {
+	return []uint16{storage.ValidatorRecordChunks, storage.ValidatorUnbondingChunks, storage.ActiveSetChunks}
+}
+
+// emissionFeeShard returns the fee shard swept by the transaction with
+// [txID] when it processes an epoch boundary.
+func emissionFeeShard(txID ids.ID) uint8 {
+	return txID[0] % storage.NumFeeShards
+}
+
+// emissionState is the emission balancer as seen by an action: its header,
+// processed up to the block being executed, the active set once a
+// registration changes, and what was distributed and slashed along the way.
+type emissionState struct {
+	ctx           context.Context
+	mu            state.Mutable
+	header        *emission.Header
+	headerBytes   []byte
+	activeSet     *emission.ActiveSet
+	ledger        *emission.Ledger
+	stakingConfig emission.StakingConfig
+	epochTracker  emission.EpochTracker
+	parentHeight  uint64
+}
+
+// loadEmission reads the header of the emission balancer and processes it up
+// to the block being executed. If it processes an epoch boundary, the fees
+// collected in the fee shard of [txID] are swept into it.
+func loadEmission(
+	ctx context.Context,
+	rules chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	txID ids.ID,
+) (*emissionState, error) {
+	parentHeight, err := getParentHeight(ctx, mu)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := storage.GetEmission(ctx, mu)
+	if err != nil {
+		return nil, err
+	}
+	header, err := emission.UnmarshalHeader(v)
+	if err != nil {
+		return nil, err
+	}
+	epochTracker := emission.GetEpochTracker(rules)
+	if header.ProcessesBoundary(parentHeight+1, epochTracker) {
+		_, _, _, _, supply, _, _, _, _, err := storage.GetAsset(ctx, mu, ids.Empty)
+		if err != nil {
+			return nil, err
+		}
+		header.Process(parentHeight+1, timestamp, supply, epochTracker)
+
+		fees, err := storage.SweepFeeShard(ctx, mu, emissionFeeShard(txID))
+		if err != nil {
+			return nil, err
+		}
+		header.CollectFees(fees, emission.GetFeeSplit(rules))
+	} else {
+		header.Process(parentHeight+1, timestamp, 0, epochTracker)
+	}
+	return &emissionState{
+		ctx:           ctx,
+		mu:            mu,
+		header:        header,
+		headerBytes:   v,
+		ledger:        &emission.Ledger{},
+		stakingConfig: emission.GetStakingConfig(rules),
+		epochTracker:  epochTracker,
+		parentHeight:  parentHeight,
+	}, nil
+}
+
+// loadActiveSet reads the active set the first time a registration changes.
+// It is an [emission.ActiveSetLoader].
+func (e *emissionState) loadActiveSet() (*emission.ActiveSet, error) {
+	if e.activeSet != nil {
+		return e.activeSet, nil
+	}
+	exists, v, err := storage.GetActiveSet(e.ctx, e.mu)
+	if err != nil {
+		return nil, err
+	}
+	e.activeSet = &emission.ActiveSet{}
+	if exists {
+		if e.activeSet, err = emission.UnmarshalActiveSet(v); err != nil {
+			return nil, err
+		}
+	}
+	return e.activeSet, nil
+}
+
+// getValidator reads the record of the validator with [nodeID], if there is
+// one, and syncs it with the epoch boundaries processed since. [stake] is the
+// stake of the validator, nil if it was withdrawn. Any stake slashed for low
+// uptime along the way is taken off [stake], its stake record, its unbonding
+// queue and the supply of NAI right away.
+func (e *emissionState) getValidator(nodeID ids.NodeID, stake *emission.Stake) (*emission.ValidatorRecord, bool, error) {
+	exists, v, err := storage.GetValidatorRecord(e.ctx, e.mu, nodeID)
+	if err != nil || !exists {
+		return nil, false, err
+	}
+	record, err := emission.UnmarshalValidatorRecord(v)
+	if err != nil {
+		return nil, false, err
+	}
+	slashes := len(e.ledger.Slashes)
+	record.Sync(nodeID, stake, e.header, e.stakingConfig, e.epochTracker, e.ledger)
+	if slashedAmount := record.SettleSlash(stake, e.header); slashedAmount > 0 {
+		if err := e.slashStake(nodeID, stake, slashedAmount); err != nil {
+			return nil, false, err
+		}
+	}
+	if err := e.slashUnbonding(nodeID, e.ledger.Slashes[slashes:]); err != nil {
+		return nil, false, err
+	}
+	return record, true, nil
+}
+
+// slashStake writes the [stake] of the validator with [nodeID] back to its
+// stake record after [slashedAmount] was taken off it, which leaves the supply
+// of NAI held in state.
+func (e *emissionState) slashStake(nodeID ids.NodeID, stake *emission.Stake, slashedAmount uint64) error {
+	_, stakeStartBlock, stakeEndBlock, _, delegationFeeRate, pendingDelegationFeeRate, feeRateEffectiveBlock, rewardAddress, ownerAddress, err := storage.GetRegisterValidatorStake(e.ctx, e.mu, nodeID)
+	if err != nil {
+		return err
+	}
+	if err := storage.SetRegisterValidatorStake(e.ctx, e.mu, nodeID, stakeStartBlock, stakeEndBlock, stake.StakedAmount, delegationFeeRate, pendingDelegationFeeRate, feeRateEffectiveBlock, rewardAddress, ownerAddress); err != nil {
+		return err
+	}
+	return storage.SubAssetSupply(e.ctx, e.mu, ids.Empty, slashedAmount)
+}
+
+// slashUnbonding slashes the stake unbonding from the validator with [nodeID]
+// for the offences in [slashes], see [emission.SlashEvent.SlashUnbonding].
+func (e *emissionState) slashUnbonding(nodeID ids.NodeID, slashes []*emission.SlashEvent) error {
+	if len(slashes) == 0 {
+		return nil
+	}
+	entries, err := storage.GetValidatorUnbonding(e.ctx, e.mu, nodeID)
+	if err != nil || len(entries) == 0 {
+		return err
+	}
+	slashedAmount := uint64(0)
+	for _, slash := range slashes {
+		for _, entry := range entries {
+			amount := slash.SlashUnbonding(entry.Amount, entry.UnbondedBlock, e.stakingConfig, e.epochTracker.EpochLength)
+			entry.Amount -= amount
+			slashedAmount += amount
+		}
+	}
+	if slashedAmount == 0 {
+		return nil
+	}
+	e.header.CollectSlash(slashedAmount)
+	if err := storage.SetValidatorUnbonding(e.ctx, e.mu, nodeID, entries); err != nil {
+		return err
+	}
+	return storage.SubAssetSupply(e.ctx, e.mu, ids.Empty, slashedAmount)
+}
+
+// updateRegistration ranks the validator with [nodeID] again after its
+// [stake] or delegations changed, see [emission.ValidatorRecord.UpdateRegistration].
+func (e *emissionState) updateRegistration(nodeID ids.NodeID, record *emission.ValidatorRecord, stake *emission.Stake) error {
+	return record.UpdateRegistration(nodeID, stake, e.header, e.loadActiveSet, e.stakingConfig, e.epochTracker)
+}
+
+// setValidator writes the record of the validator with [nodeID] back to state.
+func (e *emissionState) setValidator(nodeID ids.NodeID, record *emission.ValidatorRecord) error {
+	v, err := record.Marshal()
+	if err != nil {
+		return err
+	}
+	return storage.SetValidatorRecord(e.ctx, e.mu, nodeID, v)
+}
+
+// store writes the header, if it changed, and the active set, if it was
+// loaded, back to state.
+func (e *emissionState) store() error {
+	v, err := e.header.Marshal()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(v, e.headerBytes) {
+		if err := storage.SetEmission(e.ctx, e.mu, v); err != nil {
+			return err
+		}
+	}
+	if e.activeSet == nil {
+		return nil
+	}
+	v, err = e.activeSet.Marshal()
+	if err != nil {
+		return err
+	}
+	return storage.SetActiveSet(e.ctx, e.mu, v)
+}
+
+// output prefixes the [output] of the action with the ledger of what was
+// distributed and slashed.
+func (e *emissionState) output(output []byte) ([]byte, error) {
+	return emissionOutput(e.ledger, output)
+}
+
+// getStake reads the stake of the validator with [nodeID] from its stake
+// record. It returns nil if the validator has no stake registered.
+func getStake(ctx context.Context, im state.Immutable, nodeID ids.NodeID) (*emission.Stake, error) {
+	exists, stakeStartBlock, stakeEndBlock, stakedAmount, delegationFeeRate, pendingDelegationFeeRate, feeRateEffectiveBlock, _, _, err := storage.GetRegisterValidatorStake(ctx, im, nodeID)
+	if err != nil || !exists {
+		return nil, err
+	}
+	return &emission.Stake{
+		StakeStartBlock:          stakeStartBlock,
+		StakeEndBlock:            stakeEndBlock,
+		StakedAmount:             stakedAmount,
+		DelegationFeeRate:        delegationFeeRate,
+		PendingDelegationFeeRate: pendingDelegationFeeRate,
+		FeeRateEffectiveBlock:    feeRateEffectiveBlock,
+	}, nil
+}
+
+// emissionOutput prefixes the [output] of an action that changed the emission
+// balancer with the [ledger] of what it distributed and slashed.
+func emissionOutput(ledger *emission.Ledger, output []byte) ([]byte, error) {
+	p := codec.NewWriter(ledger.Size()+len(output), hconsts.MaxInt)
+	if err := ledger.Marshal(p); err != nil {
+		return nil, err
+	}
+	p.PackFixedBytes(output)
+	return p.Bytes(), p.Err()
+}
+
+// SplitEmissionOutput splits the output of a successful action that changed
+// the emission balancer into the ledger of what it distributed and slashed and
+// the output of the action itself.
+func SplitEmissionOutput(output []byte) (*emission.Ledger, []byte, error) {
+	p := codec.NewReader(output, len(output))
+	ledger, err := emission.UnmarshalLedger(p)
+	if err != nil {
+		return nil, nil, err
+	}
+	if p.Empty() {
+		return ledger, nil, nil
+	}
+	return ledger, output[p.Offset():], nil
+}
+
+// ChangesEmission returns whether the successful execution of [action] changes
+// the emission balancer, in which case its output is prefixed with a ledger.
+func ChangesEmission(action chain.Action) bool {
+	switch a := action.(type) {
+	case *RegisterValidatorStake, *ClaimValidatorStakeRewards, *WithdrawValidatorStake,
+		*DelegateUserStake, *ClaimDelegationStakeRewards, *UndelegateUserStake,
+		*RedelegateUserStake, *IncreaseDelegation, *DecreaseDelegation,
+		*IncreaseValidatorStake, *DecreaseValidatorStake, *UpdateValidatorStake,
+		*ValidatorHeartbeat, *ClaimEmissionFees:
+		return true
+	case *ReleaseUnbondedStake:
+		return len(a.NodeID) > 0
+	default:
+		return false
+	}
+}
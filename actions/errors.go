@@ -5,4 +5,8 @@ package actions
 
 import "errors"
 
-var ErrNoSwapToFill = errors.New("no swap to fill")
+var (
+	ErrNoSwapToFill     = errors.New("no swap to fill")
+	ErrTooManyTransfers = errors.New("too many transfers")
+	ErrInvalidHeight    = errors.New("invalid height")
+)
@@ -70,7 +70,7 @@ func (e *ExportAsset) executeReturn(
 	actor codec.Address,
 	txID ids.ID,
 ) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
-	exists, symbol, decimals, metadata, supply, _, isWarp, err := storage.GetAsset(ctx, mu, e.Asset)
+	exists, symbol, decimals, metadata, supply, _, _, _, isWarp, err := storage.GetAsset(ctx, mu, e.Asset)
 	if err != nil {
 		return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
 	}
@@ -96,7 +96,7 @@ func (e *ExportAsset) executeReturn(
 		return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
 	}
 	if newSupply > 0 {
-		if err := storage.SetAsset(ctx, mu, e.Asset, symbol, decimals, metadata, newSupply, codec.EmptyAddress, true); err != nil {
+		if err := storage.SetAsset(ctx, mu, e.Asset, symbol, decimals, metadata, newSupply, 0, codec.EmptyAddress, false, true); err != nil {
 			return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
 		}
 	} else {
@@ -148,7 +148,7 @@ func (e *ExportAsset) executeLoan(
 	actor codec.Address,
 	txID ids.ID,
 ) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
-	exists, symbol, decimals, _, _, _, isWarp, err := storage.GetAsset(ctx, mu, e.Asset)
+	exists, symbol, decimals, _, _, _, _, _, isWarp, err := storage.GetAsset(ctx, mu, e.Asset)
 	if err != nil {
 		return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
 	}
@@ -0,0 +1,36 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/ava-labs/hypersdk/chain"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+
+	"github.com/nuklai/nuklaivm/storage"
+)
+
+// Actions do not know the height of the block they are executed in, but the
+// hypersdk keeps the height of the parent block in state. Actions that depend
+// on the block height declare [heightStateKey] and read it with
+// [getParentHeight].
+
+func heightStateKey() string {
+	return string(chain.HeightKey(storage.HeightKey()))
+}
+
+// getParentHeight returns the height of the parent of the block being executed.
+func getParentHeight(ctx context.Context, im state.Immutable) (uint64, error) {
+	heightBytes, err := im.GetValue(ctx, chain.HeightKey(storage.HeightKey()))
+	if err != nil {
+		return 0, err
+	}
+	if len(heightBytes) != hconsts.Uint64Len {
+		return 0, ErrInvalidHeight
+	}
+	return binary.BigEndian.Uint64(heightBytes), nil
+}
@@ -106,7 +106,7 @@ func (i *ImportAsset) executeMint(
 	actor codec.Address,
 ) []byte {
 	asset := ImportedAssetID(i.warpTransfer.Asset, i.warpMessage.SourceChainID)
-	exists, symbol, decimals, metadata, supply, _, warp, err := storage.GetAsset(ctx, mu, asset)
+	exists, symbol, decimals, metadata, supply, _, _, _, warp, err := storage.GetAsset(ctx, mu, asset)
 	if err != nil {
 		return utils.ErrBytes(err)
 	}
@@ -127,7 +127,7 @@ func (i *ImportAsset) executeMint(
 	if err != nil {
 		return utils.ErrBytes(err)
 	}
-	if err := storage.SetAsset(ctx, mu, asset, symbol, decimals, metadata, newSupply, codec.EmptyAddress, true); err != nil {
+	if err := storage.SetAsset(ctx, mu, asset, symbol, decimals, metadata, newSupply, 0, codec.EmptyAddress, false, true); err != nil {
 		return utils.ErrBytes(err)
 	}
 	if err := storage.AddBalance(ctx, mu, i.warpTransfer.To, asset, i.warpTransfer.Value, true); err != nil {
@@ -146,7 +146,7 @@ func (i *ImportAsset) executeReturn(
 	mu state.Mutable,
 	actor codec.Address,
 ) []byte {
-	exists, symbol, decimals, _, _, _, warp, err := storage.GetAsset(ctx, mu, i.warpTransfer.Asset)
+	exists, symbol, decimals, _, _, _, _, _, warp, err := storage.GetAsset(ctx, mu, i.warpTransfer.Asset)
 	if err != nil {
 		return utils.ErrBytes(err)
 	}
@@ -0,0 +1,193 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/emission"
+	"github.com/nuklai/nuklaivm/storage"
+)
+
+var _ chain.Action = (*IncreaseDelegation)(nil)
+
+// IncreaseDelegation adds NAI to an existing delegation. The rewards accrued
+// so far are paid out to RewardAddress first.
+type IncreaseDelegation struct {
+	NodeID        []byte        `json:"nodeID"`        // Node ID of the validator where NAI is staked
+	Amount        uint64        `json:"amount"`        // Amount of NAI to add to the stake
+	RewardAddress codec.Address `json:"rewardAddress"` // Address to receive rewards
+}
+
+func (*IncreaseDelegation) GetTypeID() uint8 {
+	return nconsts.IncreaseDelegationID
+}
+
+func (i *IncreaseDelegation) StateKeys(actor codec.Address, txID ids.ID) []string {
+	// TODO: How to better handle a case where the NodeID is invalid?
+	nodeID, _ := ids.ToNodeID(i.NodeID)
+	return append([]string{
+		string(storage.BalanceKey(actor, ids.Empty)),
+		string(storage.BalanceKey(i.RewardAddress, ids.Empty)),
+		string(storage.DelegateUserStakeKey(actor, nodeID)),
+		string(storage.RegisterValidatorStakeKey(nodeID)),
+	}, append(validatorStateKeys(nodeID), emissionStateKeys(txID)...)...)
+}
+
+func (*IncreaseDelegation) StateKeysMaxChunks() []uint16 {
+	return append([]uint16{storage.BalanceChunks, storage.BalanceChunks, storage.DelegateUserStakeChunks, storage.RegisterValidatorStakeChunks}, append(validatorStateKeysMaxChunks(), emissionStateKeysMaxChunks()...)...)
+}
+
+func (*IncreaseDelegation) OutputsWarpMessage() bool {
+	return false
+}
+
+func (i *IncreaseDelegation) Execute(
+	ctx context.Context,
+	rules chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	txID ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	nodeID, err := ids.ToNodeID(i.NodeID)
+	if err != nil {
+		return false, IncreaseDelegationComputeUnits, OutputInvalidNodeID, nil, nil
+	}
+	if i.Amount == 0 {
+		return false, IncreaseDelegationComputeUnits, OutputValueZero, nil, nil
+	}
+
+	exists, stakeStartBlock, stakeEndBlock, stakedAmount, rewardAddress, ownerAddress, rewardWeight, rewardIndex, _, _ := storage.GetDelegateUserStake(ctx, mu, actor, nodeID)
+	if !exists {
+		return false, IncreaseDelegationComputeUnits, OutputStakeMissing, nil, nil
+	}
+	if ownerAddress != actor {
+		return false, IncreaseDelegationComputeUnits, OutputUnauthorized, nil, nil
+	}
+
+	e, err := loadEmission(ctx, rules, mu, timestamp, txID)
+	if err != nil {
+		return false, IncreaseDelegationComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	lastBlockHeight := e.parentHeight
+
+	// Check that the stake has not ended yet
+	if lastBlockHeight >= stakeEndBlock {
+		return false, IncreaseDelegationComputeUnits, OutputStakeEnded, nil, nil
+	}
+
+	stake, err := getStake(ctx, mu, nodeID)
+	if err != nil {
+		return false, IncreaseDelegationComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	record, exists, err := e.getValidator(nodeID, stake)
+	if err != nil {
+		return false, IncreaseDelegationComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, IncreaseDelegationComputeUnits, utils.ErrBytes(emission.ErrValidatorNotFound), nil, nil
+	}
+
+	// Check that the validator can take the stake
+	stakingConfig := emission.GetStakingConfig(rules)
+	if i.Amount > record.DelegationCapacity(stake, stakingConfig) {
+		return false, IncreaseDelegationComputeUnits, OutputDelegationCapacityExceeded, nil, nil
+	}
+
+	// Settle the rewards and increase the stake in Emission Balancer
+	rewardAmount, newRewardIndex, newRewardHeight := record.SettleDelegation(e.header, rewardWeight, rewardIndex, stakeStartBlock, e.epochTracker.EpochLength)
+	newRewardWeight := stakingConfig.StakeWeight(stakedAmount+i.Amount, stakeStartBlock, stakeEndBlock, stakingConfig.MaxDelegatorStakeDuration)
+	record.ChangeDelegation(stakedAmount, rewardWeight, stakedAmount+i.Amount, newRewardWeight)
+	if err := e.updateRegistration(nodeID, record, stake); err != nil {
+		return false, IncreaseDelegationComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := e.setValidator(nodeID, record); err != nil {
+		return false, IncreaseDelegationComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := e.store(); err != nil {
+		return false, IncreaseDelegationComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	if err := storage.SubBalance(ctx, mu, actor, ids.Empty, i.Amount); err != nil {
+		return false, IncreaseDelegationComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.AddNAI(ctx, mu, i.RewardAddress, rewardAmount); err != nil {
+		return false, IncreaseDelegationComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	stakedAmount += i.Amount
+	if err := storage.SetDelegateUserStake(ctx, mu, actor, nodeID, stakeStartBlock, stakeEndBlock, stakedAmount, rewardAddress, newRewardWeight, newRewardIndex, newRewardHeight); err != nil {
+		return false, IncreaseDelegationComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	sr := &ChangeStakeResult{stakedAmount, rewardAmount}
+	output, err := sr.Marshal()
+	if err != nil {
+		return false, IncreaseDelegationComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	output, err = e.output(output)
+	if err != nil {
+		return false, IncreaseDelegationComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, IncreaseDelegationComputeUnits, output, nil, nil
+}
+
+func (*IncreaseDelegation) MaxComputeUnits(chain.Rules) uint64 {
+	return IncreaseDelegationComputeUnits
+}
+
+func (*IncreaseDelegation) Size() int {
+	return hconsts.NodeIDLen + hconsts.Uint64Len + codec.AddressLen
+}
+
+func (i *IncreaseDelegation) Marshal(p *codec.Packer) {
+	p.PackBytes(i.NodeID)
+	p.PackUint64(i.Amount)
+	p.PackAddress(i.RewardAddress)
+}
+
+func UnmarshalIncreaseDelegation(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var increase IncreaseDelegation
+	p.UnpackBytes(hconsts.NodeIDLen, true, &increase.NodeID)
+	increase.Amount = p.UnpackUint64(true)
+	p.UnpackAddress(&increase.RewardAddress)
+	return &increase, p.Err()
+}
+
+func (*IncreaseDelegation) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+// ChangeStakeResult is the output of the actions that change the amount of an
+// existing validator stake or delegation.
+type ChangeStakeResult struct {
+	StakedAmount uint64 // Amount staked after the change
+	RewardAmount uint64 // Rewards settled before the change
+}
+
+func UnmarshalChangeStakeResult(b []byte) (*ChangeStakeResult, error) {
+	p := codec.NewReader(b, 2*hconsts.Uint64Len)
+	var result ChangeStakeResult
+	result.StakedAmount = p.UnpackUint64(true)
+	result.RewardAmount = p.UnpackUint64(false)
+	return &result, p.Err()
+}
+
+func (s *ChangeStakeResult) Marshal() ([]byte, error) {
+	p := codec.NewWriter(2*hconsts.Uint64Len, 2*hconsts.Uint64Len)
+	p.PackUint64(s.StakedAmount)
+	p.PackUint64(s.RewardAmount)
+	return p.Bytes(), p.Err()
+}
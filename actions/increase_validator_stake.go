@@ -0,0 +1,166 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/emission"
+	"github.com/nuklai/nuklaivm/storage"
+)
+
+var _ chain.Action = (*IncreaseValidatorStake)(nil)
+
+// IncreaseValidatorStake adds NAI to the stake of a registered validator. The
+// rewards accrued so far are paid out to RewardAddress first.
+type IncreaseValidatorStake struct {
+	NodeID        []byte        `json:"nodeID"`        // Node ID of the validator
+	Amount        uint64        `json:"amount"`        // Amount of NAI to add to the stake
+	RewardAddress codec.Address `json:"rewardAddress"` // Address to receive rewards
+}
+
+func (*IncreaseValidatorStake) GetTypeID() uint8 {
+	return nconsts.IncreaseValidatorStakeID
+}
+
+func (i *IncreaseValidatorStake) StateKeys(actor codec.Address, txID ids.ID) []string {
+	// TODO: How to better handle a case where the NodeID is invalid?
+	nodeID, _ := ids.ToNodeID(i.NodeID)
+	return append([]string{
+		string(storage.BalanceKey(actor, ids.Empty)),
+		string(storage.BalanceKey(i.RewardAddress, ids.Empty)),
+		string(storage.RegisterValidatorStakeKey(nodeID)),
+	}, append(validatorStateKeys(nodeID), emissionStateKeys(txID)...)...)
+}
+
+func (*IncreaseValidatorStake) StateKeysMaxChunks() []uint16 {
+	return append([]uint16{storage.BalanceChunks, storage.BalanceChunks, storage.RegisterValidatorStakeChunks}, append(validatorStateKeysMaxChunks(), emissionStateKeysMaxChunks()...)...)
+}
+
+func (*IncreaseValidatorStake) OutputsWarpMessage() bool {
+	return false
+}
+
+func (i *IncreaseValidatorStake) Execute(
+	ctx context.Context,
+	rules chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	txID ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	nodeID, err := ids.ToNodeID(i.NodeID)
+	if err != nil {
+		return false, IncreaseValidatorStakeComputeUnits, OutputInvalidNodeID, nil, nil
+	}
+	if i.Amount == 0 {
+		return false, IncreaseValidatorStakeComputeUnits, OutputValueZero, nil, nil
+	}
+
+	exists, stakeStartBlock, stakeEndBlock, _, delegationFeeRate, pendingDelegationFeeRate, feeRateEffectiveBlock, rewardAddress, ownerAddress, _ := storage.GetRegisterValidatorStake(ctx, mu, nodeID)
+	if !exists {
+		return false, IncreaseValidatorStakeComputeUnits, OutputValidatorNotYetRegistered, nil, nil
+	}
+	if ownerAddress != actor {
+		return false, IncreaseValidatorStakeComputeUnits, OutputUnauthorized, nil, nil
+	}
+
+	e, err := loadEmission(ctx, rules, mu, timestamp, txID)
+	if err != nil {
+		return false, IncreaseValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	// Check that the stake has not ended yet
+	if e.parentHeight >= stakeEndBlock {
+		return false, IncreaseValidatorStakeComputeUnits, OutputStakeEnded, nil, nil
+	}
+
+	// Check that the validator can take the stake
+	stake, err := getStake(ctx, mu, nodeID)
+	if err != nil {
+		return false, IncreaseValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	record, found, err := e.getValidator(nodeID, stake)
+	if err != nil {
+		return false, IncreaseValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !found {
+		return false, IncreaseValidatorStakeComputeUnits, utils.ErrBytes(emission.ErrValidatorNotFound), nil, nil
+	}
+	validatorStakedAmount := stake.StakedAmount
+	if validatorStakedAmount+record.DelegatedAmount+i.Amount > emission.GetStakingConfig(rules).MaxValidatorStake {
+		return false, IncreaseValidatorStakeComputeUnits, OutputValidatorStakeLimitExceeded, nil, nil
+	}
+
+	// Settle the rewards and increase the stake in Emission Balancer
+	rewardAmount := record.ClaimRewards(e.header)
+	stake.StakedAmount += i.Amount
+	if err := e.updateRegistration(nodeID, record, stake); err != nil {
+		return false, IncreaseValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := e.setValidator(nodeID, record); err != nil {
+		return false, IncreaseValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := e.store(); err != nil {
+		return false, IncreaseValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	if err := storage.SubBalance(ctx, mu, actor, ids.Empty, i.Amount); err != nil {
+		return false, IncreaseValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.AddNAI(ctx, mu, i.RewardAddress, rewardAmount); err != nil {
+		return false, IncreaseValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.SetRegisterValidatorStake(ctx, mu, nodeID, stakeStartBlock, stakeEndBlock, stake.StakedAmount, delegationFeeRate, pendingDelegationFeeRate, feeRateEffectiveBlock, rewardAddress, ownerAddress); err != nil {
+		return false, IncreaseValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	sr := &ChangeStakeResult{validatorStakedAmount + i.Amount, rewardAmount}
+	output, err := sr.Marshal()
+	if err != nil {
+		return false, IncreaseValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	output, err = e.output(output)
+	if err != nil {
+		return false, IncreaseValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, IncreaseValidatorStakeComputeUnits, output, nil, nil
+}
+
+func (*IncreaseValidatorStake) MaxComputeUnits(chain.Rules) uint64 {
+	return IncreaseValidatorStakeComputeUnits
+}
+
+func (*IncreaseValidatorStake) Size() int {
+	return hconsts.NodeIDLen + hconsts.Uint64Len + codec.AddressLen
+}
+
+func (i *IncreaseValidatorStake) Marshal(p *codec.Packer) {
+	p.PackBytes(i.NodeID)
+	p.PackUint64(i.Amount)
+	p.PackAddress(i.RewardAddress)
+}
+
+func UnmarshalIncreaseValidatorStake(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var increase IncreaseValidatorStake
+	p.UnpackBytes(hconsts.NodeIDLen, true, &increase.NodeID)
+	increase.Amount = p.UnpackUint64(true)
+	p.UnpackAddress(&increase.RewardAddress)
+	return &increase, p.Err()
+}
+
+func (*IncreaseValidatorStake) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -67,7 +67,7 @@ func (m *MintAsset) Execute(
 	if m.Value == 0 {
 		return false, MintAssetComputeUnits, OutputValueZero, nil, nil
 	}
-	exists, symbol, decimals, metadata, supply, owner, isWarp, err := storage.GetAsset(ctx, mu, m.Asset)
+	exists, symbol, decimals, metadata, supply, maxSupply, owner, frozen, isWarp, err := storage.GetAsset(ctx, mu, m.Asset)
 	if err != nil {
 		return false, MintAssetComputeUnits, utils.ErrBytes(err), nil, nil
 	}
@@ -80,11 +80,19 @@ func (m *MintAsset) Execute(
 	if owner != actor {
 		return false, MintAssetComputeUnits, OutputWrongOwner, nil, nil
 	}
+	if frozen {
+		return false, MintAssetComputeUnits, OutputMintFrozen, nil, nil
+	}
 	newSupply, err := hmath.Add64(supply, m.Value)
 	if err != nil {
 		return false, MintAssetComputeUnits, utils.ErrBytes(err), nil, nil
 	}
-	if err := storage.SetAsset(ctx, mu, m.Asset, symbol, decimals, metadata, newSupply, actor, isWarp); err != nil {
+	// [maxSupply] caps the circulating supply rather than the amount ever
+	// minted, so burned tokens can be minted again.
+	if maxSupply > 0 && newSupply > maxSupply {
+		return false, MintAssetComputeUnits, OutputMaxSupplyExceeded, nil, nil
+	}
+	if err := storage.SetAsset(ctx, mu, m.Asset, symbol, decimals, metadata, newSupply, maxSupply, actor, frozen, isWarp); err != nil {
 		return false, MintAssetComputeUnits, utils.ErrBytes(err), nil, nil
 	}
 	if err := storage.AddBalance(ctx, mu, m.To, m.Asset, m.Value, true); err != nil {
@@ -0,0 +1,134 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	hmath "github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/storage"
+)
+
+var _ chain.Action = (*MintNFT)(nil)
+
+type MintNFT struct {
+	// Collection is the [TxID] that created the collection.
+	Collection ids.ID `json:"collection"`
+
+	// ID of the NFT within its collection. It must not be in use.
+	ID uint64 `json:"id"`
+
+	// To is the recipient of the NFT.
+	To codec.Address `json:"to"`
+
+	// Metadata of the NFT.
+	Metadata []byte `json:"metadata"`
+}
+
+func (*MintNFT) GetTypeID() uint8 {
+	return nconsts.MintNFTID
+}
+
+func (m *MintNFT) StateKeys(codec.Address, ids.ID) []string {
+	return []string{
+		string(storage.NFTCollectionKey(m.Collection)),
+		string(storage.NFTKey(m.Collection, m.ID)),
+		string(storage.NFTOwnerKey(m.To, m.Collection, m.ID)),
+	}
+}
+
+func (*MintNFT) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.NFTCollectionChunks, storage.NFTChunks, storage.NFTOwnerChunks}
+}
+
+func (*MintNFT) OutputsWarpMessage() bool {
+	return false
+}
+
+func (m *MintNFT) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	if len(m.Metadata) == 0 {
+		return false, MintNFTComputeUnits, OutputMetadataEmpty, nil, nil
+	}
+	if len(m.Metadata) > MaxMetadataSize {
+		return false, MintNFTComputeUnits, OutputMetadataTooLarge, nil, nil
+	}
+	exists, symbol, metadata, supply, owner, err := storage.GetNFTCollection(ctx, mu, m.Collection)
+	if err != nil {
+		return false, MintNFTComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, MintNFTComputeUnits, OutputNFTCollectionMissing, nil, nil
+	}
+	if owner != actor {
+		return false, MintNFTComputeUnits, OutputWrongOwner, nil, nil
+	}
+	exists, _, _, err = storage.GetNFT(ctx, mu, m.Collection, m.ID)
+	if err != nil {
+		return false, MintNFTComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if exists {
+		return false, MintNFTComputeUnits, OutputNFTAlreadyExists, nil, nil
+	}
+	newSupply, err := hmath.Add64(supply, 1)
+	if err != nil {
+		return false, MintNFTComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.SetNFTCollection(ctx, mu, m.Collection, symbol, metadata, newSupply, owner); err != nil {
+		return false, MintNFTComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.SetNFT(ctx, mu, m.Collection, m.ID, m.To, m.Metadata); err != nil {
+		return false, MintNFTComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.AddNFTToOwner(ctx, mu, m.To, m.Collection, m.ID); err != nil {
+		return false, MintNFTComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, MintNFTComputeUnits, nil, nil, nil
+}
+
+func (*MintNFT) MaxComputeUnits(chain.Rules) uint64 {
+	return MintNFTComputeUnits
+}
+
+func (m *MintNFT) Size() int {
+	return hconsts.IDLen + hconsts.Uint64Len + codec.AddressLen + codec.BytesLen(m.Metadata)
+}
+
+func (m *MintNFT) Marshal(p *codec.Packer) {
+	p.PackID(m.Collection)
+	p.PackUint64(m.ID)
+	p.PackAddress(m.To)
+	p.PackBytes(m.Metadata)
+}
+
+func UnmarshalMintNFT(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var mint MintNFT
+	p.UnpackID(true, &mint.Collection)
+	mint.ID = p.UnpackUint64(false)
+	p.UnpackAddress(&mint.To)
+	p.UnpackBytes(MaxMetadataSize, true, &mint.Metadata)
+	return &mint, p.Err()
+}
+
+func (*MintNFT) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
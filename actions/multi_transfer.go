@@ -0,0 +1,163 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/storage"
+)
+
+var _ chain.Action = (*MultiTransfer)(nil)
+
+const (
+	MaxMultiTransferEntries = 64
+)
+
+type TransferEntry struct {
+	// To is the recipient of the [Value].
+	To codec.Address `json:"to"`
+
+	// Asset to transfer to [To].
+	Asset ids.ID `json:"asset"`
+
+	// Amount are transferred to [To].
+	Value uint64 `json:"value"`
+
+	// Optional message to accompany the transfer.
+	Memo []byte `json:"memo"`
+}
+
+// MultiTransfer executes up to [MaxMultiTransferEntries] transfers from the
+// actor at once. If any of them fails, none of them is applied.
+type MultiTransfer struct {
+	Transfers []*TransferEntry `json:"transfers"`
+}
+
+func (*MultiTransfer) GetTypeID() uint8 {
+	return nconsts.MultiTransferID
+}
+
+func (m *MultiTransfer) StateKeys(actor codec.Address, _ ids.ID) []string {
+	keys := set.NewSet[string](2 * len(m.Transfers))
+	stateKeys := make([]string, 0, 2*len(m.Transfers))
+	for _, transfer := range m.Transfers {
+		for _, k := range []string{
+			string(storage.BalanceKey(actor, transfer.Asset)),
+			string(storage.BalanceKey(transfer.To, transfer.Asset)),
+		} {
+			if keys.Contains(k) {
+				continue
+			}
+			keys.Add(k)
+			stateKeys = append(stateKeys, k)
+		}
+	}
+	return stateKeys
+}
+
+func (m *MultiTransfer) StateKeysMaxChunks() []uint16 {
+	// The actor may send several entries of the same asset, so this is an
+	// upper bound of the keys returned by [StateKeys].
+	chunks := make([]uint16, 2*len(m.Transfers))
+	for i := range chunks {
+		chunks[i] = storage.BalanceChunks
+	}
+	return chunks
+}
+
+func (*MultiTransfer) OutputsWarpMessage() bool {
+	return false
+}
+
+func (m *MultiTransfer) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	computeUnits := m.MaxComputeUnits(r)
+	if len(m.Transfers) == 0 {
+		return false, computeUnits, OutputNoTransfers, nil, nil
+	}
+	if len(m.Transfers) > MaxMultiTransferEntries {
+		return false, computeUnits, OutputTooManyTransfers, nil, nil
+	}
+	for _, transfer := range m.Transfers {
+		if transfer.Value == 0 {
+			return false, computeUnits, OutputValueZero, nil, nil
+		}
+		if len(transfer.Memo) > MaxMemoSize {
+			return false, computeUnits, OutputMemoTooLarge, nil, nil
+		}
+		if err := storage.SubBalance(ctx, mu, actor, transfer.Asset, transfer.Value); err != nil {
+			return false, computeUnits, utils.ErrBytes(err), nil, nil
+		}
+		if err := storage.AddBalance(ctx, mu, transfer.To, transfer.Asset, transfer.Value, true); err != nil {
+			return false, computeUnits, utils.ErrBytes(err), nil, nil
+		}
+	}
+	return true, computeUnits, nil, nil, nil
+}
+
+func (m *MultiTransfer) MaxComputeUnits(chain.Rules) uint64 {
+	return uint64(len(m.Transfers)) * MultiTransferComputeUnitsPerEntry
+}
+
+func (m *MultiTransfer) Size() int {
+	size := hconsts.IntLen
+	for _, transfer := range m.Transfers {
+		size += codec.AddressLen + hconsts.IDLen + hconsts.Uint64Len + codec.BytesLen(transfer.Memo)
+	}
+	return size
+}
+
+func (m *MultiTransfer) Marshal(p *codec.Packer) {
+	p.PackInt(len(m.Transfers))
+	for _, transfer := range m.Transfers {
+		p.PackAddress(transfer.To)
+		p.PackID(transfer.Asset)
+		p.PackUint64(transfer.Value)
+		p.PackBytes(transfer.Memo)
+	}
+}
+
+func UnmarshalMultiTransfer(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var multiTransfer MultiTransfer
+	count := p.UnpackInt(true)
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	if count > MaxMultiTransferEntries {
+		return nil, ErrTooManyTransfers
+	}
+	multiTransfer.Transfers = make([]*TransferEntry, count)
+	for i := range multiTransfer.Transfers {
+		var transfer TransferEntry
+		p.UnpackAddress(&transfer.To)
+		p.UnpackID(false, &transfer.Asset) // empty ID is the native asset
+		transfer.Value = p.UnpackUint64(true)
+		p.UnpackBytes(MaxMemoSize, false, &transfer.Memo)
+		multiTransfer.Transfers[i] = &transfer
+	}
+	return &multiTransfer, p.Err()
+}
+
+func (*MultiTransfer) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -7,6 +7,10 @@ var (
 	OutputValueZero    = []byte("value is zero")
 	OutputMemoTooLarge = []byte("memo is too large")
 
+	// multi_transfer.go
+	OutputNoTransfers      = []byte("no transfers")
+	OutputTooManyTransfers = []byte("too many transfers")
+
 	OutputLockupPeriodInvalid       = []byte("lockup period is invalid")
 	OutputStakeMissing              = []byte("stake is missing")
 	OutputUnauthorized              = []byte("unauthorized")
@@ -32,8 +36,28 @@ var (
 	OutputMustFill               = []byte("must fill request")
 
 	// mint_asset.go
-	OutputAssetIsNative = []byte("cannot mint native asset")
-	OutputWrongOwner    = []byte("wrong owner")
+	OutputAssetIsNative     = []byte("cannot mint native asset")
+	OutputWrongOwner        = []byte("wrong owner")
+	OutputMintFrozen        = []byte("minting is frozen")
+	OutputMaxSupplyExceeded = []byte("max supply exceeded")
+
+	// update_asset.go
+	OutputCannotUpdateNative = []byte("cannot update native asset")
+
+	// nft
+	// mint_nft.go
+	OutputNFTCollectionMissing = []byte("nft collection missing")
+	OutputNFTAlreadyExists     = []byte("nft already exists")
+	// transfer_nft.go
+	OutputNFTMissing = []byte("nft missing")
+
+	// allowances
+	// approve_asset.go
+	OutputApproveSelf        = []byte("cannot approve self")
+	OutputInvalidExpiryBlock = []byte("expiry block must be in the future")
+	// transfer_from.go
+	OutputAllowanceExpired  = []byte("allowance expired")
+	OutputAllowanceExceeded = []byte("allowance exceeded")
 
 	// staking
 	// register_validator_stake.go
@@ -51,4 +75,19 @@ var (
 	OutputDelegateStakedAmountInvalid = []byte("staked amount must be at least 25 NAI")
 	OutputUserAlreadyStaked           = []byte("user already staked")
 	OutputValidatorNotYetRegistered   = []byte("validator not yet registered for staking")
+	// claim_emission_fees.go
+	OutputNoFeesToClaim = []byte("no fees to claim")
+	// release_unbonded_stake.go
+	OutputUnbondingQueueFull = []byte("too many stakes unbonding")
+	OutputNoUnbondedStake    = []byte("no unbonded stake to release")
+	// redelegate_user_stake.go
+	OutputSameValidator               = []byte("cannot redelegate to the same validator")
+	OutputValidatorStakeEnded         = []byte("validator stake ended")
+	OutputValidatorStakeLimitExceeded = []byte("validator stake limit exceeded")
+	OutputLockupShortened             = []byte("stake can't end before its current end block")
+	// increase_delegation.go
+	OutputStakeEnded = []byte("stake ended")
+	// delegate_user_stake.go
+	OutputDelegationCapacityExceeded  = []byte("validator delegation capacity exceeded")
+	OutputDelegationOutlivesValidator = []byte("delegation ends after the validator stake")
 )
@@ -0,0 +1,255 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/emission"
+	"github.com/nuklai/nuklaivm/storage"
+)
+
+var _ chain.Action = (*RedelegateUserStake)(nil)
+
+// RedelegateUserStake moves a delegation, along with the rewards it accrued so
+// far, from one validator to another without going through unbonding.
+type RedelegateUserStake struct {
+	FromNodeID    []byte `json:"fromNodeID"`    // Node ID of the validator where NAI is staked
+	ToNodeID      []byte `json:"toNodeID"`      // Node ID of the validator to move the stake to
+	StakeEndBlock uint64 `json:"stakeEndBlock"` // Block height at which the stake on the new validator should end, at least the current end block
+}
+
+func (*RedelegateUserStake) GetTypeID() uint8 {
+	return nconsts.RedelegateUserStakeID
+}
+
+func (r *RedelegateUserStake) StateKeys(actor codec.Address, txID ids.ID) []string {
+	// TODO: How to better handle a case where the NodeID is invalid?
+	fromNodeID, _ := ids.ToNodeID(r.FromNodeID)
+	toNodeID, _ := ids.ToNodeID(r.ToNodeID)
+	return append([]string{
+		string(storage.DelegateUserStakeKey(actor, fromNodeID)),
+		string(storage.DelegateUserStakeKey(actor, toNodeID)),
+		string(storage.RegisterValidatorStakeKey(fromNodeID)),
+		string(storage.RegisterValidatorStakeKey(toNodeID)),
+		string(storage.ValidatorRecordKey(fromNodeID)),
+		string(storage.ValidatorUnbondingKey(fromNodeID)),
+	}, append(validatorStateKeys(toNodeID), emissionStateKeys(txID)...)...)
+}
+
+func (*RedelegateUserStake) StateKeysMaxChunks() []uint16 {
+	return append([]uint16{storage.DelegateUserStakeChunks, storage.DelegateUserStakeChunks, storage.RegisterValidatorStakeChunks, storage.RegisterValidatorStakeChunks, storage.ValidatorRecordChunks, storage.ValidatorUnbondingChunks}, append(validatorStateKeysMaxChunks(), emissionStateKeysMaxChunks()...)...)
+}
+
+func (*RedelegateUserStake) OutputsWarpMessage() bool {
+	return false
+}
+
+func (r *RedelegateUserStake) Execute(
+	ctx context.Context,
+	rules chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	txID ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	fromNodeID, err := ids.ToNodeID(r.FromNodeID)
+	if err != nil {
+		return false, RedelegateUserStakeComputeUnits, OutputInvalidNodeID, nil, nil
+	}
+	toNodeID, err := ids.ToNodeID(r.ToNodeID)
+	if err != nil {
+		return false, RedelegateUserStakeComputeUnits, OutputInvalidNodeID, nil, nil
+	}
+	if fromNodeID == toNodeID {
+		return false, RedelegateUserStakeComputeUnits, OutputSameValidator, nil, nil
+	}
+
+	exists, stakeStartBlock, stakeEndBlock, stakedAmount, rewardAddress, ownerAddress, rewardWeight, rewardIndex, _, _ := storage.GetDelegateUserStake(ctx, mu, actor, fromNodeID)
+	if !exists {
+		return false, RedelegateUserStakeComputeUnits, OutputStakeMissing, nil, nil
+	}
+	if ownerAddress != actor {
+		return false, RedelegateUserStakeComputeUnits, OutputUnauthorized, nil, nil
+	}
+
+	// Check if the validator the user is trying to move to is registered for staking
+	exists, _, validatorStakeEndBlock, _, _, _, _, _, _, _ := storage.GetRegisterValidatorStake(ctx, mu, toNodeID)
+	if !exists {
+		return false, RedelegateUserStakeComputeUnits, OutputValidatorNotYetRegistered, nil, nil
+	}
+
+	// Check if the user has already delegated to the new validator
+	exists, _, _, _, _, _, _, _, _, _ = storage.GetDelegateUserStake(ctx, mu, actor, toNodeID)
+	if exists {
+		return false, RedelegateUserStakeComputeUnits, OutputUserAlreadyStaked, nil, nil
+	}
+
+	e, err := loadEmission(ctx, rules, mu, timestamp, txID)
+	if err != nil {
+		return false, RedelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	lastBlockHeight := e.parentHeight
+
+	// The stake on the new validator starts right away
+	if validatorStakeEndBlock <= lastBlockHeight {
+		return false, RedelegateUserStakeComputeUnits, OutputValidatorStakeEnded, nil, nil
+	}
+	if r.StakeEndBlock <= lastBlockHeight {
+		return false, RedelegateUserStakeComputeUnits, OutputInvalidStakeEndBlock, nil, nil
+	}
+	// Moving the stake doesn't release it early: it stays locked up at least
+	// until the end block it was delegated until
+	if r.StakeEndBlock < stakeEndBlock {
+		return false, RedelegateUserStakeComputeUnits, OutputLockupShortened, nil, nil
+	}
+	if r.StakeEndBlock > validatorStakeEndBlock {
+		return false, RedelegateUserStakeComputeUnits, OutputDelegationOutlivesValidator, nil, nil
+	}
+	stakingConfig := emission.GetStakingConfig(rules)
+	stakeDuration := r.StakeEndBlock - lastBlockHeight
+	if stakeDuration < stakingConfig.MinDelegatorStakeDuration || stakeDuration > stakingConfig.MaxDelegatorStakeDuration {
+		return false, RedelegateUserStakeComputeUnits, OutputInvalidStakeDuration, nil, nil
+	}
+	if _, ok := stakingConfig.LockupMultiplier(stakeDuration, stakingConfig.MaxDelegatorStakeDuration); !ok {
+		return false, RedelegateUserStakeComputeUnits, OutputLockupPeriodInvalid, nil, nil
+	}
+
+	fromStake, err := getStake(ctx, mu, fromNodeID)
+	if err != nil {
+		return false, RedelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	fromRecord, exists, err := e.getValidator(fromNodeID, fromStake)
+	if err != nil {
+		return false, RedelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, RedelegateUserStakeComputeUnits, utils.ErrBytes(emission.ErrValidatorNotFound), nil, nil
+	}
+	toStake, err := getStake(ctx, mu, toNodeID)
+	if err != nil {
+		return false, RedelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	toRecord, exists, err := e.getValidator(toNodeID, toStake)
+	if err != nil {
+		return false, RedelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, RedelegateUserStakeComputeUnits, utils.ErrBytes(emission.ErrValidatorNotFound), nil, nil
+	}
+
+	// The accrued rewards are moved along with the stake
+	rewardAmount, _, _ := fromRecord.SettleDelegation(e.header, rewardWeight, rewardIndex, stakeStartBlock, e.epochTracker.EpochLength)
+	newStakedAmount := stakedAmount + rewardAmount
+
+	// Check that the new validator can take the stake
+	if newStakedAmount > toRecord.DelegationCapacity(toStake, stakingConfig) {
+		return false, RedelegateUserStakeComputeUnits, OutputDelegationCapacityExceeded, nil, nil
+	}
+
+	// Redelegate in Emission Balancer
+	fromRecord.Undelegate(stakedAmount, rewardWeight, stakeStartBlock)
+	if err := e.updateRegistration(fromNodeID, fromRecord, fromStake); err != nil {
+		return false, RedelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if fromStake == nil && fromRecord.Close(e.header) {
+		err = storage.DeleteValidatorRecord(ctx, mu, fromNodeID)
+	} else {
+		err = e.setValidator(fromNodeID, fromRecord)
+	}
+	if err != nil {
+		return false, RedelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	newRewardWeight := stakingConfig.StakeWeight(newStakedAmount, lastBlockHeight, r.StakeEndBlock, stakingConfig.MaxDelegatorStakeDuration)
+	newRewardIndex, newRewardHeight := toRecord.Delegate(newStakedAmount, newRewardWeight, e.epochTracker.EpochLength)
+	if err := e.updateRegistration(toNodeID, toRecord, toStake); err != nil {
+		return false, RedelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := e.setValidator(toNodeID, toRecord); err != nil {
+		return false, RedelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := e.store(); err != nil {
+		return false, RedelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	// The rewards paid out by the emission balancer join the supply of NAI
+	// held in state as part of the new stake
+	if err := storage.AddAssetSupply(ctx, mu, ids.Empty, rewardAmount); err != nil {
+		return false, RedelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.DeleteDelegateUserStake(ctx, mu, actor, fromNodeID); err != nil {
+		return false, RedelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.SetDelegateUserStake(ctx, mu, actor, toNodeID, lastBlockHeight, r.StakeEndBlock, newStakedAmount, rewardAddress, newRewardWeight, newRewardIndex, newRewardHeight); err != nil {
+		return false, RedelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	sr := &RedelegateUserStakeResult{newStakedAmount, rewardAmount}
+	output, err := sr.Marshal()
+	if err != nil {
+		return false, RedelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	output, err = e.output(output)
+	if err != nil {
+		return false, RedelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, RedelegateUserStakeComputeUnits, output, nil, nil
+}
+
+func (*RedelegateUserStake) MaxComputeUnits(chain.Rules) uint64 {
+	return RedelegateUserStakeComputeUnits
+}
+
+func (*RedelegateUserStake) Size() int {
+	return 2*hconsts.NodeIDLen + hconsts.Uint64Len
+}
+
+func (r *RedelegateUserStake) Marshal(p *codec.Packer) {
+	p.PackBytes(r.FromNodeID)
+	p.PackBytes(r.ToNodeID)
+	p.PackUint64(r.StakeEndBlock)
+}
+
+func UnmarshalRedelegateUserStake(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var redelegate RedelegateUserStake
+	p.UnpackBytes(hconsts.NodeIDLen, true, &redelegate.FromNodeID)
+	p.UnpackBytes(hconsts.NodeIDLen, true, &redelegate.ToNodeID)
+	redelegate.StakeEndBlock = p.UnpackUint64(true)
+	return &redelegate, p.Err()
+}
+
+func (*RedelegateUserStake) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+type RedelegateUserStakeResult struct {
+	StakedAmount uint64 // Amount staked on the new validator
+	RewardAmount uint64 // Rewards accrued on the previous validator that were added to the stake
+}
+
+func UnmarshalRedelegateUserStakeResult(b []byte) (*RedelegateUserStakeResult, error) {
+	p := codec.NewReader(b, 2*hconsts.Uint64Len)
+	var result RedelegateUserStakeResult
+	result.StakedAmount = p.UnpackUint64(true)
+	result.RewardAmount = p.UnpackUint64(false)
+	return &result, p.Err()
+}
+
+func (s *RedelegateUserStakeResult) Marshal() ([]byte, error) {
+	p := codec.NewWriter(2*hconsts.Uint64Len, 2*hconsts.Uint64Len)
+	p.PackUint64(s.StakedAmount)
+	p.PackUint64(s.RewardAmount)
+	return p.Bytes(), p.Err()
+}
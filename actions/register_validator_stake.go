@@ -32,18 +32,19 @@ func (*RegisterValidatorStake) GetTypeID() uint8 {
 	return nconsts.RegisterValidatorStakeID
 }
 
-func (r *RegisterValidatorStake) StateKeys(actor codec.Address, _ ids.ID) []string {
+func (r *RegisterValidatorStake) StateKeys(actor codec.Address, txID ids.ID) []string {
 	// TODO: How to better handle a case where the NodeID is invalid?
 	stakeInfo, _ := UnmarshalValidatorStakeInfo(r.StakeInfo)
 	nodeID, _ := ids.ToNodeID(stakeInfo.NodeID)
-	return []string{
+	return append([]string{
 		string(storage.BalanceKey(actor, ids.Empty)),
 		string(storage.RegisterValidatorStakeKey(nodeID)),
-	}
+		string(storage.ValidatorRecordKey(nodeID)),
+	}, emissionStateKeys(txID)...)
 }
 
 func (*RegisterValidatorStake) StateKeysMaxChunks() []uint16 {
-	return []uint16{storage.BalanceChunks, storage.RegisterValidatorStakeChunks}
+	return append([]uint16{storage.BalanceChunks, storage.RegisterValidatorStakeChunks, storage.ValidatorRecordChunks}, emissionStateKeysMaxChunks()...)
 }
 
 func (*RegisterValidatorStake) OutputsWarpMessage() bool {
@@ -52,11 +53,11 @@ func (*RegisterValidatorStake) OutputsWarpMessage() bool {
 
 func (r *RegisterValidatorStake) Execute(
 	ctx context.Context,
-	_ chain.Rules,
+	rules chain.Rules,
 	mu state.Mutable,
-	_ int64,
+	timestamp int64,
 	actor codec.Address,
-	_ ids.ID,
+	txID ids.ID,
 	_ bool,
 ) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
 	// Check if it's a valid signature
@@ -75,9 +76,12 @@ func (r *RegisterValidatorStake) Execute(
 
 	// Get the emission instance
 	emissionInstance := emission.GetEmission()
-	currentValidators := emissionInstance.GetAllValidators(ctx)
+	currentValidators, err := emissionInstance.GetAllValidators(ctx)
+	if err != nil {
+		return false, RegisterValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
 
-	var nodePublicKey *bls.PublicKey
+	var nodeIDOfSigner ids.NodeID
 	for _, validator := range currentValidators {
 		publicKey, err := bls.PublicKeyFromBytes(validator.PublicKey)
 		if err != nil {
@@ -86,7 +90,7 @@ func (r *RegisterValidatorStake) Execute(
 		signer := auth.NewBLSAddress(publicKey)
 		if actorAddress == codec.MustAddressBech32(nconsts.HRP, signer) {
 			isValidatorOwner = true
-			nodePublicKey = publicKey
+			nodeIDOfSigner = validator.NodeID
 			break
 		}
 	}
@@ -104,22 +108,29 @@ func (r *RegisterValidatorStake) Execute(
 	if err != nil {
 		return false, RegisterValidatorStakeComputeUnits, OutputInvalidNodeID, nil, nil
 	}
+	// Check if the node key of the actor is the key of this NodeID
+	if nodeID != nodeIDOfSigner {
+		return false, RegisterValidatorStakeComputeUnits, OutputDifferentNodeIDThanStaked, nil, nil
+	}
 
 	// Check if the validator was already registered
-	exists, _, _, _, _, _, _, _ := storage.GetRegisterValidatorStake(ctx, mu, nodeID)
+	exists, _, _, _, _, _, _, _, _, _ := storage.GetRegisterValidatorStake(ctx, mu, nodeID)
 	if exists {
 		return false, RegisterValidatorStakeComputeUnits, OutputValidatorAlreadyRegistered, nil, nil
 	}
 
-	stakingConfig := emission.GetStakingConfig()
+	stakingConfig := emission.GetStakingConfig(rules)
 
 	// Check if the staked amount is a valid amount
 	if stakeInfo.StakedAmount < stakingConfig.MinValidatorStake || stakeInfo.StakedAmount > stakingConfig.MaxValidatorStake {
 		return false, RegisterValidatorStakeComputeUnits, OutputValidatorStakedAmountInvalid, nil, nil
 	}
 
-	// Get last accepted block height
-	lastBlockHeight := emissionInstance.GetLastAcceptedBlockHeight()
+	e, err := loadEmission(ctx, rules, mu, timestamp, txID)
+	if err != nil {
+		return false, RegisterValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	lastBlockHeight := e.parentHeight
 
 	// Check that stakeStartBlock is after lastBlockHeight
 	if stakeInfo.StakeStartBlock < lastBlockHeight {
@@ -135,25 +146,43 @@ func (r *RegisterValidatorStake) Execute(
 	if stakeDuration < stakingConfig.MinValidatorStakeDuration || stakeDuration > stakingConfig.MaxValidatorStakeDuration {
 		return false, RegisterValidatorStakeComputeUnits, OutputInvalidStakeDuration, nil, nil
 	}
+	// Check that the lockup period is covered by the lockup tiers
+	if _, ok := stakingConfig.LockupMultiplier(stakeDuration, stakingConfig.MaxValidatorStakeDuration); !ok {
+		return false, RegisterValidatorStakeComputeUnits, OutputLockupPeriodInvalid, nil, nil
+	}
 
 	// Check if the delegation fee rate is valid
 	if stakeInfo.DelegationFeeRate < stakingConfig.MinDelegationFee || stakeInfo.DelegationFeeRate > 100 {
 		return false, RegisterValidatorStakeComputeUnits, OutputInvalidDelegationFeeRate, nil, nil
 	}
 
-	// Register in Emission Balancer
-	err = emissionInstance.RegisterValidatorStake(nodeID, nodePublicKey, stakeInfo.StakeStartBlock, stakeInfo.StakeEndBlock, stakeInfo.StakedAmount, stakeInfo.DelegationFeeRate)
+	// Register in Emission Balancer. A validator that withdrew its stake
+	// keeps its record while it has delegations left, so it is reused.
+	record, exists, err := e.getValidator(nodeID, nil)
 	if err != nil {
 		return false, RegisterValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
 	}
+	if !exists {
+		record = emission.NewValidatorRecord(e.header, e.epochTracker)
+	}
+	if err := e.setValidator(nodeID, record); err != nil {
+		return false, RegisterValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := e.store(); err != nil {
+		return false, RegisterValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
 
 	if err := storage.SubBalance(ctx, mu, actor, ids.Empty, stakeInfo.StakedAmount); err != nil {
 		return false, RegisterValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
 	}
-	if err := storage.SetRegisterValidatorStake(ctx, mu, nodeID, stakeInfo.StakeStartBlock, stakeInfo.StakeEndBlock, stakeInfo.StakedAmount, stakeInfo.DelegationFeeRate, stakeInfo.RewardAddress, actor); err != nil {
+	if err := storage.SetRegisterValidatorStake(ctx, mu, nodeID, stakeInfo.StakeStartBlock, stakeInfo.StakeEndBlock, stakeInfo.StakedAmount, stakeInfo.DelegationFeeRate, 0, 0, stakeInfo.RewardAddress, actor); err != nil {
+		return false, RegisterValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	output, err := e.output(nil)
+	if err != nil {
 		return false, RegisterValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
 	}
-	return true, RegisterValidatorStakeComputeUnits, nil, nil, nil
+	return true, RegisterValidatorStakeComputeUnits, output, nil, nil
 }
 
 func (*RegisterValidatorStake) MaxComputeUnits(chain.Rules) uint64 {
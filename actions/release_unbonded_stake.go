@@ -0,0 +1,260 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/emission"
+	"github.com/nuklai/nuklaivm/storage"
+)
+
+var _ chain.Action = (*ReleaseUnbondedStake)(nil)
+
+// ReleaseUnbondedStake returns every stake in the actor's unbonding queue that
+// has reached its release block to the actor's balance. If a node ID is given,
+// the stakes the actor took off that validator are released from the
+// validator's unbonding queue instead, once the validator is synced so that
+// they are slashed for any offence it committed while they were staked.
+type ReleaseUnbondedStake struct {
+	NodeID []byte `json:"nodeID"` // Node ID of the validator to release the stake taken off, if any
+}
+
+func (*ReleaseUnbondedStake) GetTypeID() uint8 {
+	return nconsts.ReleaseUnbondedStakeID
+}
+
+func (r *ReleaseUnbondedStake) StateKeys(actor codec.Address, txID ids.ID) []string {
+	if len(r.NodeID) == 0 {
+		return []string{
+			string(storage.BalanceKey(actor, ids.Empty)),
+			string(storage.UnbondingKey(actor)),
+			heightStateKey(),
+		}
+	}
+	// TODO: How to better handle a case where the NodeID is invalid?
+	nodeID, _ := ids.ToNodeID(r.NodeID)
+	return append([]string{
+		string(storage.BalanceKey(actor, ids.Empty)),
+		string(storage.RegisterValidatorStakeKey(nodeID)),
+		string(storage.ValidatorRecordKey(nodeID)),
+		string(storage.ValidatorUnbondingKey(nodeID)),
+	}, emissionStateKeys(txID)...)
+}
+
+func (r *ReleaseUnbondedStake) StateKeysMaxChunks() []uint16 {
+	if len(r.NodeID) == 0 {
+		return []uint16{storage.BalanceChunks, storage.UnbondingChunks, chain.HeightKeyChunks}
+	}
+	return append([]uint16{storage.BalanceChunks, storage.RegisterValidatorStakeChunks, storage.ValidatorRecordChunks, storage.ValidatorUnbondingChunks}, emissionStateKeysMaxChunks()...)
+}
+
+func (*ReleaseUnbondedStake) OutputsWarpMessage() bool {
+	return false
+}
+
+func (r *ReleaseUnbondedStake) Execute(
+	ctx context.Context,
+	rules chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	txID ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	if len(r.NodeID) > 0 {
+		return r.releaseValidatorStake(ctx, rules, mu, timestamp, actor, txID)
+	}
+
+	entries, err := storage.GetUnbonding(ctx, mu, actor)
+	if err != nil {
+		return false, ReleaseUnbondedStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	lastBlockHeight, err := getParentHeight(ctx, mu)
+	if err != nil {
+		return false, ReleaseUnbondedStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	// Release every entry that has matured and keep the rest queued
+	releasedAmount := uint64(0)
+	remaining := make([]*storage.UnbondingEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.ReleaseBlock > lastBlockHeight+1 {
+			remaining = append(remaining, entry)
+			continue
+		}
+		releasedAmount += entry.Amount
+	}
+	if len(remaining) == len(entries) {
+		return false, ReleaseUnbondedStakeComputeUnits, OutputNoUnbondedStake, nil, nil
+	}
+
+	if err := storage.SetUnbonding(ctx, mu, actor, remaining); err != nil {
+		return false, ReleaseUnbondedStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.AddBalance(ctx, mu, actor, ids.Empty, releasedAmount, true); err != nil {
+		return false, ReleaseUnbondedStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	sr := &ReleaseUnbondedStakeResult{releasedAmount}
+	output, err := sr.Marshal()
+	if err != nil {
+		return false, ReleaseUnbondedStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, ReleaseUnbondedStakeComputeUnits, output, nil, nil
+}
+
+// releaseValidatorStake syncs the validator and releases the stakes of the
+// actor in its unbonding queue that have reached their release block.
+func (r *ReleaseUnbondedStake) releaseValidatorStake(
+	ctx context.Context,
+	rules chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	txID ids.ID,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	nodeID, err := ids.ToNodeID(r.NodeID)
+	if err != nil {
+		return false, ReleaseUnbondedStakeComputeUnits, OutputInvalidNodeID, nil, nil
+	}
+
+	// Settle the offences of the validator first, which slash its unbonding
+	// queue
+	e, err := loadEmission(ctx, rules, mu, timestamp, txID)
+	if err != nil {
+		return false, ReleaseUnbondedStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	stake, err := getStake(ctx, mu, nodeID)
+	if err != nil {
+		return false, ReleaseUnbondedStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	record, exists, err := e.getValidator(nodeID, stake)
+	if err != nil {
+		return false, ReleaseUnbondedStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if exists {
+		if err := e.setValidator(nodeID, record); err != nil {
+			return false, ReleaseUnbondedStakeComputeUnits, utils.ErrBytes(err), nil, nil
+		}
+	}
+	if err := e.store(); err != nil {
+		return false, ReleaseUnbondedStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	// Release every entry of the actor that has matured and keep the rest
+	// queued
+	entries, err := storage.GetValidatorUnbonding(ctx, mu, nodeID)
+	if err != nil {
+		return false, ReleaseUnbondedStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	releasedAmount := uint64(0)
+	remaining := make([]*storage.ValidatorUnbondingEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Owner != actor || entry.ReleaseBlock > e.parentHeight+1 {
+			remaining = append(remaining, entry)
+			continue
+		}
+		releasedAmount += entry.Amount
+	}
+	if len(remaining) == len(entries) {
+		return false, ReleaseUnbondedStakeComputeUnits, OutputNoUnbondedStake, nil, nil
+	}
+
+	if err := storage.SetValidatorUnbonding(ctx, mu, nodeID, remaining); err != nil {
+		return false, ReleaseUnbondedStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.AddBalance(ctx, mu, actor, ids.Empty, releasedAmount, true); err != nil {
+		return false, ReleaseUnbondedStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	sr := &ReleaseUnbondedStakeResult{releasedAmount}
+	output, err := sr.Marshal()
+	if err != nil {
+		return false, ReleaseUnbondedStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	output, err = e.output(output)
+	if err != nil {
+		return false, ReleaseUnbondedStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, ReleaseUnbondedStakeComputeUnits, output, nil, nil
+}
+
+func (*ReleaseUnbondedStake) MaxComputeUnits(chain.Rules) uint64 {
+	return ReleaseUnbondedStakeComputeUnits
+}
+
+func (r *ReleaseUnbondedStake) Size() int {
+	return codec.BytesLen(r.NodeID)
+}
+
+func (r *ReleaseUnbondedStake) Marshal(p *codec.Packer) {
+	p.PackBytes(r.NodeID)
+}
+
+func UnmarshalReleaseUnbondedStake(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var release ReleaseUnbondedStake
+	p.UnpackBytes(hconsts.NodeIDLen, false, &release.NodeID)
+	return &release, p.Err()
+}
+
+func (*ReleaseUnbondedStake) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+type ReleaseUnbondedStakeResult struct {
+	ReleasedAmount uint64
+}
+
+func UnmarshalReleaseUnbondedStakeResult(b []byte) (*ReleaseUnbondedStakeResult, error) {
+	p := codec.NewReader(b, hconsts.Uint64Len)
+	var result ReleaseUnbondedStakeResult
+	result.ReleasedAmount = p.UnpackUint64(false)
+	return &result, p.Err()
+}
+
+func (s *ReleaseUnbondedStakeResult) Marshal() ([]byte, error) {
+	p := codec.NewWriter(hconsts.Uint64Len, hconsts.Uint64Len)
+	p.PackUint64(s.ReleasedAmount)
+	return p.Bytes(), p.Err()
+}
+
+// unbondStake moves [amount] of stake taken off [nodeID] in the block at
+// [blockHeight] to the unbonding queue of [owner], or straight to its balance
+// if there is no unbonding period.
+func unbondStake(ctx context.Context, rules chain.Rules, mu state.Mutable, owner codec.Address, nodeID ids.NodeID, amount uint64, blockHeight uint64) error {
+	unbondingPeriod := emission.GetStakingConfig(rules).UnbondingPeriod
+	if unbondingPeriod == 0 {
+		return storage.AddBalance(ctx, mu, owner, ids.Empty, amount, true)
+	}
+	if amount == 0 {
+		return nil
+	}
+	return storage.AddUnbonding(ctx, mu, owner, nodeID, amount, blockHeight+unbondingPeriod)
+}
+
+// unbondValidatorStake moves [amount] of stake taken off [nodeID] by its
+// [owner] in the block at [blockHeight] to the unbonding queue of the
+// validator, where it can still be slashed, or straight to the balance of
+// [owner] if there is no unbonding period.
+func unbondValidatorStake(ctx context.Context, rules chain.Rules, mu state.Mutable, owner codec.Address, nodeID ids.NodeID, amount uint64, blockHeight uint64) error {
+	unbondingPeriod := emission.GetStakingConfig(rules).UnbondingPeriod
+	if unbondingPeriod == 0 {
+		return storage.AddBalance(ctx, mu, owner, ids.Empty, amount, true)
+	}
+	if amount == 0 {
+		return nil
+	}
+	return storage.AddValidatorUnbonding(ctx, mu, nodeID, owner, amount, blockHeight, blockHeight+unbondingPeriod)
+}
@@ -0,0 +1,125 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/storage"
+)
+
+var _ chain.Action = (*TransferFrom)(nil)
+
+type TransferFrom struct {
+	// From is the owner of the balance the actor was approved to spend.
+	From codec.Address `json:"from"`
+
+	// To is the recipient of the [Value].
+	To codec.Address `json:"to"`
+
+	// Asset to transfer to [To].
+	Asset ids.ID `json:"asset"`
+
+	// Amount are transferred to [To] and deducted from the allowance.
+	Value uint64 `json:"value"`
+}
+
+func (*TransferFrom) GetTypeID() uint8 {
+	return nconsts.TransferFromID
+}
+
+func (t *TransferFrom) StateKeys(actor codec.Address, _ ids.ID) []string {
+	return []string{
+		string(storage.AllowanceKey(t.From, actor, t.Asset)),
+		string(storage.BalanceKey(t.From, t.Asset)),
+		string(storage.BalanceKey(t.To, t.Asset)),
+		heightStateKey(),
+	}
+}
+
+func (*TransferFrom) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.AllowanceChunks, storage.BalanceChunks, storage.BalanceChunks, chain.HeightKeyChunks}
+}
+
+func (*TransferFrom) OutputsWarpMessage() bool {
+	return false
+}
+
+func (t *TransferFrom) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	if t.Value == 0 {
+		return false, TransferFromComputeUnits, OutputValueZero, nil, nil
+	}
+	allowance, expiryBlock, err := storage.GetAllowance(ctx, mu, t.From, actor, t.Asset)
+	if err != nil {
+		return false, TransferFromComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if expiryBlock != 0 {
+		parentHeight, err := getParentHeight(ctx, mu)
+		if err != nil {
+			return false, TransferFromComputeUnits, utils.ErrBytes(err), nil, nil
+		}
+		if parentHeight+1 >= expiryBlock {
+			return false, TransferFromComputeUnits, OutputAllowanceExpired, nil, nil
+		}
+	}
+	if allowance < t.Value {
+		return false, TransferFromComputeUnits, OutputAllowanceExceeded, nil, nil
+	}
+	if err := storage.SetAllowance(ctx, mu, t.From, actor, t.Asset, allowance-t.Value, expiryBlock); err != nil {
+		return false, TransferFromComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.SubBalance(ctx, mu, t.From, t.Asset, t.Value); err != nil {
+		return false, TransferFromComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.AddBalance(ctx, mu, t.To, t.Asset, t.Value, true); err != nil {
+		return false, TransferFromComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, TransferFromComputeUnits, nil, nil, nil
+}
+
+func (*TransferFrom) MaxComputeUnits(chain.Rules) uint64 {
+	return TransferFromComputeUnits
+}
+
+func (*TransferFrom) Size() int {
+	return 2*codec.AddressLen + hconsts.IDLen + hconsts.Uint64Len
+}
+
+func (t *TransferFrom) Marshal(p *codec.Packer) {
+	p.PackAddress(t.From)
+	p.PackAddress(t.To)
+	p.PackID(t.Asset)
+	p.PackUint64(t.Value)
+}
+
+func UnmarshalTransferFrom(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var transfer TransferFrom
+	p.UnpackAddress(&transfer.From)
+	p.UnpackAddress(&transfer.To)
+	p.UnpackID(false, &transfer.Asset) // empty ID is the native asset
+	transfer.Value = p.UnpackUint64(true)
+	return &transfer, p.Err()
+}
+
+func (*TransferFrom) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
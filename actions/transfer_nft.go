@@ -0,0 +1,111 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/storage"
+)
+
+var _ chain.Action = (*TransferNFT)(nil)
+
+type TransferNFT struct {
+	// Collection is the [TxID] that created the collection.
+	Collection ids.ID `json:"collection"`
+
+	// ID of the NFT within its collection.
+	ID uint64 `json:"id"`
+
+	// To is the recipient of the NFT.
+	To codec.Address `json:"to"`
+}
+
+func (*TransferNFT) GetTypeID() uint8 {
+	return nconsts.TransferNFTID
+}
+
+func (t *TransferNFT) StateKeys(actor codec.Address, _ ids.ID) []string {
+	return []string{
+		string(storage.NFTKey(t.Collection, t.ID)),
+		string(storage.NFTOwnerKey(actor, t.Collection, t.ID)),
+		string(storage.NFTOwnerKey(t.To, t.Collection, t.ID)),
+	}
+}
+
+func (*TransferNFT) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.NFTChunks, storage.NFTOwnerChunks, storage.NFTOwnerChunks}
+}
+
+func (*TransferNFT) OutputsWarpMessage() bool {
+	return false
+}
+
+func (t *TransferNFT) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	exists, owner, metadata, err := storage.GetNFT(ctx, mu, t.Collection, t.ID)
+	if err != nil {
+		return false, TransferNFTComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, TransferNFTComputeUnits, OutputNFTMissing, nil, nil
+	}
+	if owner != actor {
+		return false, TransferNFTComputeUnits, OutputWrongOwner, nil, nil
+	}
+	if err := storage.SetNFT(ctx, mu, t.Collection, t.ID, t.To, metadata); err != nil {
+		return false, TransferNFTComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.RemoveNFTFromOwner(ctx, mu, actor, t.Collection, t.ID); err != nil {
+		return false, TransferNFTComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.AddNFTToOwner(ctx, mu, t.To, t.Collection, t.ID); err != nil {
+		return false, TransferNFTComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, TransferNFTComputeUnits, nil, nil, nil
+}
+
+func (*TransferNFT) MaxComputeUnits(chain.Rules) uint64 {
+	return TransferNFTComputeUnits
+}
+
+func (*TransferNFT) Size() int {
+	return hconsts.IDLen + hconsts.Uint64Len + codec.AddressLen
+}
+
+func (t *TransferNFT) Marshal(p *codec.Packer) {
+	p.PackID(t.Collection)
+	p.PackUint64(t.ID)
+	p.PackAddress(t.To)
+}
+
+func UnmarshalTransferNFT(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var transfer TransferNFT
+	p.UnpackID(true, &transfer.Collection)
+	transfer.ID = p.UnpackUint64(false)
+	p.UnpackAddress(&transfer.To)
+	return &transfer, p.Err()
+}
+
+func (*TransferNFT) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -5,6 +5,7 @@ package actions
 
 import (
 	"context"
+	"errors"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
@@ -15,7 +16,6 @@ import (
 	"github.com/ava-labs/hypersdk/utils"
 
 	nconsts "github.com/nuklai/nuklaivm/consts"
-	"github.com/nuklai/nuklaivm/emission"
 	"github.com/nuklai/nuklaivm/storage"
 )
 
@@ -30,18 +30,20 @@ func (*UndelegateUserStake) GetTypeID() uint8 {
 	return nconsts.UndelegateUserStakeID
 }
 
-func (u *UndelegateUserStake) StateKeys(actor codec.Address, _ ids.ID) []string {
+func (u *UndelegateUserStake) StateKeys(actor codec.Address, txID ids.ID) []string {
 	// TODO: How to better handle a case where the NodeID is invalid?
 	nodeID, _ := ids.ToNodeID(u.NodeID)
-	return []string{
+	return append([]string{
 		string(storage.BalanceKey(actor, ids.Empty)),
 		string(storage.BalanceKey(u.RewardAddress, ids.Empty)),
 		string(storage.DelegateUserStakeKey(actor, nodeID)),
-	}
+		string(storage.RegisterValidatorStakeKey(nodeID)),
+		string(storage.UnbondingKey(actor)),
+	}, append(validatorStateKeys(nodeID), emissionStateKeys(txID)...)...)
 }
 
 func (*UndelegateUserStake) StateKeysMaxChunks() []uint16 {
-	return []uint16{storage.BalanceChunks, storage.DelegateUserStakeChunks}
+	return append([]uint16{storage.BalanceChunks, storage.BalanceChunks, storage.DelegateUserStakeChunks, storage.RegisterValidatorStakeChunks, storage.UnbondingChunks}, append(validatorStateKeysMaxChunks(), emissionStateKeysMaxChunks()...)...)
 }
 
 func (*UndelegateUserStake) OutputsWarpMessage() bool {
@@ -50,11 +52,11 @@ func (*UndelegateUserStake) OutputsWarpMessage() bool {
 
 func (u *UndelegateUserStake) Execute(
 	ctx context.Context,
-	_ chain.Rules,
+	rules chain.Rules,
 	mu state.Mutable,
-	_ int64,
+	timestamp int64,
 	actor codec.Address,
-	_ ids.ID,
+	txID ids.ID,
 	_ bool,
 ) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
 	nodeID, err := ids.ToNodeID(u.NodeID)
@@ -62,7 +64,7 @@ func (u *UndelegateUserStake) Execute(
 		return false, UndelegateUserStakeComputeUnits, OutputInvalidNodeID, nil, nil
 	}
 
-	exists, _, stakeEndBlock, stakedAmount, _, ownerAddress, _ := storage.GetDelegateUserStake(ctx, mu, actor, nodeID)
+	exists, stakeStartBlock, stakeEndBlock, stakedAmount, _, ownerAddress, rewardWeight, rewardIndex, _, _ := storage.GetDelegateUserStake(ctx, mu, actor, nodeID)
 	if !exists {
 		return false, UndelegateUserStakeComputeUnits, OutputStakeMissing, nil, nil
 	}
@@ -70,27 +72,61 @@ func (u *UndelegateUserStake) Execute(
 		return false, UndelegateUserStakeComputeUnits, OutputUnauthorized, nil, nil
 	}
 
-	// Get the emission instance
-	emissionInstance := emission.GetEmission()
+	e, err := loadEmission(ctx, rules, mu, timestamp, txID)
+	if err != nil {
+		return false, UndelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	lastBlockHeight := e.parentHeight
 
-	// Check that lastBlockHeight is after stakeEndBlock
-	if emissionInstance.GetLastAcceptedBlockHeight() < stakeEndBlock {
+	// Check that lastBlockHeight is after stakeEndBlock, unless the validator
+	// already withdrew its stake, which settled the delegation
+	stake, err := getStake(ctx, mu, nodeID)
+	if err != nil {
+		return false, UndelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if stake != nil && lastBlockHeight < stakeEndBlock {
 		return false, UndelegateUserStakeComputeUnits, OutputStakeNotEnded, nil, nil
 	}
 
-	// Undelegate in Emission Balancer
-	rewardAmount, err := emissionInstance.UndelegateUserStake(nodeID, actor)
+	// Undelegate in Emission Balancer. Delegations left from a stake the
+	// validator withdrew after its record was removed have nothing left to
+	// earn.
+	record, exists, err := e.getValidator(nodeID, stake)
 	if err != nil {
 		return false, UndelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
 	}
-	if err := storage.AddBalance(ctx, mu, u.RewardAddress, ids.Empty, rewardAmount, true); err != nil {
+	rewardAmount := uint64(0)
+	if exists {
+		rewardAmount, _, _ = record.SettleDelegation(e.header, rewardWeight, rewardIndex, stakeStartBlock, e.epochTracker.EpochLength)
+		record.Undelegate(stakedAmount, rewardWeight, stakeStartBlock)
+		if err := e.updateRegistration(nodeID, record, stake); err != nil {
+			return false, UndelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+		}
+		// The record of a validator that withdrew its stake goes with its
+		// last delegation
+		if stake == nil && record.Close(e.header) {
+			err = storage.DeleteValidatorRecord(ctx, mu, nodeID)
+		} else {
+			err = e.setValidator(nodeID, record)
+		}
+		if err != nil {
+			return false, UndelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+		}
+	}
+	if err := e.store(); err != nil {
+		return false, UndelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.AddNAI(ctx, mu, u.RewardAddress, rewardAmount); err != nil {
 		return false, UndelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
 	}
 
 	if err := storage.DeleteDelegateUserStake(ctx, mu, ownerAddress, nodeID); err != nil {
 		return false, UndelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
 	}
-	if err := storage.AddBalance(ctx, mu, ownerAddress, ids.Empty, stakedAmount, true); err != nil {
+	if err := unbondStake(ctx, rules, mu, ownerAddress, nodeID, stakedAmount, lastBlockHeight+1); err != nil {
+		if errors.Is(err, storage.ErrUnbondingQueueFull) {
+			return false, UndelegateUserStakeComputeUnits, OutputUnbondingQueueFull, nil, nil
+		}
 		return false, UndelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
 	}
 
@@ -99,6 +135,10 @@ func (u *UndelegateUserStake) Execute(
 	if err != nil {
 		return false, UndelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
 	}
+	output, err = e.output(output)
+	if err != nil {
+		return false, UndelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
 	return true, UndelegateUserStakeComputeUnits, output, nil, nil
 }
 
@@ -0,0 +1,126 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/storage"
+)
+
+var _ chain.Action = (*UpdateAsset)(nil)
+
+// UpdateAsset changes the metadata and the owner of an asset and can freeze
+// its minting. It must be sent by the current owner. Setting [Owner] to
+// [codec.EmptyAddress] renounces the ownership for good, after which nobody can
+// mint or update the asset.
+type UpdateAsset struct {
+	// Asset is the [TxID] that created the asset.
+	Asset ids.ID `json:"asset"`
+
+	// Metadata is the new metadata of the asset. It is kept as is if empty.
+	Metadata []byte `json:"metadata"`
+
+	// Owner is the new owner of the asset. It must be set to the current owner
+	// to keep the ownership.
+	Owner codec.Address `json:"owner"`
+
+	// FreezeMint disables minting of the asset permanently. A frozen asset
+	// cannot be unfrozen.
+	FreezeMint bool `json:"freezeMint"`
+}
+
+func (*UpdateAsset) GetTypeID() uint8 {
+	return nconsts.UpdateAssetID
+}
+
+func (u *UpdateAsset) StateKeys(codec.Address, ids.ID) []string {
+	return []string{
+		string(storage.AssetKey(u.Asset)),
+	}
+}
+
+func (*UpdateAsset) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.AssetChunks}
+}
+
+func (*UpdateAsset) OutputsWarpMessage() bool {
+	return false
+}
+
+func (u *UpdateAsset) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	if u.Asset == ids.Empty {
+		return false, UpdateAssetComputeUnits, OutputCannotUpdateNative, nil, nil
+	}
+	if len(u.Metadata) > MaxMetadataSize {
+		return false, UpdateAssetComputeUnits, OutputMetadataTooLarge, nil, nil
+	}
+	exists, symbol, decimals, metadata, supply, maxSupply, owner, frozen, isWarp, err := storage.GetAsset(ctx, mu, u.Asset)
+	if err != nil {
+		return false, UpdateAssetComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, UpdateAssetComputeUnits, OutputAssetMissing, nil, nil
+	}
+	if isWarp {
+		return false, UpdateAssetComputeUnits, OutputWarpAsset, nil, nil
+	}
+	if owner != actor {
+		return false, UpdateAssetComputeUnits, OutputWrongOwner, nil, nil
+	}
+	if len(u.Metadata) > 0 {
+		metadata = u.Metadata
+	}
+	if err := storage.SetAsset(ctx, mu, u.Asset, symbol, decimals, metadata, supply, maxSupply, u.Owner, frozen || u.FreezeMint, isWarp); err != nil {
+		return false, UpdateAssetComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, UpdateAssetComputeUnits, nil, nil, nil
+}
+
+func (*UpdateAsset) MaxComputeUnits(chain.Rules) uint64 {
+	return UpdateAssetComputeUnits
+}
+
+func (u *UpdateAsset) Size() int {
+	return hconsts.IDLen + codec.BytesLen(u.Metadata) + codec.AddressLen + hconsts.BoolLen
+}
+
+func (u *UpdateAsset) Marshal(p *codec.Packer) {
+	p.PackID(u.Asset)
+	p.PackBytes(u.Metadata)
+	p.PackAddress(u.Owner)
+	p.PackBool(u.FreezeMint)
+}
+
+func UnmarshalUpdateAsset(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var update UpdateAsset
+	p.UnpackID(true, &update.Asset)
+	p.UnpackBytes(MaxMetadataSize, false, &update.Metadata)
+	p.UnpackAddress(&update.Owner)
+	update.FreezeMint = p.UnpackBool()
+	return &update, p.Err()
+}
+
+func (*UpdateAsset) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -0,0 +1,213 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/emission"
+	"github.com/nuklai/nuklaivm/storage"
+)
+
+var _ chain.Action = (*UpdateValidatorStake)(nil)
+
+// UpdateValidatorStake changes the stake end block, delegation fee rate and
+// reward address of a registered validator. It must be sent by the same BLS
+// signer key that registered the stake. A fee rate increase only takes effect
+// after the fee rate notice period.
+type UpdateValidatorStake struct {
+	NodeID            []byte        `json:"nodeID"`            // Node ID of the validator
+	StakeEndBlock     uint64        `json:"stakeEndBlock"`     // New end block of the stake
+	DelegationFeeRate uint64        `json:"delegationFeeRate"` // New delegation fee rate
+	RewardAddress     codec.Address `json:"rewardAddress"`     // New address to receive rewards
+}
+
+func (*UpdateValidatorStake) GetTypeID() uint8 {
+	return nconsts.UpdateValidatorStakeID
+}
+
+func (u *UpdateValidatorStake) StateKeys(_ codec.Address, txID ids.ID) []string {
+	// TODO: How to better handle a case where the NodeID is invalid?
+	nodeID, _ := ids.ToNodeID(u.NodeID)
+	return append([]string{
+		string(storage.RegisterValidatorStakeKey(nodeID)),
+	}, append(validatorStateKeys(nodeID), emissionStateKeys(txID)...)...)
+}
+
+func (*UpdateValidatorStake) StateKeysMaxChunks() []uint16 {
+	return append([]uint16{storage.RegisterValidatorStakeChunks}, append(validatorStateKeysMaxChunks(), emissionStateKeysMaxChunks()...)...)
+}
+
+func (*UpdateValidatorStake) OutputsWarpMessage() bool {
+	return false
+}
+
+func (u *UpdateValidatorStake) Execute(
+	ctx context.Context,
+	rules chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	txID ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	nodeID, err := ids.ToNodeID(u.NodeID)
+	if err != nil {
+		return false, UpdateValidatorStakeComputeUnits, OutputInvalidNodeID, nil, nil
+	}
+
+	// The owner of the stake is the BLS signer key that registered it
+	exists, stakeStartBlock, stakeEndBlock, _, delegationFeeRate, pendingDelegationFeeRate, feeRateEffectiveBlock, _, ownerAddress, _ := storage.GetRegisterValidatorStake(ctx, mu, nodeID)
+	if !exists {
+		return false, UpdateValidatorStakeComputeUnits, OutputValidatorNotYetRegistered, nil, nil
+	}
+	if ownerAddress != actor {
+		return false, UpdateValidatorStakeComputeUnits, OutputUnauthorized, nil, nil
+	}
+
+	stakingConfig := emission.GetStakingConfig(rules)
+
+	e, err := loadEmission(ctx, rules, mu, timestamp, txID)
+	if err != nil {
+		return false, UpdateValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	lastBlockHeight := e.parentHeight
+
+	// The stake can only be extended while it has not ended, and within the
+	// maximum stake duration
+	if u.StakeEndBlock != stakeEndBlock {
+		if lastBlockHeight >= stakeEndBlock {
+			return false, UpdateValidatorStakeComputeUnits, OutputStakeEnded, nil, nil
+		}
+		if u.StakeEndBlock < stakeEndBlock {
+			return false, UpdateValidatorStakeComputeUnits, OutputInvalidStakeEndBlock, nil, nil
+		}
+		if u.StakeEndBlock-stakeStartBlock > stakingConfig.MaxValidatorStakeDuration {
+			return false, UpdateValidatorStakeComputeUnits, OutputInvalidStakeDuration, nil, nil
+		}
+		if _, ok := stakingConfig.LockupMultiplier(u.StakeEndBlock-stakeStartBlock, stakingConfig.MaxValidatorStakeDuration); !ok {
+			return false, UpdateValidatorStakeComputeUnits, OutputLockupPeriodInvalid, nil, nil
+		}
+	}
+
+	// Check if the delegation fee rate is valid
+	if u.DelegationFeeRate < stakingConfig.MinDelegationFee || u.DelegationFeeRate > 100 {
+		return false, UpdateValidatorStakeComputeUnits, OutputInvalidDelegationFeeRate, nil, nil
+	}
+
+	// An increase is kept pending in the stake record until its notice period
+	// is over, and the rate in effect until then is left untouched. A
+	// decrease takes effect right away and drops any pending increase.
+	delegationFeeRate, pendingDelegationFeeRate, feeRateEffectiveBlock = emission.EffectiveDelegationFeeRate(delegationFeeRate, pendingDelegationFeeRate, feeRateEffectiveBlock, lastBlockHeight)
+	if u.DelegationFeeRate > delegationFeeRate && stakingConfig.FeeRateNoticePeriod > 0 {
+		if u.DelegationFeeRate != pendingDelegationFeeRate {
+			pendingDelegationFeeRate = u.DelegationFeeRate
+			feeRateEffectiveBlock = lastBlockHeight + stakingConfig.FeeRateNoticePeriod
+		}
+	} else {
+		delegationFeeRate = u.DelegationFeeRate
+		pendingDelegationFeeRate, feeRateEffectiveBlock = 0, 0
+	}
+	effectiveBlock := lastBlockHeight
+	if feeRateEffectiveBlock > 0 {
+		effectiveBlock = feeRateEffectiveBlock
+	}
+
+	// Update in Emission Balancer. The record is synced with the stake as it
+	// was before the update.
+	stake, err := getStake(ctx, mu, nodeID)
+	if err != nil {
+		return false, UpdateValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	record, found, err := e.getValidator(nodeID, stake)
+	if err != nil {
+		return false, UpdateValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !found {
+		return false, UpdateValidatorStakeComputeUnits, utils.ErrBytes(emission.ErrValidatorNotFound), nil, nil
+	}
+	stake.StakeEndBlock = u.StakeEndBlock
+	stake.DelegationFeeRate = delegationFeeRate
+	stake.PendingDelegationFeeRate = pendingDelegationFeeRate
+	stake.FeeRateEffectiveBlock = feeRateEffectiveBlock
+	if err := e.updateRegistration(nodeID, record, stake); err != nil {
+		return false, UpdateValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := e.setValidator(nodeID, record); err != nil {
+		return false, UpdateValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := e.store(); err != nil {
+		return false, UpdateValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	if err := storage.SetRegisterValidatorStake(ctx, mu, nodeID, stakeStartBlock, u.StakeEndBlock, stake.StakedAmount, delegationFeeRate, pendingDelegationFeeRate, feeRateEffectiveBlock, u.RewardAddress, ownerAddress); err != nil {
+		return false, UpdateValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	sr := &UpdateValidatorStakeResult{effectiveBlock}
+	output, err := sr.Marshal()
+	if err != nil {
+		return false, UpdateValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	output, err = e.output(output)
+	if err != nil {
+		return false, UpdateValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, UpdateValidatorStakeComputeUnits, output, nil, nil
+}
+
+func (*UpdateValidatorStake) MaxComputeUnits(chain.Rules) uint64 {
+	return UpdateValidatorStakeComputeUnits
+}
+
+func (*UpdateValidatorStake) Size() int {
+	return hconsts.NodeIDLen + 2*hconsts.Uint64Len + codec.AddressLen
+}
+
+func (u *UpdateValidatorStake) Marshal(p *codec.Packer) {
+	p.PackBytes(u.NodeID)
+	p.PackUint64(u.StakeEndBlock)
+	p.PackUint64(u.DelegationFeeRate)
+	p.PackAddress(u.RewardAddress)
+}
+
+func UnmarshalUpdateValidatorStake(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var update UpdateValidatorStake
+	p.UnpackBytes(hconsts.NodeIDLen, true, &update.NodeID)
+	update.StakeEndBlock = p.UnpackUint64(true)
+	update.DelegationFeeRate = p.UnpackUint64(true)
+	p.UnpackAddress(&update.RewardAddress)
+	return &update, p.Err()
+}
+
+func (*UpdateValidatorStake) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+type UpdateValidatorStakeResult struct {
+	FeeRateEffectiveBlock uint64 // Block height at which the new delegation fee rate takes effect
+}
+
+func UnmarshalUpdateValidatorStakeResult(b []byte) (*UpdateValidatorStakeResult, error) {
+	p := codec.NewReader(b, hconsts.Uint64Len)
+	var result UpdateValidatorStakeResult
+	result.FeeRateEffectiveBlock = p.UnpackUint64(false)
+	return &result, p.Err()
+}
+
+func (s *UpdateValidatorStakeResult) Marshal() ([]byte, error) {
+	p := codec.NewWriter(hconsts.Uint64Len, hconsts.Uint64Len)
+	p.PackUint64(s.FeeRateEffectiveBlock)
+	return p.Bytes(), p.Err()
+}
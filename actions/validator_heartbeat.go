@@ -0,0 +1,156 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/crypto/bls"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+
+	"github.com/nuklai/nuklaivm/auth"
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/emission"
+	"github.com/nuklai/nuklaivm/storage"
+)
+
+var _ chain.Action = (*ValidatorHeartbeat)(nil)
+
+// ValidatorHeartbeat marks a staked validator as online. It must be sent by
+// the validator's node signer key at least once every heartbeat interval for
+// the validator to reach the minimum uptime. The first heartbeat of every
+// epoch also registers the validator for the rewards of the next epoch
+// boundary, so validators send heartbeats even when uptime is not tracked.
+type ValidatorHeartbeat struct {
+	NodeID []byte `json:"nodeID"` // Node ID of the validator
+}
+
+func (*ValidatorHeartbeat) GetTypeID() uint8 {
+	return nconsts.ValidatorHeartbeatID
+}
+
+func (v *ValidatorHeartbeat) StateKeys(_ codec.Address, txID ids.ID) []string {
+	nodeID, _ := ids.ToNodeID(v.NodeID)
+	return append([]string{
+		string(storage.RegisterValidatorStakeKey(nodeID)),
+	}, append(validatorStateKeys(nodeID), emissionStateKeys(txID)...)...)
+}
+
+func (*ValidatorHeartbeat) StateKeysMaxChunks() []uint16 {
+	return append([]uint16{storage.RegisterValidatorStakeChunks}, append(validatorStateKeysMaxChunks(), emissionStateKeysMaxChunks()...)...)
+}
+
+func (*ValidatorHeartbeat) OutputsWarpMessage() bool {
+	return false
+}
+
+func (v *ValidatorHeartbeat) Execute(
+	ctx context.Context,
+	rules chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	txID ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	// Check if it's a valid nodeID
+	nodeID, err := ids.ToNodeID(v.NodeID)
+	if err != nil {
+		return false, ValidatorHeartbeatComputeUnits, OutputInvalidNodeID, nil, nil
+	}
+
+	// Check if the validator is registered for staking
+	exists, _, _, _, _, _, _, _, _, _ := storage.GetRegisterValidatorStake(ctx, mu, nodeID)
+	if !exists {
+		return false, ValidatorHeartbeatComputeUnits, OutputValidatorNotYetRegistered, nil, nil
+	}
+
+	// Get the emission instance
+	emissionInstance := emission.GetEmission()
+
+	// Only the node signer key of the validator can send heartbeats
+	currentValidators, err := emissionInstance.GetAllValidators(ctx)
+	if err != nil {
+		return false, ValidatorHeartbeatComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	isValidatorSigner := false
+	for _, validator := range currentValidators {
+		if validator.NodeID != nodeID {
+			continue
+		}
+		publicKey, err := bls.PublicKeyFromBytes(validator.PublicKey)
+		if err != nil {
+			return false, ValidatorHeartbeatComputeUnits, utils.ErrBytes(err), nil, nil
+		}
+		signer := auth.NewBLSAddress(publicKey)
+		isValidatorSigner = signer == actor
+		break
+	}
+	if !isValidatorSigner {
+		return false, ValidatorHeartbeatComputeUnits, OutputUnauthorized, nil, nil
+	}
+
+	// Record the heartbeat in Emission Balancer and register the validator
+	// for the next epoch boundary
+	e, err := loadEmission(ctx, rules, mu, timestamp, txID)
+	if err != nil {
+		return false, ValidatorHeartbeatComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	stake, err := getStake(ctx, mu, nodeID)
+	if err != nil {
+		return false, ValidatorHeartbeatComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	record, found, err := e.getValidator(nodeID, stake)
+	if err != nil {
+		return false, ValidatorHeartbeatComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !found {
+		return false, ValidatorHeartbeatComputeUnits, utils.ErrBytes(emission.ErrValidatorNotFound), nil, nil
+	}
+	blockHeight := e.parentHeight + 1
+	record.RecordHeartbeat(blockHeight, e.stakingConfig, e.epochTracker)
+	if err := record.Register(nodeID, stake, blockHeight, e.header, e.loadActiveSet, e.stakingConfig, e.epochTracker); err != nil {
+		return false, ValidatorHeartbeatComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := e.setValidator(nodeID, record); err != nil {
+		return false, ValidatorHeartbeatComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := e.store(); err != nil {
+		return false, ValidatorHeartbeatComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	output, err := e.output(nil)
+	if err != nil {
+		return false, ValidatorHeartbeatComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, ValidatorHeartbeatComputeUnits, output, nil, nil
+}
+
+func (*ValidatorHeartbeat) MaxComputeUnits(chain.Rules) uint64 {
+	return ValidatorHeartbeatComputeUnits
+}
+
+func (*ValidatorHeartbeat) Size() int {
+	return hconsts.NodeIDLen
+}
+
+func (v *ValidatorHeartbeat) Marshal(p *codec.Packer) {
+	p.PackBytes(v.NodeID)
+}
+
+func UnmarshalValidatorHeartbeat(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var heartbeat ValidatorHeartbeat
+	p.UnpackBytes(hconsts.NodeIDLen, true, &heartbeat.NodeID)
+	return &heartbeat, p.Err()
+}
+
+func (*ValidatorHeartbeat) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -5,6 +5,7 @@ package actions
 
 import (
 	"context"
+	"errors"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
@@ -30,18 +31,18 @@ func (*WithdrawValidatorStake) GetTypeID() uint8 {
 	return nconsts.WithdrawValidatorStakeID
 }
 
-func (u *WithdrawValidatorStake) StateKeys(actor codec.Address, _ ids.ID) []string {
+func (u *WithdrawValidatorStake) StateKeys(actor codec.Address, txID ids.ID) []string {
 	// TODO: How to better handle a case where the NodeID is invalid?
 	nodeID, _ := ids.ToNodeID(u.NodeID)
-	return []string{
+	return append([]string{
 		string(storage.BalanceKey(actor, ids.Empty)),
 		string(storage.BalanceKey(u.RewardAddress, ids.Empty)),
 		string(storage.RegisterValidatorStakeKey(nodeID)),
-	}
+	}, append(validatorStateKeys(nodeID), emissionStateKeys(txID)...)...)
 }
 
 func (*WithdrawValidatorStake) StateKeysMaxChunks() []uint16 {
-	return []uint16{storage.BalanceChunks, storage.RegisterValidatorStakeChunks}
+	return append([]uint16{storage.BalanceChunks, storage.BalanceChunks, storage.RegisterValidatorStakeChunks}, append(validatorStateKeysMaxChunks(), emissionStateKeysMaxChunks()...)...)
 }
 
 func (*WithdrawValidatorStake) OutputsWarpMessage() bool {
@@ -50,11 +51,11 @@ func (*WithdrawValidatorStake) OutputsWarpMessage() bool {
 
 func (u *WithdrawValidatorStake) Execute(
 	ctx context.Context,
-	_ chain.Rules,
+	rules chain.Rules,
 	mu state.Mutable,
-	_ int64,
+	timestamp int64,
 	actor codec.Address,
-	_ ids.ID,
+	txID ids.ID,
 	_ bool,
 ) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
 	// Check if it's a valid nodeID
@@ -64,7 +65,7 @@ func (u *WithdrawValidatorStake) Execute(
 	}
 
 	// Check if the validator was already registered
-	exists, _, stakeEndBlock, stakedAmount, _, _, ownerAddress, _ := storage.GetRegisterValidatorStake(ctx, mu, nodeID)
+	exists, _, stakeEndBlock, _, _, _, _, _, ownerAddress, _ := storage.GetRegisterValidatorStake(ctx, mu, nodeID)
 	if !exists {
 		return false, WithdrawValidatorStakeComputeUnits, OutputValidatorAlreadyRegistered, nil, nil
 	}
@@ -72,37 +73,69 @@ func (u *WithdrawValidatorStake) Execute(
 		return false, WithdrawValidatorStakeComputeUnits, OutputUnauthorized, nil, nil
 	}
 
-	// Get the emission instance
-	emissionInstance := emission.GetEmission()
+	e, err := loadEmission(ctx, rules, mu, timestamp, txID)
+	if err != nil {
+		return false, WithdrawValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	lastBlockHeight := e.parentHeight
 
-	// Get last accepted block height
-	lastBlockHeight := emissionInstance.GetLastAcceptedBlockHeight()
 	// Check that lastBlockTime is after stakeStartBlock
 	if lastBlockHeight < stakeEndBlock {
 		return false, WithdrawValidatorStakeComputeUnits, OutputStakeNotStarted, nil, nil
 	}
 
 	// Withdraw in Emission Balancer
-	rewardAmount, err := emissionInstance.WithdrawValidatorStake(nodeID)
+	stake, err := getStake(ctx, mu, nodeID)
 	if err != nil {
 		return false, WithdrawValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
 	}
+	record, exists, err := e.getValidator(nodeID, stake)
+	if err != nil {
+		return false, WithdrawValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, WithdrawValidatorStakeComputeUnits, utils.ErrBytes(emission.ErrValidatorNotFound), nil, nil
+	}
+	rewardAmount, err := record.Withdraw(nodeID, lastBlockHeight, e.header, e.loadActiveSet, e.stakingConfig, e.epochTracker)
+	if err != nil {
+		return false, WithdrawValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	// The record is kept until all of the delegations of the validator are
+	// undelegated
+	if record.Close(e.header) {
+		err = storage.DeleteValidatorRecord(ctx, mu, nodeID)
+	} else {
+		err = e.setValidator(nodeID, record)
+	}
+	if err != nil {
+		return false, WithdrawValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := e.store(); err != nil {
+		return false, WithdrawValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
 
-	if err := storage.AddBalance(ctx, mu, u.RewardAddress, ids.Empty, rewardAmount, true); err != nil {
+	if err := storage.AddNAI(ctx, mu, u.RewardAddress, rewardAmount); err != nil {
 		return false, WithdrawValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
 	}
 	if err := storage.DeleteRegisterValidatorStake(ctx, mu, nodeID); err != nil {
 		return false, WithdrawValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
 	}
-	if err := storage.AddBalance(ctx, mu, actor, ids.Empty, stakedAmount, true); err != nil {
+	if err := unbondValidatorStake(ctx, rules, mu, actor, nodeID, stake.StakedAmount, lastBlockHeight+1); err != nil {
+		if errors.Is(err, storage.ErrUnbondingQueueFull) {
+			return false, WithdrawValidatorStakeComputeUnits, OutputUnbondingQueueFull, nil, nil
+		}
 		return false, WithdrawValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
 	}
 
-	sr := &WithdrawStakeResult{stakedAmount, rewardAmount}
+	sr := &WithdrawStakeResult{stake.StakedAmount, rewardAmount}
 	output, err := sr.Marshal()
 	if err != nil {
 		return false, WithdrawValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
 	}
+	output, err = e.output(output)
+	if err != nil {
+		return false, WithdrawValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
 
 	return true, WithdrawValidatorStakeComputeUnits, output, nil, nil
 }
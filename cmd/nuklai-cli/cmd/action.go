@@ -128,6 +128,75 @@ var transferCmd = &cobra.Command{
 	},
 }
 
+var multiTransferCmd = &cobra.Command{
+	Use: "multi-transfer [file]",
+	RunE: func(_ *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return ErrInvalidArgs
+		}
+		ctx := context.Background()
+		_, priv, factory, hcli, hws, ncli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+
+		// Read transfers
+		records, err := readTransfersFile(args[0])
+		if err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			hutils.Outf("{{red}}no transfers in %s{{/}}\n", args[0])
+			return nil
+		}
+		entries, totals, err := parseTransfers(ctx, ncli, records)
+		if err != nil {
+			return err
+		}
+
+		// Check balances
+		for assetID, total := range totals {
+			_, decimals, balance, _, err := handler.GetAssetInfo(ctx, ncli, priv.Address, assetID, true)
+			if balance == 0 || err != nil {
+				return err
+			}
+			hutils.Outf("{{yellow}}total:{{/}} %s %s\n", hutils.FormatBalance(total, decimals), assetID)
+			if total > balance {
+				hutils.Outf("{{red}}insufficient balance{{/}}\n")
+				hutils.Outf("{{red}}exiting...{{/}}\n")
+				return nil
+			}
+		}
+
+		// Each transaction can only hold [actions.MaxMultiTransferEntries]
+		// transfers, so larger files are sent in several transactions.
+		batches := (len(entries) + actions.MaxMultiTransferEntries - 1) / actions.MaxMultiTransferEntries
+		hutils.Outf("{{yellow}}transfers:{{/}} %d {{yellow}}transactions:{{/}} %d\n", len(entries), batches)
+
+		// Confirm action
+		cont, err := handler.Root().PromptContinue()
+		if !cont || err != nil {
+			return err
+		}
+
+		// Generate transactions
+		for start := 0; start < len(entries); start += actions.MaxMultiTransferEntries {
+			end := min(start+actions.MaxMultiTransferEntries, len(entries))
+			success, _, err := sendAndWait(ctx, nil, &actions.MultiTransfer{
+				Transfers: entries[start:end],
+			}, hcli, hws, ncli, factory, true)
+			if err != nil {
+				return err
+			}
+			if !success {
+				hutils.Outf("{{red}}transfers %d to %d failed, later transfers were not sent{{/}}\n", start, end-1)
+				return nil
+			}
+		}
+		return nil
+	},
+}
+
 var createAssetCmd = &cobra.Command{
 	Use: "create-asset",
 	RunE: func(*cobra.Command, []string) error {
@@ -155,6 +224,12 @@ var createAssetCmd = &cobra.Command{
 			return err
 		}
 
+		// Add max supply to token
+		maxSupply, err := handler.Root().PromptAmount("max supply (0 for unlimited)", uint8(decimals), hconsts.MaxUint64, nil)
+		if err != nil {
+			return err
+		}
+
 		// Confirm action
 		cont, err := handler.Root().PromptContinue()
 		if !cont || err != nil {
@@ -163,9 +238,10 @@ var createAssetCmd = &cobra.Command{
 
 		// Generate transaction
 		_, _, err = sendAndWait(ctx, nil, &actions.CreateAsset{
-			Symbol:   []byte(symbol),
-			Decimals: uint8(decimals), // already constrain above to prevent overflow
-			Metadata: []byte(metadata),
+			Symbol:    []byte(symbol),
+			Decimals:  uint8(decimals), // already constrain above to prevent overflow
+			Metadata:  []byte(metadata),
+			MaxSupply: maxSupply,
 		}, hcli, hws, ncli, factory, true)
 		return err
 	},
@@ -185,7 +261,7 @@ var mintAssetCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		exists, symbol, decimals, metadata, supply, owner, warp, err := ncli.Asset(ctx, assetID, false)
+		exists, symbol, decimals, metadata, supply, maxSupply, owner, frozen, warp, err := ncli.Asset(ctx, assetID, false)
 		if err != nil {
 			return err
 		}
@@ -204,12 +280,19 @@ var mintAssetCmd = &cobra.Command{
 			hutils.Outf("{{red}}exiting...{{/}}\n")
 			return nil
 		}
+		if frozen {
+			hutils.Outf("{{red}}minting of %s is frozen{{/}}\n", assetID)
+			hutils.Outf("{{red}}exiting...{{/}}\n")
+			return nil
+		}
 		hutils.Outf(
-			"{{yellow}}symbol:{{/}} %s {{yellow}}decimals:{{/}} %d {{yellow}}metadata:{{/}} %s {{yellow}}supply:{{/}} %d\n",
+			"{{yellow}}symbol:{{/}} %s {{yellow}}decimals:{{/}} %d {{yellow}}metadata:{{/}} %s {{yellow}}supply:{{/}} %d {{yellow}}maxSupply:{{/}} %d {{yellow}}frozen:{{/}} %t\n",
 			string(symbol),
 			decimals,
 			string(metadata),
 			supply,
+			maxSupply,
+			frozen,
 		)
 
 		// Select recipient
@@ -219,7 +302,16 @@ var mintAssetCmd = &cobra.Command{
 		}
 
 		// Select amount
-		amount, err := handler.Root().PromptAmount("amount", decimals, hconsts.MaxUint64-supply, nil)
+		mintable := hconsts.MaxUint64 - supply
+		if maxSupply > 0 {
+			mintable = maxSupply - supply
+		}
+		if mintable == 0 {
+			hutils.Outf("{{red}}%s has reached its max supply{{/}}\n", assetID)
+			hutils.Outf("{{red}}exiting...{{/}}\n")
+			return nil
+		}
+		amount, err := handler.Root().PromptAmount("amount", decimals, mintable, nil)
 		if err != nil {
 			return err
 		}
@@ -240,231 +332,1281 @@ var mintAssetCmd = &cobra.Command{
 	},
 }
 
-func performImport(
-	ctx context.Context,
-	hscli *hrpc.JSONRPCClient,
-	hdcli *hrpc.JSONRPCClient,
-	hws *hrpc.WebSocketClient,
-	ncli *nrpc.JSONRPCClient,
-	exportTxID ids.ID,
-	factory chain.AuthFactory,
-) error {
-	// Select TxID (if not provided)
-	var err error
-	if exportTxID == ids.Empty {
-		exportTxID, err = handler.Root().PromptID("export txID")
+var updateAssetCmd = &cobra.Command{
+	Use: "update-asset",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, priv, factory, hcli, hws, ncli, err := handler.DefaultActor()
 		if err != nil {
 			return err
 		}
-	}
 
-	// Generate warp signature (as long as >= 80% stake)
-	var (
-		msg                     *warp.Message
-		subnetWeight, sigWeight uint64
-	)
-	for ctx.Err() == nil {
-		msg, subnetWeight, sigWeight, err = hscli.GenerateAggregateWarpSignature(ctx, exportTxID)
-		if sigWeight >= (subnetWeight*4)/5 && err == nil {
-			break
-		}
-		if err == nil {
-			hutils.Outf(
-				"{{yellow}}waiting for signature weight:{{/}} %d {{yellow}}observed:{{/}} %d\n",
-				subnetWeight,
-				sigWeight,
-			)
-		} else {
-			hutils.Outf("{{red}}encountered error:{{/}} %v\n", err)
+		// Select token to update
+		assetID, err := handler.Root().PromptAsset("assetID", false)
+		if err != nil {
+			return err
 		}
-		cont, err := handler.Root().PromptBool("try again")
+		exists, symbol, decimals, metadata, supply, maxSupply, owner, frozen, warp, err := ncli.Asset(ctx, assetID, false)
 		if err != nil {
 			return err
 		}
-		if !cont {
+		if !exists {
+			hutils.Outf("{{red}}%s does not exist{{/}}\n", assetID)
 			hutils.Outf("{{red}}exiting...{{/}}\n")
 			return nil
 		}
-	}
-	if ctx.Err() != nil {
-		return ctx.Err()
-	}
-	wt, err := actions.UnmarshalWarpTransfer(msg.UnsignedMessage.Payload)
-	if err != nil {
-		return err
-	}
-	outputAssetID := wt.Asset
-	if !wt.Return {
-		outputAssetID = actions.ImportedAssetID(wt.Asset, msg.SourceChainID)
-	}
-	hutils.Outf(
-		"%s {{yellow}}to:{{/}} %s {{yellow}}source assetID:{{/}} %s {{yellow}}source symbol:{{/}} %s {{yellow}}output assetID:{{/}} %s {{yellow}}value:{{/}} %s {{yellow}}reward:{{/}} %s {{yellow}}return:{{/}} %t\n",
-		hutils.ToID(
-			msg.UnsignedMessage.Payload,
-		),
-		codec.MustAddressBech32(nconsts.HRP, wt.To),
-		wt.Asset,
-		wt.Symbol,
-		outputAssetID,
-		hutils.FormatBalance(wt.Value, wt.Decimals),
-		hutils.FormatBalance(wt.Reward, wt.Decimals),
-		wt.Return,
-	)
-	if wt.SwapIn > 0 {
-		_, outSymbol, outDecimals, _, _, _, _, err := ncli.Asset(ctx, wt.AssetOut, false)
-		if err != nil {
-			return err
+		if warp {
+			hutils.Outf("{{red}}cannot update a warped asset{{/}}\n")
+			hutils.Outf("{{red}}exiting...{{/}}\n")
+			return nil
+		}
+		if owner != codec.MustAddressBech32(nconsts.HRP, priv.Address) {
+			hutils.Outf("{{red}}%s is the owner of %s, you are not{{/}}\n", owner, assetID)
+			hutils.Outf("{{red}}exiting...{{/}}\n")
+			return nil
 		}
 		hutils.Outf(
-			"{{yellow}}asset in:{{/}} %s {{yellow}}swap in:{{/}} %s {{yellow}}asset out:{{/}} %s {{yellow}}symbol out:{{/}} %s {{yellow}}swap out:{{/}} %s {{yellow}}swap expiry:{{/}} %d\n",
-			outputAssetID,
-			hutils.FormatBalance(wt.SwapIn, wt.Decimals),
-			wt.AssetOut,
-			outSymbol,
-			hutils.FormatBalance(wt.SwapOut, outDecimals),
-			wt.SwapExpiry,
+			"{{yellow}}symbol:{{/}} %s {{yellow}}decimals:{{/}} %d {{yellow}}metadata:{{/}} %s {{yellow}}supply:{{/}} %d {{yellow}}maxSupply:{{/}} %d {{yellow}}frozen:{{/}} %t\n",
+			string(symbol),
+			decimals,
+			string(metadata),
+			supply,
+			maxSupply,
+			frozen,
 		)
-	}
 
-	// Select fill
-	var fill bool
-	if wt.SwapIn > 0 {
-		fill, err = handler.Root().PromptBool("fill")
+		// Update metadata (leave empty to keep the current one)
+		newMetadata, err := handler.Root().PromptString("new metadata (leave empty to keep)", 0, actions.MaxMetadataSize)
 		if err != nil {
 			return err
 		}
-	}
-	if !fill && wt.SwapExpiry > time.Now().UnixMilli() {
-		return ErrMustFill
-	}
-
-	// Generate transaction
-	_, _, err = sendAndWait(ctx, msg, &actions.ImportAsset{
-		Fill: fill,
-	}, hdcli, hws, ncli, factory, true)
-	return err
-}
-
-var importAssetCmd = &cobra.Command{
-	Use: "import-asset",
-	RunE: func(*cobra.Command, []string) error {
-		ctx := context.Background()
 
-		currentChainID, _, factory, hdcli, hws, ncli, err := handler.DefaultActor()
+		// Select new owner
+		newOwner := priv.Address
+		transfer, err := handler.Root().PromptBool("transfer ownership")
 		if err != nil {
 			return err
 		}
+		if transfer {
+			renounce, err := handler.Root().PromptBool("renounce ownership permanently")
+			if err != nil {
+				return err
+			}
+			if renounce {
+				newOwner = codec.EmptyAddress
+			} else {
+				newOwner, err = handler.Root().PromptAddress("new owner")
+				if err != nil {
+					return err
+				}
+			}
+		}
 
-		// Select source
-		_, uris, err := handler.Root().PromptChain("sourceChainID", set.Of(currentChainID))
-		if err != nil {
+		// Freeze minting
+		freezeMint := false
+		if !frozen {
+			freezeMint, err = handler.Root().PromptBool("freeze minting permanently")
+			if err != nil {
+				return err
+			}
+		}
+
+		// Confirm action
+		cont, err := handler.Root().PromptContinue()
+		if !cont || err != nil {
 			return err
 		}
-		hscli := hrpc.NewJSONRPCClient(uris[0])
 
-		// Perform import
-		return performImport(ctx, hscli, hdcli, hws, ncli, ids.Empty, factory)
+		// Generate transaction
+		_, _, err = sendAndWait(ctx, nil, &actions.UpdateAsset{
+			Asset:      assetID,
+			Metadata:   []byte(newMetadata),
+			Owner:      newOwner,
+			FreezeMint: freezeMint,
+		}, hcli, hws, ncli, factory, true)
+		return err
 	},
 }
 
-var exportAssetCmd = &cobra.Command{
-	Use: "export-asset",
+var approveAssetCmd = &cobra.Command{
+	Use: "approve-asset",
 	RunE: func(*cobra.Command, []string) error {
 		ctx := context.Background()
-		currentChainID, priv, factory, hcli, hws, ncli, err := handler.DefaultActor()
+		_, priv, factory, hcli, hws, ncli, err := handler.DefaultActor()
 		if err != nil {
 			return err
 		}
 
-		// Select token to send
+		// Select token to approve
 		assetID, err := handler.Root().PromptAsset("assetID", true)
 		if err != nil {
 			return err
 		}
-		_, decimals, balance, sourceChainID, err := handler.GetAssetInfo(ctx, ncli, priv.Address, assetID, true)
-		if balance == 0 || err != nil {
+		_, decimals, _, _, err := handler.GetAssetInfo(ctx, ncli, priv.Address, assetID, true)
+		if err != nil {
 			return err
 		}
 
-		// Select recipient
-		recipient, err := handler.Root().PromptAddress("recipient")
+		// Select spender
+		spender, err := handler.Root().PromptAddress("spender")
 		if err != nil {
 			return err
 		}
 
-		// Select amount
-		amount, err := handler.Root().PromptAmount("amount", decimals, balance, nil)
+		// Select allowance (replaces any previous allowance)
+		amount, err := handler.Root().PromptAmount("allowance (0 to revoke)", decimals, hconsts.MaxUint64, nil)
 		if err != nil {
 			return err
 		}
 
-		// Determine return
-		var ret bool
-		if sourceChainID != ids.Empty {
-			ret = true
+		// Select expiry
+		var expiryBlock uint64
+		if amount > 0 {
+			currentBlockHeight, _, _, _, _, _, _, err := ncli.EmissionInfo(ctx)
+			if err != nil {
+				return err
+			}
+			hutils.Outf("{{yellow}}current block height:{{/}} %d\n", currentBlockHeight)
+			expiryBlockStr, err := handler.Root().PromptString("expiry block (0 for none)", 1, 20)
+			if err != nil {
+				return err
+			}
+			expiryBlock, err = strconv.ParseUint(expiryBlockStr, 10, 64)
+			if err != nil {
+				return err
+			}
 		}
 
-		// Select reward
-		reward, err := handler.Root().PromptAmount("reward", decimals, balance-amount, nil)
+		// Confirm action
+		cont, err := handler.Root().PromptContinue()
+		if !cont || err != nil {
+			return err
+		}
+
+		// Generate transaction
+		_, _, err = sendAndWait(ctx, nil, &actions.ApproveAsset{
+			Spender:     spender,
+			Asset:       assetID,
+			Value:       amount,
+			ExpiryBlock: expiryBlock,
+		}, hcli, hws, ncli, factory, true)
+		return err
+	},
+}
+
+var transferFromCmd = &cobra.Command{
+	Use: "transfer-from",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, priv, factory, hcli, hws, ncli, err := handler.DefaultActor()
 		if err != nil {
 			return err
 		}
 
-		// Determine destination
-		destination := sourceChainID
-		if !ret {
-			destination, _, err = handler.Root().PromptChain("destination", set.Of(currentChainID))
-			if err != nil {
-				return err
-			}
+		// Select token to transfer
+		assetID, err := handler.Root().PromptAsset("assetID", true)
+		if err != nil {
+			return err
 		}
 
-		// Determine if swap in
-		swap, err := handler.Root().PromptBool("swap on import")
+		// Select owner of the balance
+		owner, err := handler.Root().PromptAddress("owner")
 		if err != nil {
 			return err
 		}
-		var (
-			swapIn     uint64
-			assetOut   ids.ID
-			swapOut    uint64
-			swapExpiry int64
+		_, decimals, balance, _, err := handler.GetAssetInfo(ctx, ncli, owner, assetID, true)
+		if balance == 0 || err != nil {
+			return err
+		}
+		allowance, _, expired, err := handler.GetAllowance(
+			ctx,
+			ncli,
+			codec.MustAddressBech32(nconsts.HRP, owner),
+			codec.MustAddressBech32(nconsts.HRP, priv.Address),
+			assetID,
+			decimals,
+		)
+		if err != nil {
+			return err
+		}
+		if allowance == 0 || expired {
+			hutils.Outf("{{red}}exiting...{{/}}\n")
+			return nil
+		}
+
+		// Select recipient
+		recipient, err := handler.Root().PromptAddress("recipient")
+		if err != nil {
+			return err
+		}
+
+		// Select amount
+		amount, err := handler.Root().PromptAmount("amount", decimals, min(allowance, balance), nil)
+		if err != nil {
+			return err
+		}
+
+		// Confirm action
+		cont, err := handler.Root().PromptContinue()
+		if !cont || err != nil {
+			return err
+		}
+
+		// Generate transaction
+		_, _, err = sendAndWait(ctx, nil, &actions.TransferFrom{
+			From:  owner,
+			To:    recipient,
+			Asset: assetID,
+			Value: amount,
+		}, hcli, hws, ncli, factory, true)
+		return err
+	},
+}
+
+var createNFTCollectionCmd = &cobra.Command{
+	Use: "create-nft-collection",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, _, factory, hcli, hws, ncli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+
+		// Add symbol to collection
+		symbol, err := handler.Root().PromptString("symbol", 1, actions.MaxSymbolSize)
+		if err != nil {
+			return err
+		}
+
+		// Add metadata to collection
+		metadata, err := handler.Root().PromptString("metadata", 1, actions.MaxMetadataSize)
+		if err != nil {
+			return err
+		}
+
+		// Confirm action
+		cont, err := handler.Root().PromptContinue()
+		if !cont || err != nil {
+			return err
+		}
+
+		// Generate transaction
+		_, _, err = sendAndWait(ctx, nil, &actions.CreateNFTCollection{
+			Symbol:   []byte(symbol),
+			Metadata: []byte(metadata),
+		}, hcli, hws, ncli, factory, true)
+		return err
+	},
+}
+
+var mintNFTCmd = &cobra.Command{
+	Use: "mint-nft",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, priv, factory, hcli, hws, ncli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+
+		// Select collection to mint into
+		collectionID, err := handler.Root().PromptID("collectionID")
+		if err != nil {
+			return err
+		}
+		exists, _, _, _, owner, err := handler.GetNFTCollection(ctx, ncli, collectionID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			hutils.Outf("{{red}}exiting...{{/}}\n")
+			return nil
+		}
+		if owner != codec.MustAddressBech32(nconsts.HRP, priv.Address) {
+			hutils.Outf("{{red}}%s is the owner of %s, you are not{{/}}\n", owner, collectionID)
+			hutils.Outf("{{red}}exiting...{{/}}\n")
+			return nil
+		}
+
+		// Select NFT ID
+		idString, err := handler.Root().PromptString("nft id", 1, 20)
+		if err != nil {
+			return err
+		}
+		id, err := strconv.ParseUint(idString, 10, 64)
+		if err != nil {
+			return err
+		}
+
+		// Select recipient
+		recipient, err := handler.Root().PromptAddress("recipient")
+		if err != nil {
+			return err
+		}
+
+		// Add metadata to NFT
+		metadata, err := handler.Root().PromptString("metadata", 1, actions.MaxMetadataSize)
+		if err != nil {
+			return err
+		}
+
+		// Confirm action
+		cont, err := handler.Root().PromptContinue()
+		if !cont || err != nil {
+			return err
+		}
+
+		// Generate transaction
+		_, _, err = sendAndWait(ctx, nil, &actions.MintNFT{
+			Collection: collectionID,
+			ID:         id,
+			To:         recipient,
+			Metadata:   []byte(metadata),
+		}, hcli, hws, ncli, factory, true)
+		return err
+	},
+}
+
+var transferNFTCmd = &cobra.Command{
+	Use: "transfer-nft",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, priv, factory, hcli, hws, ncli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+
+		// Select NFT to transfer
+		nfts, err := handler.GetNFTsOfOwner(ctx, ncli, codec.MustAddressBech32(nconsts.HRP, priv.Address))
+		if err != nil || len(nfts) == 0 {
+			return err
+		}
+		nftIndex, err := handler.Root().PromptChoice("nft to transfer", len(nfts))
+		if err != nil {
+			return err
+		}
+		nft := nfts[nftIndex]
+
+		// Select recipient
+		recipient, err := handler.Root().PromptAddress("recipient")
+		if err != nil {
+			return err
+		}
+
+		// Confirm action
+		cont, err := handler.Root().PromptContinue()
+		if !cont || err != nil {
+			return err
+		}
+
+		// Generate transaction
+		_, _, err = sendAndWait(ctx, nil, &actions.TransferNFT{
+			Collection: nft.Collection,
+			ID:         nft.ID,
+			To:         recipient,
+		}, hcli, hws, ncli, factory, true)
+		return err
+	},
+}
+
+var burnNFTCmd = &cobra.Command{
+	Use: "burn-nft",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, priv, factory, hcli, hws, ncli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+
+		// Select NFT to burn
+		nfts, err := handler.GetNFTsOfOwner(ctx, ncli, codec.MustAddressBech32(nconsts.HRP, priv.Address))
+		if err != nil || len(nfts) == 0 {
+			return err
+		}
+		nftIndex, err := handler.Root().PromptChoice("nft to burn", len(nfts))
+		if err != nil {
+			return err
+		}
+		nft := nfts[nftIndex]
+
+		// Confirm action
+		cont, err := handler.Root().PromptContinue()
+		if !cont || err != nil {
+			return err
+		}
+
+		// Generate transaction
+		_, _, err = sendAndWait(ctx, nil, &actions.BurnNFT{
+			Collection: nft.Collection,
+			ID:         nft.ID,
+		}, hcli, hws, ncli, factory, true)
+		return err
+	},
+}
+
+func performImport(
+	ctx context.Context,
+	hscli *hrpc.JSONRPCClient,
+	hdcli *hrpc.JSONRPCClient,
+	hws *hrpc.WebSocketClient,
+	ncli *nrpc.JSONRPCClient,
+	exportTxID ids.ID,
+	factory chain.AuthFactory,
+) error {
+	// Select TxID (if not provided)
+	var err error
+	if exportTxID == ids.Empty {
+		exportTxID, err = handler.Root().PromptID("export txID")
+		if err != nil {
+			return err
+		}
+	}
+
+	// Generate warp signature (as long as >= 80% stake)
+	var (
+		msg                     *warp.Message
+		subnetWeight, sigWeight uint64
+	)
+	for ctx.Err() == nil {
+		msg, subnetWeight, sigWeight, err = hscli.GenerateAggregateWarpSignature(ctx, exportTxID)
+		if sigWeight >= (subnetWeight*4)/5 && err == nil {
+			break
+		}
+		if err == nil {
+			hutils.Outf(
+				"{{yellow}}waiting for signature weight:{{/}} %d {{yellow}}observed:{{/}} %d\n",
+				subnetWeight,
+				sigWeight,
+			)
+		} else {
+			hutils.Outf("{{red}}encountered error:{{/}} %v\n", err)
+		}
+		cont, err := handler.Root().PromptBool("try again")
+		if err != nil {
+			return err
+		}
+		if !cont {
+			hutils.Outf("{{red}}exiting...{{/}}\n")
+			return nil
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	wt, err := actions.UnmarshalWarpTransfer(msg.UnsignedMessage.Payload)
+	if err != nil {
+		return err
+	}
+	outputAssetID := wt.Asset
+	if !wt.Return {
+		outputAssetID = actions.ImportedAssetID(wt.Asset, msg.SourceChainID)
+	}
+	hutils.Outf(
+		"%s {{yellow}}to:{{/}} %s {{yellow}}source assetID:{{/}} %s {{yellow}}source symbol:{{/}} %s {{yellow}}output assetID:{{/}} %s {{yellow}}value:{{/}} %s {{yellow}}reward:{{/}} %s {{yellow}}return:{{/}} %t\n",
+		hutils.ToID(
+			msg.UnsignedMessage.Payload,
+		),
+		codec.MustAddressBech32(nconsts.HRP, wt.To),
+		wt.Asset,
+		wt.Symbol,
+		outputAssetID,
+		hutils.FormatBalance(wt.Value, wt.Decimals),
+		hutils.FormatBalance(wt.Reward, wt.Decimals),
+		wt.Return,
+	)
+	if wt.SwapIn > 0 {
+		_, outSymbol, outDecimals, _, _, _, _, _, _, err := ncli.Asset(ctx, wt.AssetOut, false)
+		if err != nil {
+			return err
+		}
+		hutils.Outf(
+			"{{yellow}}asset in:{{/}} %s {{yellow}}swap in:{{/}} %s {{yellow}}asset out:{{/}} %s {{yellow}}symbol out:{{/}} %s {{yellow}}swap out:{{/}} %s {{yellow}}swap expiry:{{/}} %d\n",
+			outputAssetID,
+			hutils.FormatBalance(wt.SwapIn, wt.Decimals),
+			wt.AssetOut,
+			outSymbol,
+			hutils.FormatBalance(wt.SwapOut, outDecimals),
+			wt.SwapExpiry,
+		)
+	}
+
+	// Select fill
+	var fill bool
+	if wt.SwapIn > 0 {
+		fill, err = handler.Root().PromptBool("fill")
+		if err != nil {
+			return err
+		}
+	}
+	if !fill && wt.SwapExpiry > time.Now().UnixMilli() {
+		return ErrMustFill
+	}
+
+	// Generate transaction
+	_, _, err = sendAndWait(ctx, msg, &actions.ImportAsset{
+		Fill: fill,
+	}, hdcli, hws, ncli, factory, true)
+	return err
+}
+
+var importAssetCmd = &cobra.Command{
+	Use: "import-asset",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+
+		currentChainID, _, factory, hdcli, hws, ncli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+
+		// Select source
+		_, uris, err := handler.Root().PromptChain("sourceChainID", set.Of(currentChainID))
+		if err != nil {
+			return err
+		}
+		hscli := hrpc.NewJSONRPCClient(uris[0])
+
+		// Perform import
+		return performImport(ctx, hscli, hdcli, hws, ncli, ids.Empty, factory)
+	},
+}
+
+var exportAssetCmd = &cobra.Command{
+	Use: "export-asset",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		currentChainID, priv, factory, hcli, hws, ncli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+
+		// Select token to send
+		assetID, err := handler.Root().PromptAsset("assetID", true)
+		if err != nil {
+			return err
+		}
+		_, decimals, balance, sourceChainID, err := handler.GetAssetInfo(ctx, ncli, priv.Address, assetID, true)
+		if balance == 0 || err != nil {
+			return err
+		}
+
+		// Select recipient
+		recipient, err := handler.Root().PromptAddress("recipient")
+		if err != nil {
+			return err
+		}
+
+		// Select amount
+		amount, err := handler.Root().PromptAmount("amount", decimals, balance, nil)
+		if err != nil {
+			return err
+		}
+
+		// Determine return
+		var ret bool
+		if sourceChainID != ids.Empty {
+			ret = true
+		}
+
+		// Select reward
+		reward, err := handler.Root().PromptAmount("reward", decimals, balance-amount, nil)
+		if err != nil {
+			return err
+		}
+
+		// Determine destination
+		destination := sourceChainID
+		if !ret {
+			destination, _, err = handler.Root().PromptChain("destination", set.Of(currentChainID))
+			if err != nil {
+				return err
+			}
+		}
+
+		// Determine if swap in
+		swap, err := handler.Root().PromptBool("swap on import")
+		if err != nil {
+			return err
+		}
+		var (
+			swapIn     uint64
+			assetOut   ids.ID
+			swapOut    uint64
+			swapExpiry int64
 		)
 		if swap {
 			swapIn, err = handler.Root().PromptAmount("swap in", decimals, amount, nil)
 			if err != nil {
 				return err
 			}
-			assetOut, err = handler.Root().PromptAsset("asset out (on destination)", true)
+			assetOut, err = handler.Root().PromptAsset("asset out (on destination)", true)
+			if err != nil {
+				return err
+			}
+			uris, err := handler.Root().GetChain(destination)
+			if err != nil {
+				return err
+			}
+			networkID, _, _, err := hcli.Network(ctx)
+			if err != nil {
+				return err
+			}
+			dcli := nrpc.NewJSONRPCClient(uris[0], networkID, destination)
+			_, decimals, _, _, err := handler.GetAssetInfo(ctx, dcli, priv.Address, assetOut, false)
+			if err != nil {
+				return err
+			}
+			swapOut, err = handler.Root().PromptAmount(
+				"swap out (on destination, no decimals)",
+				decimals,
+				hconsts.MaxUint64,
+				nil,
+			)
+			if err != nil {
+				return err
+			}
+			swapExpiry, err = handler.Root().PromptTime("swap expiry")
+			if err != nil {
+				return err
+			}
+		}
+
+		// Confirm action
+		cont, err := handler.Root().PromptContinue()
+		if !cont || err != nil {
+			return err
+		}
+
+		// Generate transaction
+		success, txID, err := sendAndWait(ctx, nil, &actions.ExportAsset{
+			To:          recipient,
+			Asset:       assetID,
+			Value:       amount,
+			Return:      ret,
+			Reward:      reward,
+			SwapIn:      swapIn,
+			AssetOut:    assetOut,
+			SwapOut:     swapOut,
+			SwapExpiry:  swapExpiry,
+			Destination: destination,
+		}, hcli, hws, ncli, factory, true)
+		if err != nil {
+			return err
+		}
+		if !success {
+			return errors.New("not successful")
+		}
+
+		// Perform import
+		imp, err := handler.Root().PromptBool("perform import on destination")
+		if err != nil {
+			return err
+		}
+		if imp {
+			uris, err := handler.Root().GetChain(destination)
+			if err != nil {
+				return err
+			}
+			networkID, _, _, err := hcli.Network(ctx)
+			if err != nil {
+				return err
+			}
+			hdcli, err := hrpc.NewWebSocketClient(uris[0], hrpc.DefaultHandshakeTimeout, pubsub.MaxPendingMessages, pubsub.MaxReadMessageSize)
+			if err != nil {
+				return err
+			}
+			if err := performImport(ctx, hcli, hrpc.NewJSONRPCClient(uris[0]), hdcli, nrpc.NewJSONRPCClient(uris[0], networkID, destination), txID, factory); err != nil {
+				return err
+			}
+		}
+
+		// Ask if user would like to switch to destination chain
+		sw, err := handler.Root().PromptBool("switch default chain to destination")
+		if err != nil {
+			return err
+		}
+		if !sw {
+			return nil
+		}
+		return handler.Root().StoreDefaultChain(destination)
+	},
+}
+
+// Define the layout that matches the provided date string
+// Note: the reference time is "Mon Jan 2 15:04:05 MST 2006" in Go
+const (
+	TimeLayout = "2006-01-02 15:04:05"
+	Auto       = "auto"
+	Manual     = "manual"
+)
+
+var registerValidatorStakeCmd = &cobra.Command{
+	Use: "register-validator-stake [manual | auto <node#>]",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 || (args[0] != Manual && args[0] != Auto) {
+			return ErrInvalidArgs
+		}
+		return nil
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		autoRegister := args[0] == Auto
+		nodeNumber := "node1"
+		if len(args) == 2 {
+			isValid := regexp.MustCompile(`^node([1-9]|10)$`).MatchString(nodeNumber)
+			if !isValid {
+				return fmt.Errorf("invalid node number")
+			}
+			nodeNumber = args[1]
+		}
+
+		ctx := context.Background()
+		_, priv, factory, hcli, hws, ncli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+
+		if autoRegister {
+			hutils.Outf("{{yellow}}Loading private key for %s{{/}}\n", nodeNumber)
+			validatorSignerKey, err := loadPrivateKey("bls", fmt.Sprintf("/tmp/nuklaivm/nodes/%s-bls/signer.key", nodeNumber))
+			if err != nil {
+				return err
+			}
+			validatorSignerAddress := codec.MustAddressBech32(nconsts.HRP, validatorSignerKey.Address)
+			nclients, err := handler.DefaultNuklaiVMJSONRPCClient(checkAllChains)
+			if err != nil {
+				return err
+			}
+			_, _, balance, _, err := handler.GetAssetInfo(ctx, nclients[0], validatorSignerKey.Address, ids.Empty, true)
+			if err != nil {
+				return err
+			}
+			hutils.Outf("{{yellow}}Balance of validator signer:{{/}} %s\n", hutils.FormatBalance(balance, nconsts.Decimals))
+			if balance < uint64(100*math.Pow10(int(nconsts.Decimals))) {
+				hutils.Outf("{{yellow}} You need a minimum of 100 NAI to register a validator{{/}}\n")
+				return nil
+			}
+			// Set the default key to the validator signer key
+			hutils.Outf("{{yellow}}Loading validator signer key :{{/}} %s\n", validatorSignerAddress)
+			if err := handler.h.StoreKey(validatorSignerKey); err != nil && !errors.Is(err, hyperCli.ErrDuplicate) {
+				return err
+			}
+			if err := handler.h.StoreDefaultKey(validatorSignerKey.Address); err != nil {
+				return err
+			}
+			_, priv, factory, hcli, hws, ncli, err = handler.DefaultActor()
+			if err != nil {
+				return err
+			}
+		}
+
+		keyType, _ := getKeyType(priv.Address)
+		if keyType != blsKey {
+			return fmt.Errorf("actor must be a BLS key")
+		}
+		secretKey, err := bls.PrivateKeyFromBytes(priv.Bytes)
+		if err != nil {
+			return err
+		}
+		publicKey := bls.PublicKeyToBytes(bls.PublicFromPrivateKey(secretKey))
+		hutils.Outf("{{yellow}}Validator Signer Address: %s\n", codec.MustAddressBech32(nconsts.HRP, priv.Address))
+
+		// Get the validator for which the actor is a signer
+		// Get current list of validators
+		validators, err := ncli.AllValidators(ctx)
+		if err != nil {
+			return err
+		}
+		if len(validators) == 0 {
+			hutils.Outf("{{red}}no validators{{/}}\n")
+			return nil
+		}
+
+		var nodeID ids.NodeID
+		for i := 0; i < len(validators); i++ {
+			if bytes.Equal(publicKey, validators[i].PublicKey) {
+				nodeID = validators[i].NodeID
+				break
+			}
+		}
+		hutils.Outf("{{yellow}}Validator NodeID:{{/}} %s\n", nodeID.String())
+		if nodeID.Compare(ids.EmptyNodeID) == 0 {
+			hutils.Outf("{{red}}actor is not a signer for any of the validators{{/}}\n")
+			return nil
+		}
+
+		// Get balance info
+		_, _, balance, _, err := handler.GetAssetInfo(ctx, ncli, priv.Address, ids.Empty, true)
+		if balance == 0 || err != nil {
+			return err
+		}
+		if balance < uint64(100*math.Pow10(int(nconsts.Decimals))) {
+			hutils.Outf("{{yellow}} You need a minimum of 100 NAI to register a validator{{/}}\n")
+			return nil
+		}
+
+		// Select staked amount
+		stakedAmount, err := handler.Root().PromptAmount("Staked amount", nconsts.Decimals, balance, nil)
+		if err != nil {
+			return err
+		}
+
+		// Get current block
+		currentBlockHeight, _, _, _, _, _, _, err := ncli.EmissionInfo(ctx)
+		if err != nil {
+			return err
+		}
+
+		stakeStartBlock := currentBlockHeight + 20 // roughly 1 minute from now
+		stakeEndBlock := stakeStartBlock + 20*2    // roughly 10 minutes from stakeStartBlock
+		delegationFeeRate := 50
+		rewardAddress := priv.Address
+
+		if !autoRegister {
+			// Select stakeStartBlock
+			stakeStartBlockString, err := handler.Root().PromptString(
+				fmt.Sprintf("Staking Start Block(must be after %d)", currentBlockHeight),
+				1,
+				32,
+			)
+			if err != nil {
+				return err
+			}
+
+			stakeStartBlock, err = strconv.ParseUint(stakeStartBlockString, 10, 64)
+			if err != nil {
+				return err
+			}
+
+			// Select stakeEndBlock
+			stakeEndBlockString, err := handler.Root().PromptString(
+				fmt.Sprintf("Staking End Block(must be after %s)", stakeStartBlockString),
+				1,
+				32,
+			)
+			if err != nil {
+				return err
+			}
+			stakeEndBlock, err = strconv.ParseUint(stakeEndBlockString, 10, 64)
+			if err != nil {
+				return err
+			}
+
+			// Select delegationFeeRate
+			delegationFeeRate, err = handler.Root().PromptInt("Delegation Fee Rate(must be over 2)", 100)
+			if err != nil {
+				return err
+			}
+
+			// Select rewardAddress
+			rewardAddress, err = handler.Root().PromptAddress("Reward Address")
+			if err != nil {
+				return err
+			}
+		}
+
+		if stakeStartBlock < currentBlockHeight {
+			return fmt.Errorf("staking start block must be after the current block height (%d)", currentBlockHeight)
+		}
+		if stakeEndBlock < stakeStartBlock {
+			return fmt.Errorf("staking end block must be after the staking start block height (%d)", stakeStartBlock)
+		}
+		if delegationFeeRate < 2 || delegationFeeRate > 100 {
+			return fmt.Errorf("delegation fee rate must be over 2 and under 100")
+		}
+
+		// Confirm action
+		cont, err := handler.Root().PromptContinue()
+		if !cont || err != nil {
+			return err
+		}
+
+		hutils.Outf("{{yellow}}Register Validator Stake Info - stakeStartBlock: %d stakeEndBlock: %d delegationFeeRate: %d rewardAddress: %s\n", stakeStartBlock, stakeEndBlock, delegationFeeRate, codec.MustAddressBech32(nconsts.HRP, rewardAddress))
+
+		stakeInfo := &actions.ValidatorStakeInfo{
+			NodeID:            nodeID.Bytes(),
+			StakeStartBlock:   stakeStartBlock,
+			StakeEndBlock:     stakeEndBlock,
+			StakedAmount:      stakedAmount,
+			DelegationFeeRate: uint64(delegationFeeRate),
+			RewardAddress:     rewardAddress,
+		}
+		stakeInfoBytes, err := stakeInfo.Marshal()
+		if err != nil {
+			return err
+		}
+		authFactory := auth.NewBLSFactory(secretKey)
+		signature, err := authFactory.Sign(stakeInfoBytes)
+		if err != nil {
+			return err
+		}
+		signaturePacker := codec.NewWriter(signature.Size(), signature.Size())
+		signature.Marshal(signaturePacker)
+		authSignature := signaturePacker.Bytes()
+
+		// Generate transaction
+		_, _, err = sendAndWait(ctx, nil, &actions.RegisterValidatorStake{
+			StakeInfo:     stakeInfoBytes,
+			AuthSignature: authSignature,
+		}, hcli, hws, ncli, factory, true)
+		return err
+	},
+}
+
+var getValidatorStakeCmd = &cobra.Command{
+	Use: "get-validator-stake [address]",
+	RunE: func(_ *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		// The owner address is used to show stakes that are still unbonding
+		// after the validator stake was withdrawn
+		var address codec.Address
+		if len(args) == 0 {
+			_, priv, _, _, _, _, err := handler.DefaultActor()
+			if err != nil {
+				return err
+			}
+			address = priv.Address
+		} else {
+			addr, err := codec.ParseAddressBech32(nconsts.HRP, args[0])
 			if err != nil {
 				return err
 			}
-			uris, err := handler.Root().GetChain(destination)
+			address = addr
+		}
+
+		// Get clients
+		nclients, err := handler.DefaultNuklaiVMJSONRPCClient(checkAllChains)
+		if err != nil {
+			return err
+		}
+		ncli := nclients[0]
+
+		// Get current list of validators
+		validators, err := ncli.StakedValidators(ctx)
+		if err != nil {
+			return err
+		}
+		if len(validators) == 0 {
+			hutils.Outf("{{red}}no validators{{/}}\n")
+			return nil
+		}
+
+		hutils.Outf("{{cyan}}validators:{{/}} %d\n", len(validators))
+		for i := 0; i < len(validators); i++ {
+			hutils.Outf(
+				"{{yellow}}%d:{{/}} NodeID=%s\n",
+				i,
+				validators[i].NodeID,
+			)
+		}
+		// Select validator
+		keyIndex, err := handler.Root().PromptChoice("validator to get staking info for", len(validators))
+		if err != nil {
+			return err
+		}
+		validatorChosen := validators[keyIndex]
+		nodeID := validatorChosen.NodeID
+
+		// Get validator stake
+		_, _, _, _, _, _, err = handler.GetValidatorStake(ctx, ncli, nodeID, address)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	},
+}
+
+var claimValidatorStakeRewardCmd = &cobra.Command{
+	Use: "claim-validator-stake-reward",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, _, factory, hcli, hws, ncli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+
+		// Get current list of validators
+		validators, err := ncli.StakedValidators(ctx)
+		if err != nil {
+			return err
+		}
+		if len(validators) == 0 {
+			hutils.Outf("{{red}}no validators{{/}}\n")
+			return nil
+		}
+
+		// Show validators to the user
+		hutils.Outf("{{cyan}}validators:{{/}} %d\n", len(validators))
+		for i := 0; i < len(validators); i++ {
+			hutils.Outf(
+				"{{yellow}}%d:{{/}} NodeID=%s\n",
+				i,
+				validators[i].NodeID,
+			)
+		}
+		// Select validator
+		keyIndex, err := handler.Root().PromptChoice("validator to claim staking rewards for", len(validators))
+		if err != nil {
+			return err
+		}
+		validatorChosen := validators[keyIndex]
+		nodeID := validatorChosen.NodeID
+
+		// Get stake info
+		_, _, stakedAmount, _, _, _, _, _, _, err := ncli.ValidatorStake(ctx, nodeID, codec.EmptyAddress)
+		if err != nil {
+			return err
+		}
+
+		if stakedAmount == 0 {
+			hutils.Outf("{{red}}validator has not yet staked{{/}}\n")
+			return nil
+		}
+
+		// Confirm action
+		cont, err := handler.Root().PromptContinue()
+		if !cont || err != nil {
+			return err
+		}
+
+		// Generate transaction
+		_, _, err = sendAndWait(ctx, nil, &actions.ClaimValidatorStakeRewards{
+			NodeID: nodeID.Bytes(),
+		}, hcli, hws, ncli, factory, true)
+		return err
+	},
+}
+
+var withdrawValidatorStakeCmd = &cobra.Command{
+	Use: "withdraw-validator-stake",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, priv, factory, hcli, hws, ncli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+
+		// Get current list of validators
+		validators, err := ncli.StakedValidators(ctx)
+		if err != nil {
+			return err
+		}
+		if len(validators) == 0 {
+			hutils.Outf("{{red}}no validators{{/}}\n")
+			return nil
+		}
+
+		// Show validators to the user
+		hutils.Outf("{{cyan}}validators:{{/}} %d\n", len(validators))
+		for i := 0; i < len(validators); i++ {
+			hutils.Outf(
+				"{{yellow}}%d:{{/}} NodeID=%s\n",
+				i,
+				validators[i].NodeID,
+			)
+		}
+		// Select validator
+		keyIndex, err := handler.Root().PromptChoice("validator to withdraw from staking", len(validators))
+		if err != nil {
+			return err
+		}
+		validatorChosen := validators[keyIndex]
+		nodeID := validatorChosen.NodeID
+
+		// Get stake info
+		_, _, stakedAmount, _, _, _, _, _, _, err := ncli.ValidatorStake(ctx, nodeID, codec.EmptyAddress)
+		if err != nil {
+			return err
+		}
+
+		if stakedAmount == 0 {
+			hutils.Outf("{{red}}validator has not yet been staked{{/}}\n")
+			return nil
+		}
+
+		// Confirm action
+		cont, err := handler.Root().PromptContinue()
+		if !cont || err != nil {
+			return err
+		}
+
+		// Generate transaction
+		_, _, err = sendAndWait(ctx, nil, &actions.WithdrawValidatorStake{
+			NodeID:        nodeID.Bytes(),
+			RewardAddress: priv.Address,
+		}, hcli, hws, ncli, factory, true)
+		return err
+	},
+}
+
+var updateValidatorStakeCmd = &cobra.Command{
+	Use: "update-validator-stake",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, priv, factory, hcli, hws, ncli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+
+		keyType, _ := getKeyType(priv.Address)
+		if keyType != blsKey {
+			return fmt.Errorf("actor must be a BLS key")
+		}
+		secretKey, err := bls.PrivateKeyFromBytes(priv.Bytes)
+		if err != nil {
+			return err
+		}
+		publicKey := bls.PublicKeyToBytes(bls.PublicFromPrivateKey(secretKey))
+
+		// Get the validator for which the actor is a signer
+		validators, err := ncli.AllValidators(ctx)
+		if err != nil {
+			return err
+		}
+		var nodeID ids.NodeID
+		for i := 0; i < len(validators); i++ {
+			if bytes.Equal(publicKey, validators[i].PublicKey) {
+				nodeID = validators[i].NodeID
+				break
+			}
+		}
+		if nodeID.Compare(ids.EmptyNodeID) == 0 {
+			hutils.Outf("{{red}}actor is not a signer for any of the validators{{/}}\n")
+			return nil
+		}
+		hutils.Outf("{{yellow}}Validator NodeID:{{/}} %s\n", nodeID.String())
+
+		// Get stake info
+		_, stakeEndBlock, stakedAmount, delegationFeeRate, rewardAddress, _, _, _, _, err := ncli.ValidatorStake(ctx, nodeID, codec.EmptyAddress)
+		if err != nil {
+			return err
+		}
+		if stakedAmount == 0 {
+			hutils.Outf("{{red}}validator has not yet been staked{{/}}\n")
+			return nil
+		}
+
+		// Select the new values, keeping the current ones by default
+		stakeEndBlockString, err := handler.Root().PromptString(
+			fmt.Sprintf("Staking End Block(current: %d)", stakeEndBlock),
+			0,
+			32,
+		)
+		if err != nil {
+			return err
+		}
+		if len(stakeEndBlockString) > 0 {
+			stakeEndBlock, err = strconv.ParseUint(stakeEndBlockString, 10, 64)
 			if err != nil {
 				return err
 			}
-			networkID, _, _, err := hcli.Network(ctx)
+		}
+		delegationFeeRateString, err := handler.Root().PromptString(
+			fmt.Sprintf("Delegation Fee Rate(current: %d)", delegationFeeRate),
+			0,
+			3,
+		)
+		if err != nil {
+			return err
+		}
+		if len(delegationFeeRateString) > 0 {
+			delegationFeeRate, err = strconv.ParseUint(delegationFeeRateString, 10, 64)
 			if err != nil {
 				return err
 			}
-			dcli := nrpc.NewJSONRPCClient(uris[0], networkID, destination)
-			_, decimals, _, _, err := handler.GetAssetInfo(ctx, dcli, priv.Address, assetOut, false)
+		}
+		if delegationFeeRate < 2 || delegationFeeRate > 100 {
+			return fmt.Errorf("delegation fee rate must be over 2 and under 100")
+		}
+		rewardAddressString, err := handler.Root().PromptString(
+			fmt.Sprintf("Reward Address(current: %s)", codec.MustAddressBech32(nconsts.HRP, rewardAddress)),
+			0,
+			128,
+		)
+		if err != nil {
+			return err
+		}
+		if len(rewardAddressString) > 0 {
+			rewardAddress, err = codec.ParseAddressBech32(nconsts.HRP, rewardAddressString)
 			if err != nil {
 				return err
 			}
-			swapOut, err = handler.Root().PromptAmount(
-				"swap out (on destination, no decimals)",
-				decimals,
-				hconsts.MaxUint64,
-				nil,
+		}
+
+		// Confirm action
+		cont, err := handler.Root().PromptContinue()
+		if !cont || err != nil {
+			return err
+		}
+
+		// Generate transaction
+		_, _, err = sendAndWait(ctx, nil, &actions.UpdateValidatorStake{
+			NodeID:            nodeID.Bytes(),
+			StakeEndBlock:     stakeEndBlock,
+			DelegationFeeRate: delegationFeeRate,
+			RewardAddress:     rewardAddress,
+		}, hcli, hws, ncli, factory, true)
+		return err
+	},
+}
+
+var increaseValidatorStakeCmd = &cobra.Command{
+	Use: "increase-validator-stake",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, priv, factory, hcli, hws, ncli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+
+		// Get current list of validators
+		validators, err := ncli.StakedValidators(ctx)
+		if err != nil {
+			return err
+		}
+		if len(validators) == 0 {
+			hutils.Outf("{{red}}no validators{{/}}\n")
+			return nil
+		}
+
+		// Show validators to the user
+		hutils.Outf("{{cyan}}validators:{{/}} %d\n", len(validators))
+		for i := 0; i < len(validators); i++ {
+			hutils.Outf(
+				"{{yellow}}%d:{{/}} NodeID=%s\n",
+				i,
+				validators[i].NodeID,
 			)
-			if err != nil {
-				return err
-			}
-			swapExpiry, err = handler.Root().PromptTime("swap expiry")
-			if err != nil {
-				return err
-			}
+		}
+		// Select validator
+		keyIndex, err := handler.Root().PromptChoice("validator to add stake to", len(validators))
+		if err != nil {
+			return err
+		}
+		validatorChosen := validators[keyIndex]
+		nodeID := validatorChosen.NodeID
+
+		// Get stake info
+		_, _, stakedAmount, _, _, _, _, _, _, err := ncli.ValidatorStake(ctx, nodeID, codec.EmptyAddress)
+		if err != nil {
+			return err
+		}
+
+		if stakedAmount == 0 {
+			hutils.Outf("{{red}}validator has not yet been staked{{/}}\n")
+			return nil
+		}
+
+		// Get balance info
+		_, _, balance, _, err := handler.GetAssetInfo(ctx, ncli, priv.Address, ids.Empty, true)
+		if balance == 0 || err != nil {
+			return err
+		}
+
+		// Select amount to add
+		amount, err := handler.Root().PromptAmount("Amount to add", nconsts.Decimals, balance, nil)
+		if err != nil {
+			return err
 		}
 
 		// Confirm action
@@ -474,127 +1616,94 @@ var exportAssetCmd = &cobra.Command{
 		}
 
 		// Generate transaction
-		success, txID, err := sendAndWait(ctx, nil, &actions.ExportAsset{
-			To:          recipient,
-			Asset:       assetID,
-			Value:       amount,
-			Return:      ret,
-			Reward:      reward,
-			SwapIn:      swapIn,
-			AssetOut:    assetOut,
-			SwapOut:     swapOut,
-			SwapExpiry:  swapExpiry,
-			Destination: destination,
+		_, _, err = sendAndWait(ctx, nil, &actions.IncreaseValidatorStake{
+			NodeID:        nodeID.Bytes(),
+			Amount:        amount,
+			RewardAddress: priv.Address,
 		}, hcli, hws, ncli, factory, true)
+		return err
+	},
+}
+
+var decreaseValidatorStakeCmd = &cobra.Command{
+	Use: "decrease-validator-stake",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, priv, factory, hcli, hws, ncli, err := handler.DefaultActor()
 		if err != nil {
 			return err
 		}
-		if !success {
-			return errors.New("not successful")
-		}
 
-		// Perform import
-		imp, err := handler.Root().PromptBool("perform import on destination")
+		// Get current list of validators
+		validators, err := ncli.StakedValidators(ctx)
 		if err != nil {
 			return err
 		}
-		if imp {
-			uris, err := handler.Root().GetChain(destination)
-			if err != nil {
-				return err
-			}
-			networkID, _, _, err := hcli.Network(ctx)
-			if err != nil {
-				return err
-			}
-			hdcli, err := hrpc.NewWebSocketClient(uris[0], hrpc.DefaultHandshakeTimeout, pubsub.MaxPendingMessages, pubsub.MaxReadMessageSize)
-			if err != nil {
-				return err
-			}
-			if err := performImport(ctx, hcli, hrpc.NewJSONRPCClient(uris[0]), hdcli, nrpc.NewJSONRPCClient(uris[0], networkID, destination), txID, factory); err != nil {
-				return err
-			}
+		if len(validators) == 0 {
+			hutils.Outf("{{red}}no validators{{/}}\n")
+			return nil
 		}
 
-		// Ask if user would like to switch to destination chain
-		sw, err := handler.Root().PromptBool("switch default chain to destination")
+		// Show validators to the user
+		hutils.Outf("{{cyan}}validators:{{/}} %d\n", len(validators))
+		for i := 0; i < len(validators); i++ {
+			hutils.Outf(
+				"{{yellow}}%d:{{/}} NodeID=%s\n",
+				i,
+				validators[i].NodeID,
+			)
+		}
+		// Select validator
+		keyIndex, err := handler.Root().PromptChoice("validator to take stake off", len(validators))
 		if err != nil {
 			return err
 		}
-		if !sw {
+		validatorChosen := validators[keyIndex]
+		nodeID := validatorChosen.NodeID
+
+		// Get stake info
+		_, _, stakedAmount, _, _, _, _, _, _, err := ncli.ValidatorStake(ctx, nodeID, codec.EmptyAddress)
+		if err != nil {
+			return err
+		}
+
+		if stakedAmount == 0 {
+			hutils.Outf("{{red}}validator has not yet been staked{{/}}\n")
 			return nil
 		}
-		return handler.Root().StoreDefaultChain(destination)
-	},
-}
 
-// Define the layout that matches the provided date string
-// Note: the reference time is "Mon Jan 2 15:04:05 MST 2006" in Go
-const (
-	TimeLayout = "2006-01-02 15:04:05"
-	Auto       = "auto"
-	Manual     = "manual"
-)
+		// Select amount to take off
+		amount, err := handler.Root().PromptAmount("Amount to take off", nconsts.Decimals, stakedAmount, nil)
+		if err != nil {
+			return err
+		}
 
-var registerValidatorStakeCmd = &cobra.Command{
-	Use: "register-validator-stake [manual | auto <node#>]",
-	PreRunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) == 0 || (args[0] != Manual && args[0] != Auto) {
-			return ErrInvalidArgs
+		// Confirm action
+		cont, err := handler.Root().PromptContinue()
+		if !cont || err != nil {
+			return err
 		}
-		return nil
+
+		// Generate transaction
+		_, _, err = sendAndWait(ctx, nil, &actions.DecreaseValidatorStake{
+			NodeID:        nodeID.Bytes(),
+			Amount:        amount,
+			RewardAddress: priv.Address,
+		}, hcli, hws, ncli, factory, true)
+		return err
 	},
-	RunE: func(_ *cobra.Command, args []string) error {
-		autoRegister := args[0] == Auto
-		nodeNumber := "node1"
-		if len(args) == 2 {
-			isValid := regexp.MustCompile(`^node([1-9]|10)$`).MatchString(nodeNumber)
-			if !isValid {
-				return fmt.Errorf("invalid node number")
-			}
-			nodeNumber = args[1]
-		}
+}
 
+var validatorHeartbeatCmd = &cobra.Command{
+	Use: "validator-heartbeat",
+	RunE: func(*cobra.Command, []string) error {
 		ctx := context.Background()
 		_, priv, factory, hcli, hws, ncli, err := handler.DefaultActor()
 		if err != nil {
 			return err
 		}
 
-		if autoRegister {
-			hutils.Outf("{{yellow}}Loading private key for %s{{/}}\n", nodeNumber)
-			validatorSignerKey, err := loadPrivateKey("bls", fmt.Sprintf("/tmp/nuklaivm/nodes/%s-bls/signer.key", nodeNumber))
-			if err != nil {
-				return err
-			}
-			validatorSignerAddress := codec.MustAddressBech32(nconsts.HRP, validatorSignerKey.Address)
-			nclients, err := handler.DefaultNuklaiVMJSONRPCClient(checkAllChains)
-			if err != nil {
-				return err
-			}
-			_, _, balance, _, err := handler.GetAssetInfo(ctx, nclients[0], validatorSignerKey.Address, ids.Empty, true)
-			if err != nil {
-				return err
-			}
-			hutils.Outf("{{yellow}}Balance of validator signer:{{/}} %s\n", hutils.FormatBalance(balance, nconsts.Decimals))
-			if balance < uint64(100*math.Pow10(int(nconsts.Decimals))) {
-				hutils.Outf("{{yellow}} You need a minimum of 100 NAI to register a validator{{/}}\n")
-				return nil
-			}
-			// Set the default key to the validator signer key
-			hutils.Outf("{{yellow}}Loading validator signer key :{{/}} %s\n", validatorSignerAddress)
-			if err := handler.h.StoreKey(validatorSignerKey); err != nil && !errors.Is(err, hyperCli.ErrDuplicate) {
-				return err
-			}
-			if err := handler.h.StoreDefaultKey(validatorSignerKey.Address); err != nil {
-				return err
-			}
-			_, priv, factory, hcli, hws, ncli, err = handler.DefaultActor()
-			if err != nil {
-				return err
-			}
-		}
-
+		// Heartbeats must be signed by the node signer key of the validator
 		keyType, _ := getKeyType(priv.Address)
 		if keyType != blsKey {
 			return fmt.Errorf("actor must be a BLS key")
@@ -604,19 +1713,12 @@ var registerValidatorStakeCmd = &cobra.Command{
 			return err
 		}
 		publicKey := bls.PublicKeyToBytes(bls.PublicFromPrivateKey(secretKey))
-		hutils.Outf("{{yellow}}Validator Signer Address: %s\n", codec.MustAddressBech32(nconsts.HRP, priv.Address))
 
 		// Get the validator for which the actor is a signer
-		// Get current list of validators
 		validators, err := ncli.AllValidators(ctx)
 		if err != nil {
 			return err
 		}
-		if len(validators) == 0 {
-			hutils.Outf("{{red}}no validators{{/}}\n")
-			return nil
-		}
-
 		var nodeID ids.NodeID
 		for i := 0; i < len(validators); i++ {
 			if bytes.Equal(publicKey, validators[i].PublicKey) {
@@ -624,43 +1726,105 @@ var registerValidatorStakeCmd = &cobra.Command{
 				break
 			}
 		}
-		hutils.Outf("{{yellow}}Validator NodeID:{{/}} %s\n", nodeID.String())
 		if nodeID.Compare(ids.EmptyNodeID) == 0 {
 			hutils.Outf("{{red}}actor is not a signer for any of the validators{{/}}\n")
 			return nil
 		}
+		hutils.Outf("{{yellow}}Validator NodeID:{{/}} %s\n", nodeID.String())
+
+		// Generate transaction
+		_, _, err = sendAndWait(ctx, nil, &actions.ValidatorHeartbeat{
+			NodeID: nodeID.Bytes(),
+		}, hcli, hws, ncli, factory, true)
+		return err
+	},
+}
+
+var delegateUserStakeCmd = &cobra.Command{
+	Use: "delegate-user-stake [manual | auto]",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 || (args[0] != Manual && args[0] != Auto) {
+			return ErrInvalidArgs
+		}
+		return nil
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		autoRegister := args[0] == Auto
+		ctx := context.Background()
+		_, priv, factory, hcli, hws, ncli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+
+		// Get current list of validators
+		validators, err := ncli.StakedValidators(ctx)
+		if err != nil {
+			return err
+		}
+		if len(validators) == 0 {
+			hutils.Outf("{{red}}no validators{{/}}\n")
+			return nil
+		}
+		hutils.Outf("{{cyan}}validators:{{/}} %d\n", len(validators))
+
+		capacities := make([]uint64, len(validators))
+		stakeEndBlocks := make([]uint64, len(validators))
+		for i := 0; i < len(validators); i++ {
+			_, stakeEndBlocks[i], _, _, _, _, _, capacities[i], _, err = ncli.ValidatorStake(ctx, validators[i].NodeID, codec.EmptyAddress)
+			if err != nil {
+				return err
+			}
+			hutils.Outf(
+				"{{yellow}}%d:{{/}} NodeID=%s StakeEndBlock=%d DelegationCapacity=%s %s\n",
+				i,
+				validators[i].NodeID,
+				stakeEndBlocks[i],
+				hutils.FormatBalance(capacities[i], nconsts.Decimals),
+				nconsts.Symbol,
+			)
+		}
+		// Select validator
+		keyIndex, err := handler.Root().PromptChoice("validator to delegate to", len(validators))
+		if err != nil {
+			return err
+		}
+		validatorChosen := validators[keyIndex]
+		nodeID := validatorChosen.NodeID
+		if capacities[keyIndex] == 0 {
+			hutils.Outf("{{red}}validator cannot take more delegations{{/}}\n")
+			return nil
+		}
 
 		// Get balance info
 		_, _, balance, _, err := handler.GetAssetInfo(ctx, ncli, priv.Address, ids.Empty, true)
 		if balance == 0 || err != nil {
 			return err
 		}
-		if balance < uint64(100*math.Pow10(int(nconsts.Decimals))) {
-			hutils.Outf("{{yellow}} You need a minimum of 100 NAI to register a validator{{/}}\n")
-			return nil
-		}
 
 		// Select staked amount
-		stakedAmount, err := handler.Root().PromptAmount("Staked amount", nconsts.Decimals, balance, nil)
+		stakedAmount, err := handler.Root().PromptAmount("Staked amount", nconsts.Decimals, min(balance, capacities[keyIndex]), nil)
 		if err != nil {
 			return err
 		}
 
 		// Get current block
-		currentBlockHeight, _, _, _, _, _, _, err := ncli.EmissionInfo(ctx)
+		currentBlockHeight, _, _, _, _, _, epochTracker, err := ncli.EmissionInfo(ctx)
 		if err != nil {
 			return err
 		}
+		// Delegations must start by the next epoch boundary
+		nextBoundary := (currentBlockHeight/epochTracker.EpochLength + 1) * epochTracker.EpochLength
 
-		stakeStartBlock := currentBlockHeight + 20 // roughly 1 minute from now
-		stakeEndBlock := stakeStartBlock + 20*2    // roughly 10 minutes from stakeStartBlock
-		delegationFeeRate := 50
+		stakeStartBlock := min(currentBlockHeight+20, nextBoundary) // roughly 1 minute from now
+		stakeEndBlock := stakeStartBlock + 20*2                     // roughly 10 minutes from stakeStartBlock
+		// Delegations can't outlive the validator stake
+		stakeEndBlock = min(stakeEndBlock, stakeEndBlocks[keyIndex])
 		rewardAddress := priv.Address
 
 		if !autoRegister {
 			// Select stakeStartBlock
 			stakeStartBlockString, err := handler.Root().PromptString(
-				fmt.Sprintf("Staking Start Block(must be after %d)", currentBlockHeight),
+				fmt.Sprintf("Staking Start Block(must be after %d and at most %d)", currentBlockHeight, nextBoundary),
 				1,
 				32,
 			)
@@ -675,7 +1839,7 @@ var registerValidatorStakeCmd = &cobra.Command{
 
 			// Select stakeEndBlock
 			stakeEndBlockString, err := handler.Root().PromptString(
-				fmt.Sprintf("Staking End Block(must be after %s)", stakeStartBlockString),
+				fmt.Sprintf("Staking End Block(must be after %s and at most %d)", stakeStartBlockString, stakeEndBlocks[keyIndex]),
 				1,
 				32,
 			)
@@ -687,12 +1851,6 @@ var registerValidatorStakeCmd = &cobra.Command{
 				return err
 			}
 
-			// Select delegationFeeRate
-			delegationFeeRate, err = handler.Root().PromptInt("Delegation Fee Rate(must be over 2)", 100)
-			if err != nil {
-				return err
-			}
-
 			// Select rewardAddress
 			rewardAddress, err = handler.Root().PromptAddress("Reward Address")
 			if err != nil {
@@ -703,11 +1861,8 @@ var registerValidatorStakeCmd = &cobra.Command{
 		if stakeStartBlock < currentBlockHeight {
 			return fmt.Errorf("staking start block must be after the current block height (%d)", currentBlockHeight)
 		}
-		if stakeEndBlock < stakeStartBlock {
-			return fmt.Errorf("staking end block must be after the staking start block height (%d)", stakeStartBlock)
-		}
-		if delegationFeeRate < 2 || delegationFeeRate > 100 {
-			return fmt.Errorf("delegation fee rate must be over 2 and under 100")
+		if stakeStartBlock > nextBoundary {
+			return fmt.Errorf("staking start block must be at most the next epoch boundary (%d)", nextBoundary)
 		}
 
 		// Confirm action
@@ -715,44 +1870,39 @@ var registerValidatorStakeCmd = &cobra.Command{
 		if !cont || err != nil {
 			return err
 		}
-
-		hutils.Outf("{{yellow}}Register Validator Stake Info - stakeStartBlock: %d stakeEndBlock: %d delegationFeeRate: %d rewardAddress: %s\n", stakeStartBlock, stakeEndBlock, delegationFeeRate, codec.MustAddressBech32(nconsts.HRP, rewardAddress))
-
-		stakeInfo := &actions.ValidatorStakeInfo{
-			NodeID:            nodeID.Bytes(),
-			StakeStartBlock:   stakeStartBlock,
-			StakeEndBlock:     stakeEndBlock,
-			StakedAmount:      stakedAmount,
-			DelegationFeeRate: uint64(delegationFeeRate),
-			RewardAddress:     rewardAddress,
-		}
-		stakeInfoBytes, err := stakeInfo.Marshal()
-		if err != nil {
-			return err
-		}
-		authFactory := auth.NewBLSFactory(secretKey)
-		signature, err := authFactory.Sign(stakeInfoBytes)
-		if err != nil {
-			return err
-		}
-		signaturePacker := codec.NewWriter(signature.Size(), signature.Size())
-		signature.Marshal(signaturePacker)
-		authSignature := signaturePacker.Bytes()
-
-		// Generate transaction
-		_, _, err = sendAndWait(ctx, nil, &actions.RegisterValidatorStake{
-			StakeInfo:     stakeInfoBytes,
-			AuthSignature: authSignature,
+
+		// Generate transaction
+		_, _, err = sendAndWait(ctx, nil, &actions.DelegateUserStake{
+			NodeID:          nodeID.Bytes(),
+			StakeStartBlock: stakeStartBlock,
+			StakeEndBlock:   stakeEndBlock,
+			StakedAmount:    stakedAmount,
+			RewardAddress:   rewardAddress,
 		}, hcli, hws, ncli, factory, true)
 		return err
 	},
 }
 
-var getValidatorStakeCmd = &cobra.Command{
-	Use: "get-validator-stake",
+var getUserStakeCmd = &cobra.Command{
+	Use: "get-user-stake [address]",
 	RunE: func(_ *cobra.Command, args []string) error {
 		ctx := context.Background()
 
+		var address codec.Address
+		if len(args) == 0 {
+			_, priv, _, _, _, _, err := handler.DefaultActor()
+			if err != nil {
+				return err
+			}
+			address = priv.Address
+		} else {
+			addr, err := codec.ParseAddressBech32(nconsts.HRP, args[0])
+			if err != nil {
+				return err
+			}
+			address = addr
+		}
+
 		// Get clients
 		nclients, err := handler.DefaultNuklaiVMJSONRPCClient(checkAllChains)
 		if err != nil {
@@ -786,8 +1936,8 @@ var getValidatorStakeCmd = &cobra.Command{
 		validatorChosen := validators[keyIndex]
 		nodeID := validatorChosen.NodeID
 
-		// Get validator stake
-		_, _, _, _, _, _, err = handler.GetValidatorStake(ctx, ncli, nodeID)
+		// Get user stake
+		_, _, _, _, _, err = handler.GetUserStake(ctx, ncli, address, nodeID)
 		if err != nil {
 			return err
 		}
@@ -796,11 +1946,11 @@ var getValidatorStakeCmd = &cobra.Command{
 	},
 }
 
-var claimValidatorStakeRewardCmd = &cobra.Command{
-	Use: "claim-validator-stake-reward",
+var claimUserStakeRewardCmd = &cobra.Command{
+	Use: "claim-user-stake-reward",
 	RunE: func(*cobra.Command, []string) error {
 		ctx := context.Background()
-		_, _, factory, hcli, hws, ncli, err := handler.DefaultActor()
+		_, priv, factory, hcli, hws, ncli, err := handler.DefaultActor()
 		if err != nil {
 			return err
 		}
@@ -825,7 +1975,7 @@ var claimValidatorStakeRewardCmd = &cobra.Command{
 			)
 		}
 		// Select validator
-		keyIndex, err := handler.Root().PromptChoice("validator to claim staking rewards for", len(validators))
+		keyIndex, err := handler.Root().PromptChoice("validator to claim staking rewards from", len(validators))
 		if err != nil {
 			return err
 		}
@@ -833,13 +1983,13 @@ var claimValidatorStakeRewardCmd = &cobra.Command{
 		nodeID := validatorChosen.NodeID
 
 		// Get stake info
-		_, _, stakedAmount, _, _, _, err := ncli.ValidatorStake(ctx, nodeID)
+		_, _, stakedAmount, _, _, _, _, err := ncli.UserStake(ctx, priv.Address, nodeID)
 		if err != nil {
 			return err
 		}
 
 		if stakedAmount == 0 {
-			hutils.Outf("{{red}}validator has not yet staked{{/}}\n")
+			hutils.Outf("{{red}}user has not yet delegated to this validator{{/}}\n")
 			return nil
 		}
 
@@ -850,15 +2000,16 @@ var claimValidatorStakeRewardCmd = &cobra.Command{
 		}
 
 		// Generate transaction
-		_, _, err = sendAndWait(ctx, nil, &actions.ClaimValidatorStakeRewards{
-			NodeID: nodeID.Bytes(),
+		_, _, err = sendAndWait(ctx, nil, &actions.ClaimDelegationStakeRewards{
+			NodeID:           nodeID.Bytes(),
+			UserStakeAddress: priv.Address,
 		}, hcli, hws, ncli, factory, true)
 		return err
 	},
 }
 
-var withdrawValidatorStakeCmd = &cobra.Command{
-	Use: "withdraw-validator-stake",
+var undelegateUserStakeCmd = &cobra.Command{
+	Use: "undelegate-user-stake",
 	RunE: func(*cobra.Command, []string) error {
 		ctx := context.Background()
 		_, priv, factory, hcli, hws, ncli, err := handler.DefaultActor()
@@ -886,7 +2037,7 @@ var withdrawValidatorStakeCmd = &cobra.Command{
 			)
 		}
 		// Select validator
-		keyIndex, err := handler.Root().PromptChoice("validator to withdraw from staking", len(validators))
+		keyIndex, err := handler.Root().PromptChoice("validator to unstake from", len(validators))
 		if err != nil {
 			return err
 		}
@@ -894,13 +2045,13 @@ var withdrawValidatorStakeCmd = &cobra.Command{
 		nodeID := validatorChosen.NodeID
 
 		// Get stake info
-		_, _, stakedAmount, _, _, _, err := ncli.ValidatorStake(ctx, nodeID)
+		_, _, stakedAmount, _, _, _, _, err := ncli.UserStake(ctx, priv.Address, nodeID)
 		if err != nil {
 			return err
 		}
 
 		if stakedAmount == 0 {
-			hutils.Outf("{{red}}validator has not yet been staked{{/}}\n")
+			hutils.Outf("{{red}}user has not yet delegated to this validator{{/}}\n")
 			return nil
 		}
 
@@ -911,7 +2062,7 @@ var withdrawValidatorStakeCmd = &cobra.Command{
 		}
 
 		// Generate transaction
-		_, _, err = sendAndWait(ctx, nil, &actions.WithdrawValidatorStake{
+		_, _, err = sendAndWait(ctx, nil, &actions.UndelegateUserStake{
 			NodeID:        nodeID.Bytes(),
 			RewardAddress: priv.Address,
 		}, hcli, hws, ncli, factory, true)
@@ -919,16 +2070,9 @@ var withdrawValidatorStakeCmd = &cobra.Command{
 	},
 }
 
-var delegateUserStakeCmd = &cobra.Command{
-	Use: "delegate-user-stake [manual | auto]",
-	PreRunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) == 0 || (args[0] != Manual && args[0] != Auto) {
-			return ErrInvalidArgs
-		}
-		return nil
-	},
-	RunE: func(_ *cobra.Command, args []string) error {
-		autoRegister := args[0] == Auto
+var redelegateUserStakeCmd = &cobra.Command{
+	Use: "redelegate-user-stake",
+	RunE: func(*cobra.Command, []string) error {
 		ctx := context.Background()
 		_, priv, factory, hcli, hws, ncli, err := handler.DefaultActor()
 		if err != nil {
@@ -940,12 +2084,13 @@ var delegateUserStakeCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		if len(validators) == 0 {
-			hutils.Outf("{{red}}no validators{{/}}\n")
+		if len(validators) < 2 {
+			hutils.Outf("{{red}}not enough validators to redelegate{{/}}\n")
 			return nil
 		}
-		hutils.Outf("{{cyan}}validators:{{/}} %d\n", len(validators))
 
+		// Show validators to the user
+		hutils.Outf("{{cyan}}validators:{{/}} %d\n", len(validators))
 		for i := 0; i < len(validators); i++ {
 			hutils.Outf(
 				"{{yellow}}%d:{{/}} NodeID=%s\n",
@@ -953,75 +2098,71 @@ var delegateUserStakeCmd = &cobra.Command{
 				validators[i].NodeID,
 			)
 		}
-		// Select validator
-		keyIndex, err := handler.Root().PromptChoice("validator to delegate to", len(validators))
+		// Select validators
+		fromIndex, err := handler.Root().PromptChoice("validator to move the stake from", len(validators))
 		if err != nil {
 			return err
 		}
-		validatorChosen := validators[keyIndex]
-		nodeID := validatorChosen.NodeID
-
-		// Get balance info
-		_, _, balance, _, err := handler.GetAssetInfo(ctx, ncli, priv.Address, ids.Empty, true)
-		if balance == 0 || err != nil {
+		fromNodeID := validators[fromIndex].NodeID
+		toIndex, err := handler.Root().PromptChoice("validator to move the stake to", len(validators))
+		if err != nil {
 			return err
 		}
+		toNodeID := validators[toIndex].NodeID
+		if fromNodeID == toNodeID {
+			hutils.Outf("{{red}}cannot redelegate to the same validator{{/}}\n")
+			return nil
+		}
 
-		// Select staked amount
-		stakedAmount, err := handler.Root().PromptAmount("Staked amount", nconsts.Decimals, balance, nil)
+		// Get stake info
+		_, stakeEndBlock, stakedAmount, _, _, _, _, err := ncli.UserStake(ctx, priv.Address, fromNodeID)
 		if err != nil {
 			return err
 		}
 
+		if stakedAmount == 0 {
+			hutils.Outf("{{red}}user has not yet delegated to this validator{{/}}\n")
+			return nil
+		}
+
 		// Get current block
 		currentBlockHeight, _, _, _, _, _, _, err := ncli.EmissionInfo(ctx)
 		if err != nil {
 			return err
 		}
 
-		stakeStartBlock := currentBlockHeight + 20 // roughly 1 minute from now
-		stakeEndBlock := stakeStartBlock + 20*2    // roughly 10 minutes from stakeStartBlock
-		rewardAddress := priv.Address
-
-		if !autoRegister {
-			// Select stakeStartBlock
-			stakeStartBlockString, err := handler.Root().PromptString(
-				fmt.Sprintf("Staking Start Block(must be after %d)", currentBlockHeight),
-				1,
-				32,
-			)
-			if err != nil {
-				return err
-			}
-
-			stakeStartBlock, err = strconv.ParseUint(stakeStartBlockString, 10, 64)
-			if err != nil {
-				return err
-			}
+		// The stake can't outlive the stake of the new validator
+		_, validatorStakeEndBlock, _, _, _, _, _, _, _, err := ncli.ValidatorStake(ctx, toNodeID, codec.EmptyAddress)
+		if err != nil {
+			return err
+		}
+		// and the stake stays locked up until its current end block
+		if validatorStakeEndBlock < stakeEndBlock {
+			hutils.Outf("{{red}}validator stake ends before the delegation (%d < %d){{/}}\n", validatorStakeEndBlock, stakeEndBlock)
+			return nil
+		}
+		currentStakeEndBlock := stakeEndBlock
 
-			// Select stakeEndBlock
-			stakeEndBlockString, err := handler.Root().PromptString(
-				fmt.Sprintf("Staking End Block(must be after %s)", stakeStartBlockString),
-				1,
-				32,
-			)
-			if err != nil {
-				return err
-			}
+		// Select stakeEndBlock, keeping the current one by default
+		stakeEndBlockString, err := handler.Root().PromptString(
+			fmt.Sprintf("Staking End Block(must be after %d, at least %d and at most %d, current: %d)", currentBlockHeight, currentStakeEndBlock, validatorStakeEndBlock, stakeEndBlock),
+			0,
+			32,
+		)
+		if err != nil {
+			return err
+		}
+		if len(stakeEndBlockString) > 0 {
 			stakeEndBlock, err = strconv.ParseUint(stakeEndBlockString, 10, 64)
 			if err != nil {
 				return err
 			}
-
-			// Select rewardAddress
-			rewardAddress, err = handler.Root().PromptAddress("Reward Address")
-			if err != nil {
-				return err
-			}
 		}
-
-		if stakeStartBlock < currentBlockHeight {
-			return fmt.Errorf("staking start block must be after the current block height (%d)", currentBlockHeight)
+		if stakeEndBlock <= currentBlockHeight {
+			return fmt.Errorf("staking end block must be after the current block height (%d)", currentBlockHeight)
+		}
+		if stakeEndBlock < currentStakeEndBlock {
+			return fmt.Errorf("staking end block must be at least the current end block (%d)", currentStakeEndBlock)
 		}
 
 		// Confirm action
@@ -1031,43 +2172,23 @@ var delegateUserStakeCmd = &cobra.Command{
 		}
 
 		// Generate transaction
-		_, _, err = sendAndWait(ctx, nil, &actions.DelegateUserStake{
-			NodeID:          nodeID.Bytes(),
-			StakeStartBlock: stakeStartBlock,
-			StakeEndBlock:   stakeEndBlock,
-			StakedAmount:    stakedAmount,
-			RewardAddress:   rewardAddress,
+		_, _, err = sendAndWait(ctx, nil, &actions.RedelegateUserStake{
+			FromNodeID:    fromNodeID.Bytes(),
+			ToNodeID:      toNodeID.Bytes(),
+			StakeEndBlock: stakeEndBlock,
 		}, hcli, hws, ncli, factory, true)
 		return err
 	},
 }
 
-var getUserStakeCmd = &cobra.Command{
-	Use: "get-user-stake [address]",
-	RunE: func(_ *cobra.Command, args []string) error {
+var increaseDelegationCmd = &cobra.Command{
+	Use: "increase-delegation",
+	RunE: func(*cobra.Command, []string) error {
 		ctx := context.Background()
-
-		var address codec.Address
-		if len(args) == 0 {
-			_, priv, _, _, _, _, err := handler.DefaultActor()
-			if err != nil {
-				return err
-			}
-			address = priv.Address
-		} else {
-			addr, err := codec.ParseAddressBech32(nconsts.HRP, args[0])
-			if err != nil {
-				return err
-			}
-			address = addr
-		}
-
-		// Get clients
-		nclients, err := handler.DefaultNuklaiVMJSONRPCClient(checkAllChains)
+		_, priv, factory, hcli, hws, ncli, err := handler.DefaultActor()
 		if err != nil {
 			return err
 		}
-		ncli := nclients[0]
 
 		// Get current list of validators
 		validators, err := ncli.StakedValidators(ctx)
@@ -1079,6 +2200,7 @@ var getUserStakeCmd = &cobra.Command{
 			return nil
 		}
 
+		// Show validators to the user
 		hutils.Outf("{{cyan}}validators:{{/}} %d\n", len(validators))
 		for i := 0; i < len(validators); i++ {
 			hutils.Outf(
@@ -1088,25 +2210,54 @@ var getUserStakeCmd = &cobra.Command{
 			)
 		}
 		// Select validator
-		keyIndex, err := handler.Root().PromptChoice("validator to get staking info for", len(validators))
+		keyIndex, err := handler.Root().PromptChoice("validator to add stake to", len(validators))
 		if err != nil {
 			return err
 		}
 		validatorChosen := validators[keyIndex]
 		nodeID := validatorChosen.NodeID
 
-		// Get user stake
-		_, _, _, _, _, err = handler.GetUserStake(ctx, ncli, address, nodeID)
+		// Get stake info
+		_, _, stakedAmount, _, _, _, _, err := ncli.UserStake(ctx, priv.Address, nodeID)
 		if err != nil {
 			return err
 		}
 
-		return nil
+		if stakedAmount == 0 {
+			hutils.Outf("{{red}}user has not yet delegated to this validator{{/}}\n")
+			return nil
+		}
+
+		// Get balance info
+		_, _, balance, _, err := handler.GetAssetInfo(ctx, ncli, priv.Address, ids.Empty, true)
+		if balance == 0 || err != nil {
+			return err
+		}
+
+		// Select amount to add
+		amount, err := handler.Root().PromptAmount("Amount to add", nconsts.Decimals, balance, nil)
+		if err != nil {
+			return err
+		}
+
+		// Confirm action
+		cont, err := handler.Root().PromptContinue()
+		if !cont || err != nil {
+			return err
+		}
+
+		// Generate transaction
+		_, _, err = sendAndWait(ctx, nil, &actions.IncreaseDelegation{
+			NodeID:        nodeID.Bytes(),
+			Amount:        amount,
+			RewardAddress: priv.Address,
+		}, hcli, hws, ncli, factory, true)
+		return err
 	},
 }
 
-var claimUserStakeRewardCmd = &cobra.Command{
-	Use: "claim-user-stake-reward",
+var decreaseDelegationCmd = &cobra.Command{
+	Use: "decrease-delegation",
 	RunE: func(*cobra.Command, []string) error {
 		ctx := context.Background()
 		_, priv, factory, hcli, hws, ncli, err := handler.DefaultActor()
@@ -1134,7 +2285,7 @@ var claimUserStakeRewardCmd = &cobra.Command{
 			)
 		}
 		// Select validator
-		keyIndex, err := handler.Root().PromptChoice("validator to claim staking rewards from", len(validators))
+		keyIndex, err := handler.Root().PromptChoice("validator to take stake off", len(validators))
 		if err != nil {
 			return err
 		}
@@ -1142,7 +2293,7 @@ var claimUserStakeRewardCmd = &cobra.Command{
 		nodeID := validatorChosen.NodeID
 
 		// Get stake info
-		_, _, stakedAmount, _, _, err := ncli.UserStake(ctx, priv.Address, nodeID)
+		_, _, stakedAmount, _, _, _, _, err := ncli.UserStake(ctx, priv.Address, nodeID)
 		if err != nil {
 			return err
 		}
@@ -1152,6 +2303,12 @@ var claimUserStakeRewardCmd = &cobra.Command{
 			return nil
 		}
 
+		// Select amount to take off
+		amount, err := handler.Root().PromptAmount("Amount to take off", nconsts.Decimals, stakedAmount, nil)
+		if err != nil {
+			return err
+		}
+
 		// Confirm action
 		cont, err := handler.Root().PromptContinue()
 		if !cont || err != nil {
@@ -1159,59 +2316,41 @@ var claimUserStakeRewardCmd = &cobra.Command{
 		}
 
 		// Generate transaction
-		_, _, err = sendAndWait(ctx, nil, &actions.ClaimDelegationStakeRewards{
-			NodeID:           nodeID.Bytes(),
-			UserStakeAddress: priv.Address,
+		_, _, err = sendAndWait(ctx, nil, &actions.DecreaseDelegation{
+			NodeID:        nodeID.Bytes(),
+			Amount:        amount,
+			RewardAddress: priv.Address,
 		}, hcli, hws, ncli, factory, true)
 		return err
 	},
 }
 
-var undelegateUserStakeCmd = &cobra.Command{
-	Use: "undelegate-user-stake",
+var releaseUnbondedStakeCmd = &cobra.Command{
+	Use: "release-unbonded-stake",
 	RunE: func(*cobra.Command, []string) error {
 		ctx := context.Background()
-		_, priv, factory, hcli, hws, ncli, err := handler.DefaultActor()
-		if err != nil {
-			return err
-		}
-
-		// Get current list of validators
-		validators, err := ncli.StakedValidators(ctx)
-		if err != nil {
-			return err
-		}
-		if len(validators) == 0 {
-			hutils.Outf("{{red}}no validators{{/}}\n")
-			return nil
-		}
-
-		// Show validators to the user
-		hutils.Outf("{{cyan}}validators:{{/}} %d\n", len(validators))
-		for i := 0; i < len(validators); i++ {
-			hutils.Outf(
-				"{{yellow}}%d:{{/}} NodeID=%s\n",
-				i,
-				validators[i].NodeID,
-			)
-		}
-		// Select validator
-		keyIndex, err := handler.Root().PromptChoice("validator to unstake from", len(validators))
+		_, _, factory, hcli, hws, ncli, err := handler.DefaultActor()
 		if err != nil {
 			return err
 		}
-		validatorChosen := validators[keyIndex]
-		nodeID := validatorChosen.NodeID
 
-		// Get stake info
-		_, _, stakedAmount, _, _, err := ncli.UserStake(ctx, priv.Address, nodeID)
+		// Validator stake unbonds in the queue of the validator it was taken
+		// off, and delegations in the queue of the actor
+		release := &actions.ReleaseUnbondedStake{}
+		validatorStake, err := handler.Root().PromptBool("release stake taken off a validator you own")
 		if err != nil {
 			return err
 		}
-
-		if stakedAmount == 0 {
-			hutils.Outf("{{red}}user has not yet delegated to this validator{{/}}\n")
-			return nil
+		if validatorStake {
+			nodeIDStr, err := handler.Root().PromptString("nodeID", 1, 100)
+			if err != nil {
+				return err
+			}
+			nodeID, err := ids.NodeIDFromString(nodeIDStr)
+			if err != nil {
+				return err
+			}
+			release.NodeID = nodeID.Bytes()
 		}
 
 		// Confirm action
@@ -1221,10 +2360,7 @@ var undelegateUserStakeCmd = &cobra.Command{
 		}
 
 		// Generate transaction
-		_, _, err = sendAndWait(ctx, nil, &actions.UndelegateUserStake{
-			NodeID:        nodeID.Bytes(),
-			RewardAddress: priv.Address,
-		}, hcli, hws, ncli, factory, true)
+		_, _, err = sendAndWait(ctx, nil, release, hcli, hws, ncli, factory, true)
 		return err
 	},
 }
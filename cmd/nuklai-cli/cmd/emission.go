@@ -5,8 +5,18 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
+	"os"
 
 	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+	hutils "github.com/ava-labs/hypersdk/utils"
+
+	"github.com/nuklai/nuklaivm/actions"
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/genesis"
 )
 
 var emissionCmd = &cobra.Command{
@@ -81,3 +91,157 @@ var emissionStakedValidatorsCmd = &cobra.Command{
 		return nil
 	},
 }
+
+var emissionClaimFeesCmd = &cobra.Command{
+	Use: "claim-fees",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, priv, factory, hcli, hws, ncli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+
+		// Get emission info
+		_, _, _, _, _, _, emissionAddress, accumulatedFees, err := handler.GetEmissionInfo(ctx, ncli)
+		if err != nil {
+			return err
+		}
+		if emissionAddress != codec.MustAddressBech32(nconsts.HRP, priv.Address) {
+			hutils.Outf("{{red}}only the emission address can claim the emission fees{{/}}\n")
+			return nil
+		}
+		if accumulatedFees == 0 {
+			hutils.Outf("{{red}}no fees to claim{{/}}\n")
+			return nil
+		}
+
+		// Confirm action
+		cont, err := handler.Root().PromptContinue()
+		if !cont || err != nil {
+			return err
+		}
+
+		// Generate transaction
+		_, _, err = sendAndWait(ctx, nil, &actions.ClaimEmissionFees{}, hcli, hws, ncli, factory, true)
+		return err
+	},
+}
+
+var emissionSlashHistoryCmd = &cobra.Command{
+	Use: "slash-history [nodeID]",
+	RunE: func(_ *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		// Leave the nodeID empty for the history of all validators
+		nodeID := ids.EmptyNodeID
+		if len(args) > 0 {
+			var err error
+			nodeID, err = ids.NodeIDFromString(args[0])
+			if err != nil {
+				return err
+			}
+		}
+
+		// Get clients
+		nclients, err := handler.DefaultNuklaiVMJSONRPCClient(checkAllChains)
+		if err != nil {
+			return err
+		}
+		ncli := nclients[0]
+
+		// Get slash history
+		_, err = handler.GetSlashHistory(ctx, ncli, nodeID)
+		return err
+	},
+}
+
+var emissionInvariantCheckCmd = &cobra.Command{
+	Use: "invariant-check",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+
+		// Get clients
+		nclients, err := handler.DefaultNuklaiVMJSONRPCClient(checkAllChains)
+		if err != nil {
+			return err
+		}
+		ncli := nclients[0]
+
+		// Compare the supply of NAI with the NAI held
+		_, err = handler.InvariantCheck(ctx, ncli)
+		return err
+	},
+}
+
+var emissionRewardsCmd = &cobra.Command{
+	Use: "rewards [nodeID | address]",
+	RunE: func(_ *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		if len(args) != 1 {
+			return ErrInvalidArgs
+		}
+
+		// Get clients
+		nclients, err := handler.DefaultNuklaiVMJSONRPCClient(checkAllChains)
+		if err != nil {
+			return err
+		}
+		ncli := nclients[0]
+
+		// Get the reward history of a validator, or of the delegations of an
+		// address
+		if nodeID, err := ids.NodeIDFromString(args[0]); err == nil {
+			_, err = handler.GetRewardHistory(ctx, ncli, nodeID, rewardsFromEpoch, rewardsToEpoch, rewardsCSV)
+			return err
+		}
+		owner, err := codec.ParseAddressBech32(nconsts.HRP, args[0])
+		if err != nil {
+			return err
+		}
+		_, err = handler.GetDelegatorRewardHistory(ctx, ncli, owner, rewardsFromEpoch, rewardsToEpoch, rewardsCSV)
+		return err
+	},
+}
+
+var emissionSimulateCmd = &cobra.Command{
+	Use:   "simulate [genesis file] [scenario file]",
+	Short: "Projects the supply, APR and rewards of a scenario offline",
+	PreRunE: func(_ *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return ErrInvalidArgs
+		}
+		return nil
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		// Read genesis file
+		gb, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		g, err := genesis.New(gb, nil)
+		if err != nil {
+			return err
+		}
+
+		// Read scenario file
+		sb, err := os.ReadFile(args[1])
+		if err != nil {
+			return err
+		}
+		scenario := &simulationScenario{}
+		if err := json.Unmarshal(sb, scenario); err != nil {
+			return err
+		}
+		if err := scenario.verify(); err != nil {
+			return err
+		}
+
+		// Run the emission balancer through the scenario
+		epochs, err := runSimulation(g, scenario)
+		if err != nil {
+			return err
+		}
+		return writeSimulation(os.Stdout, scenario, epochs, simulateJSON)
+	},
+}
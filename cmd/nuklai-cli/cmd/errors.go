@@ -11,4 +11,5 @@ var (
 	ErrInvalidAddress    = errors.New("invalid address")
 	ErrInvalidKeyType    = errors.New("invalid key type")
 	ErrMustFill          = errors.New("must fill")
+	ErrInvalidScenario   = errors.New("invalid scenario")
 )
@@ -76,7 +76,7 @@ var genGenesisCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		emissionBalancer := genesis.EmissionBalancer{}
+		emissionBalancer := g.EmissionBalancer
 		if err := json.Unmarshal(eb, &emissionBalancer); err != nil {
 			return err
 		}
@@ -6,7 +6,11 @@ package cmd
 import (
 	"context"
 	"encoding/base64"
+	"encoding/csv"
 	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/hypersdk/chain"
@@ -24,6 +28,7 @@ import (
 	nconsts "github.com/nuklai/nuklaivm/consts"
 	"github.com/nuklai/nuklaivm/emission"
 	nrpc "github.com/nuklai/nuklaivm/rpc"
+	"github.com/nuklai/nuklaivm/storage"
 )
 
 var _ cli.Controller = (*Controller)(nil)
@@ -76,7 +81,7 @@ func (*Handler) GetAssetInfo(
 	checkBalance bool,
 ) ([]byte, uint8, uint64, ids.ID, error) {
 	var sourceChainID ids.ID
-	exists, symbol, decimals, metadata, supply, _, warp, err := ncli.Asset(ctx, assetID, false)
+	exists, symbol, decimals, metadata, supply, maxSupply, _, frozen, warp, err := ncli.Asset(ctx, assetID, false)
 	if err != nil {
 		return nil, 0, 0, ids.Empty, err
 	}
@@ -97,11 +102,13 @@ func (*Handler) GetAssetInfo(
 			)
 		} else {
 			hutils.Outf(
-				"{{yellow}}symbol:{{/}} %s {{yellow}}decimals:{{/}} %d {{yellow}}metadata:{{/}} %s {{yellow}}supply:{{/}} %d {{yellow}}warp:{{/}} %t\n",
+				"{{yellow}}symbol:{{/}} %s {{yellow}}decimals:{{/}} %d {{yellow}}metadata:{{/}} %s {{yellow}}supply:{{/}} %d {{yellow}}maxSupply:{{/}} %d {{yellow}}frozen:{{/}} %t {{yellow}}warp:{{/}} %t\n",
 				symbol,
 				decimals,
 				metadata,
 				supply,
+				maxSupply,
+				frozen,
 				warp,
 			)
 		}
@@ -249,10 +256,12 @@ func (*Handler) GetAllValidators(
 			return nil, err
 		}
 		hutils.Outf(
-			"{{yellow}}validator %d:{{/}} NodeID=%s PublicKey=%s StakedAmount=%d AccumulatedStakedReward=%d DelegationFeeRate=%f DelegatedAmount=%d AccumulatedDelegatedReward=%d\n",
+			"{{yellow}}validator %d:{{/}} NodeID=%s PublicKey=%s Rank=%d Waitlisted=%t StakedAmount=%d AccumulatedStakedReward=%d DelegationFeeRate=%d DelegatedAmount=%d AccumulatedDelegatedReward=%d\n",
 			index,
 			validator.NodeID,
 			base64.StdEncoding.EncodeToString(publicKey.Compress()),
+			validator.Rank,
+			validator.Waitlisted,
 			validator.StakedAmount,
 			validator.AccumulatedStakedReward,
 			validator.DelegationFeeRate,
@@ -277,27 +286,190 @@ func (*Handler) GetStakedValidators(
 			return nil, err
 		}
 		hutils.Outf(
-			"{{yellow}}validator %d:{{/}} NodeID=%s PublicKey=%s Active=%t StakedAmount=%d AccumulatedStakedReward=%d DelegationFeeRate=%f DelegatedAmount=%d AccumulatedDelegatedReward=%d\n",
+			"{{yellow}}validator %d:{{/}} NodeID=%s PublicKey=%s Active=%t Rank=%d Waitlisted=%t StakedAmount=%d AccumulatedStakedReward=%d DelegationFeeRate=%d DelegatedAmount=%d AccumulatedDelegatedReward=%d JailedUntil=%d Offences=%d SlashedAmount=%d PendingDelegationFeeRate=%d FeeRateEffectiveBlock=%d\n",
 			index,
 			validator.NodeID,
 			base64.StdEncoding.EncodeToString(publicKey.Compress()),
 			validator.IsActive,
+			validator.Rank,
+			validator.Waitlisted,
 			validator.StakedAmount,
 			validator.AccumulatedStakedReward,
 			validator.DelegationFeeRate,
 			validator.DelegatedAmount,
 			validator.AccumulatedDelegatedReward,
+			validator.JailedUntil,
+			validator.Offences,
+			validator.SlashedAmount,
+			validator.PendingDelegationFeeRate,
+			validator.FeeRateEffectiveBlock,
 		)
 	}
 	return validators, nil
 }
 
+func (*Handler) GetSlashHistory(
+	ctx context.Context,
+	cli *nrpc.JSONRPCClient,
+	nodeID ids.NodeID,
+) ([]*emission.SlashEvent, error) {
+	slashes, err := cli.SlashHistory(ctx, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	if len(slashes) == 0 {
+		hutils.Outf("{{yellow}}no uptime offences{{/}}\n")
+		return slashes, nil
+	}
+	for index, slash := range slashes {
+		hutils.Outf(
+			"{{yellow}}offence %d:{{/}} NodeID=%s BlockHeight=%d Epoch=%d Uptime=%d.%02d%% Offences=%d JailedUntil=%d SlashedAmount=%s %s\n",
+			index,
+			slash.NodeID,
+			slash.BlockHeight,
+			slash.Epoch,
+			slash.Uptime/100,
+			slash.Uptime%100,
+			slash.Offences,
+			slash.JailedUntil,
+			hutils.FormatBalance(slash.SlashedAmount, nconsts.Decimals),
+			nconsts.Symbol,
+		)
+	}
+	return slashes, nil
+}
+
+func (*Handler) InvariantCheck(
+	ctx context.Context,
+	cli *nrpc.JSONRPCClient,
+) ([]string, error) {
+	check, err := cli.InvariantCheck(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hutils.Outf(
+		"{{yellow}}supply:{{/}} TotalSupply=%s NativeSupply=%s UnclaimedRewards=%s %s\n",
+		hutils.FormatBalance(check.TotalSupply, nconsts.Decimals),
+		hutils.FormatBalance(check.NativeSupply, nconsts.Decimals),
+		hutils.FormatBalance(check.UnclaimedRewards, nconsts.Decimals),
+		nconsts.Symbol,
+	)
+	hutils.Outf(
+		"{{yellow}}held in state:{{/}} Balances=%s Loans=%s Staked=%s Unbonding=%s %s\n",
+		hutils.FormatBalance(check.Holdings.Balances, nconsts.Decimals),
+		hutils.FormatBalance(check.Holdings.Loans, nconsts.Decimals),
+		hutils.FormatBalance(check.Holdings.Staked, nconsts.Decimals),
+		hutils.FormatBalance(check.Holdings.Unbonding, nconsts.Decimals),
+		nconsts.Symbol,
+	)
+	if len(check.Discrepancies) == 0 {
+		hutils.Outf("{{green}}no discrepancies{{/}}\n")
+		return check.Discrepancies, nil
+	}
+	for _, discrepancy := range check.Discrepancies {
+		hutils.Outf("{{red}}discrepancy:{{/}} %s\n", discrepancy)
+	}
+	return check.Discrepancies, nil
+}
+
+func (*Handler) GetRewardHistory(
+	ctx context.Context,
+	cli *nrpc.JSONRPCClient,
+	nodeID ids.NodeID,
+	fromEpoch uint64,
+	toEpoch uint64,
+	asCSV bool,
+) ([]*emission.EpochReward, error) {
+	rewards, err := cli.RewardHistory(ctx, nodeID, fromEpoch, toEpoch)
+	if err != nil {
+		return nil, err
+	}
+	header := []string{"Epoch", "MintedReward", "FeeReward", "ValidatorReward", "DelegationReward", "DelegatedWeight"}
+	rows := make([][]string, 0, len(rewards))
+	for _, reward := range rewards {
+		rows = append(rows, []string{
+			strconv.FormatUint(reward.Epoch, 10),
+			formatRewardAmount(reward.MintedReward, asCSV),
+			formatRewardAmount(reward.FeeReward, asCSV),
+			formatRewardAmount(reward.ValidatorReward, asCSV),
+			formatRewardAmount(reward.DelegationReward, asCSV),
+			strconv.FormatUint(reward.DelegatedWeight, 10),
+		})
+	}
+	return rewards, printRewards(header, rows, asCSV)
+}
+
+func (*Handler) GetDelegatorRewardHistory(
+	ctx context.Context,
+	cli *nrpc.JSONRPCClient,
+	owner codec.Address,
+	fromEpoch uint64,
+	toEpoch uint64,
+	asCSV bool,
+) ([]*emission.DelegatorEpochReward, error) {
+	rewards, err := cli.DelegatorRewardHistory(ctx, owner, fromEpoch, toEpoch)
+	if err != nil {
+		return nil, err
+	}
+	header := []string{"NodeID", "Epoch", "Reward", "Settled"}
+	rows := make([][]string, 0, len(rewards))
+	for _, reward := range rewards {
+		rows = append(rows, []string{
+			reward.NodeID.String(),
+			strconv.FormatUint(reward.Epoch, 10),
+			formatRewardAmount(reward.Reward, asCSV),
+			strconv.FormatBool(reward.Settled),
+		})
+	}
+	return rewards, printRewards(header, rows, asCSV)
+}
+
+// formatRewardAmount formats NAI amounts in base units for CSV output, so that
+// they can be summed up exactly, and in NAI otherwise.
+func formatRewardAmount(amount uint64, asCSV bool) string {
+	if asCSV {
+		return strconv.FormatUint(amount, 10)
+	}
+	return hutils.FormatBalance(amount, nconsts.Decimals)
+}
+
+// printRewards prints the reward history to stdout as CSV or as a table.
+func printRewards(header []string, rows [][]string, asCSV bool) error {
+	if asCSV {
+		w := csv.NewWriter(os.Stdout)
+		if err := w.Write(header); err != nil {
+			return err
+		}
+		if err := w.WriteAll(rows); err != nil {
+			return err
+		}
+		return w.Error()
+	}
+	if len(rows) == 0 {
+		hutils.Outf("{{yellow}}no rewards{{/}}\n")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	for _, row := range append([][]string{header}, rows...) {
+		for _, column := range row {
+			if _, err := fmt.Fprintf(w, "%s\t", column); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
 func (*Handler) GetValidatorStake(
 	ctx context.Context,
 	cli *nrpc.JSONRPCClient,
 	nodeID ids.NodeID,
+	owner codec.Address,
 ) (uint64, uint64, uint64, uint64, string, string, error) {
-	stakeStartBlock, stakeEndBlock, stakedAmount, delegationFeeRate, rewardAddress, ownerAddress, err := cli.ValidatorStake(ctx, nodeID)
+	stakeStartBlock, stakeEndBlock, stakedAmount, delegationFeeRate, rewardAddress, ownerAddress, lockupMultiplier, delegationCapacity, unbonding, err := cli.ValidatorStake(ctx, nodeID, owner)
 	if err != nil {
 		return 0, 0, 0, 0, "", "", err
 	}
@@ -312,14 +484,18 @@ func (*Handler) GetValidatorStake(
 	}
 
 	hutils.Outf(
-		"{{yellow}}validator stake: {{/}}\nStakeStartBlock=%d StakeEndBlock=%d StakedAmount=%d DelegationFeeRate=%d RewardAddress=%s OwnerAddress=%s\n",
+		"{{yellow}}validator stake: {{/}}\nStakeStartBlock=%d StakeEndBlock=%d StakedAmount=%d DelegationFeeRate=%d RewardAddress=%s OwnerAddress=%s LockupMultiplier=%d.%02dx DelegationCapacity=%d\n",
 		stakeStartBlock,
 		stakeEndBlock,
 		stakedAmount,
 		delegationFeeRate,
 		rewardAddressString,
 		ownerAddressString,
+		lockupMultiplier/10_000,
+		lockupMultiplier%10_000/100,
+		delegationCapacity,
 	)
+	printUnbonding(unbonding)
 	return stakeStartBlock,
 		stakeEndBlock,
 		stakedAmount,
@@ -331,7 +507,7 @@ func (*Handler) GetValidatorStake(
 func (*Handler) GetUserStake(ctx context.Context,
 	cli *nrpc.JSONRPCClient, owner codec.Address, nodeID ids.NodeID,
 ) (uint64, uint64, uint64, string, string, error) {
-	stakeStartBlock, stakeEndBlock, stakedAmount, rewardAddress, ownerAddress, err := cli.UserStake(ctx, owner, nodeID)
+	stakeStartBlock, stakeEndBlock, stakedAmount, rewardAddress, ownerAddress, lockupMultiplier, unbonding, err := cli.UserStake(ctx, owner, nodeID)
 	if err != nil {
 		return 0, 0, 0, "", "", err
 	}
@@ -346,13 +522,16 @@ func (*Handler) GetUserStake(ctx context.Context,
 	}
 
 	hutils.Outf(
-		"{{yellow}}user stake: {{/}}\nStakeStartBlock=%d StakeEndBlock=%d StakedAmount=%d RewardAddress=%s OwnerAddress=%s\n",
+		"{{yellow}}user stake: {{/}}\nStakeStartBlock=%d StakeEndBlock=%d StakedAmount=%d RewardAddress=%s OwnerAddress=%s LockupMultiplier=%d.%02dx\n",
 		stakeStartBlock,
 		stakeEndBlock,
 		stakedAmount,
 		rewardAddressString,
 		ownerAddressString,
+		lockupMultiplier/10_000,
+		lockupMultiplier%10_000/100,
 	)
+	printUnbonding(unbonding)
 	return stakeStartBlock,
 		stakeEndBlock,
 		stakedAmount,
@@ -360,6 +539,126 @@ func (*Handler) GetUserStake(ctx context.Context,
 		ownerAddressString, err
 }
 
+func printUnbonding(unbonding []*storage.UnbondingEntry) {
+	for index, entry := range unbonding {
+		hutils.Outf(
+			"{{yellow}}unbonding %d:{{/}} Amount=%s %s ReleaseBlock=%d\n",
+			index,
+			hutils.FormatBalance(entry.Amount, nconsts.Decimals),
+			nconsts.Symbol,
+			entry.ReleaseBlock,
+		)
+	}
+}
+
+func (*Handler) GetNFTCollection(
+	ctx context.Context,
+	cli *nrpc.JSONRPCClient,
+	collection ids.ID,
+) (bool, []byte, []byte, uint64, string, error) {
+	exists, symbol, metadata, supply, owner, err := cli.NFTCollection(ctx, collection)
+	if err != nil {
+		return false, nil, nil, 0, "", err
+	}
+	if !exists {
+		hutils.Outf("{{red}}%s does not exist{{/}}\n", collection)
+		return false, nil, nil, 0, "", nil
+	}
+	hutils.Outf(
+		"{{yellow}}symbol:{{/}} %s {{yellow}}metadata:{{/}} %s {{yellow}}supply:{{/}} %d {{yellow}}owner:{{/}} %s\n",
+		symbol,
+		metadata,
+		supply,
+		owner,
+	)
+	return true, symbol, metadata, supply, owner, nil
+}
+
+func (*Handler) GetNFT(
+	ctx context.Context,
+	cli *nrpc.JSONRPCClient,
+	collection ids.ID,
+	id uint64,
+) (bool, string, []byte, error) {
+	exists, owner, metadata, err := cli.NFT(ctx, collection, id)
+	if err != nil {
+		return false, "", nil, err
+	}
+	if !exists {
+		hutils.Outf("{{red}}nft %d of %s does not exist{{/}}\n", id, collection)
+		return false, "", nil, nil
+	}
+	hutils.Outf(
+		"{{yellow}}collection:{{/}} %s {{yellow}}id:{{/}} %d {{yellow}}owner:{{/}} %s {{yellow}}metadata:{{/}} %s\n",
+		collection,
+		id,
+		owner,
+		metadata,
+	)
+	return true, owner, metadata, nil
+}
+
+func (*Handler) GetNFTsOfOwner(
+	ctx context.Context,
+	cli *nrpc.JSONRPCClient,
+	addr string,
+) ([]*storage.OwnedNFT, error) {
+	var (
+		nfts  []*storage.OwnedNFT
+		start *storage.OwnedNFT
+	)
+	for {
+		page, next, err := cli.NFTsOfOwner(ctx, addr, start, 0)
+		if err != nil {
+			return nil, err
+		}
+		nfts = append(nfts, page...)
+		if next == nil {
+			break
+		}
+		start = next
+	}
+	if len(nfts) == 0 {
+		hutils.Outf("{{red}}no nfts held by %s{{/}}\n", addr)
+		return nil, nil
+	}
+	hutils.Outf("{{cyan}}nfts:{{/}} %d\n", len(nfts))
+	for index, nft := range nfts {
+		hutils.Outf(
+			"{{yellow}}%d:{{/}} Collection=%s ID=%d\n",
+			index,
+			nft.Collection,
+			nft.ID,
+		)
+	}
+	return nfts, nil
+}
+
+func (*Handler) GetAllowance(
+	ctx context.Context,
+	cli *nrpc.JSONRPCClient,
+	owner string,
+	spender string,
+	asset ids.ID,
+	decimals uint8,
+) (uint64, uint64, bool, error) {
+	amount, expiryBlock, expired, err := cli.Allowance(ctx, owner, spender, asset)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	if amount == 0 {
+		hutils.Outf("{{red}}%s has no allowance from %s{{/}}\n", spender, owner)
+		return 0, 0, false, nil
+	}
+	hutils.Outf(
+		"{{yellow}}allowance:{{/}} %s {{yellow}}expiryBlock:{{/}} %d {{yellow}}expired:{{/}} %t\n",
+		hutils.FormatBalance(amount, decimals),
+		expiryBlock,
+		expired,
+	)
+	return amount, expiryBlock, expired, nil
+}
+
 var _ cli.Controller = (*Controller)(nil)
 
 type Controller struct {
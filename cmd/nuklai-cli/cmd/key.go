@@ -275,6 +275,48 @@ var balanceKeyCmd = &cobra.Command{
 	},
 }
 
+var allowanceKeyCmd = &cobra.Command{
+	Use: "allowance [owner]",
+	RunE: func(_ *cobra.Command, args []string) error {
+		var owner string
+		if len(args) == 0 {
+			_, priv, _, _, _, _, err := handler.DefaultActor()
+			if err != nil {
+				return err
+			}
+			owner = codec.MustAddressBech32(nconsts.HRP, priv.Address)
+		} else {
+			if _, err := codec.ParseAddressBech32(nconsts.HRP, args[0]); err != nil {
+				return err
+			}
+			owner = args[0]
+		}
+		hutils.Outf("{{yellow}}owner:{{/}} %s\n", owner)
+		nclients, err := handler.DefaultNuklaiVMJSONRPCClient(checkAllChains)
+		if err != nil {
+			return err
+		}
+		spender, err := handler.h.PromptAddress("spender")
+		if err != nil {
+			return err
+		}
+		assetID, err := handler.h.PromptAsset("assetID", true)
+		if err != nil {
+			return err
+		}
+		for _, ncli := range nclients {
+			_, decimals, _, _, err := handler.GetAssetInfo(context.TODO(), ncli, spender, assetID, false)
+			if err != nil {
+				return err
+			}
+			if _, _, _, err := handler.GetAllowance(context.TODO(), ncli, owner, codec.MustAddressBech32(nconsts.HRP, spender), assetID, decimals); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
 func generateRandomData(n int) ([]byte, error) {
 	data := make([]byte, n)
 	_, err := rand.Read(data)
@@ -0,0 +1,112 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/hypersdk/codec"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+)
+
+var nftCmd = &cobra.Command{
+	Use: "nft",
+	RunE: func(*cobra.Command, []string) error {
+		return ErrMissingSubcommand
+	},
+}
+
+var nftCollectionCmd = &cobra.Command{
+	Use: "collection",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+
+		// Get clients
+		nclients, err := handler.DefaultNuklaiVMJSONRPCClient(checkAllChains)
+		if err != nil {
+			return err
+		}
+		ncli := nclients[0]
+
+		// Select collection
+		collectionID, err := handler.Root().PromptID("collectionID")
+		if err != nil {
+			return err
+		}
+
+		// Get collection info
+		_, _, _, _, _, err = handler.GetNFTCollection(ctx, ncli, collectionID)
+		return err
+	},
+}
+
+var nftInfoCmd = &cobra.Command{
+	Use: "info",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+
+		// Get clients
+		nclients, err := handler.DefaultNuklaiVMJSONRPCClient(checkAllChains)
+		if err != nil {
+			return err
+		}
+		ncli := nclients[0]
+
+		// Select collection
+		collectionID, err := handler.Root().PromptID("collectionID")
+		if err != nil {
+			return err
+		}
+
+		// Select NFT ID
+		idString, err := handler.Root().PromptString("nft id", 1, 20)
+		if err != nil {
+			return err
+		}
+		id, err := strconv.ParseUint(idString, 10, 64)
+		if err != nil {
+			return err
+		}
+
+		// Get NFT info
+		_, _, _, err = handler.GetNFT(ctx, ncli, collectionID, id)
+		return err
+	},
+}
+
+var nftOwnedCmd = &cobra.Command{
+	Use: "owned [address]",
+	RunE: func(_ *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		var address string
+		if len(args) == 0 {
+			_, priv, _, _, _, _, err := handler.DefaultActor()
+			if err != nil {
+				return err
+			}
+			address = codec.MustAddressBech32(nconsts.HRP, priv.Address)
+		} else {
+			if _, err := codec.ParseAddressBech32(nconsts.HRP, args[0]); err != nil {
+				return err
+			}
+			address = args[0]
+		}
+
+		// Get clients
+		nclients, err := handler.DefaultNuklaiVMJSONRPCClient(checkAllChains)
+		if err != nil {
+			return err
+		}
+		ncli := nclients[0]
+
+		// Get NFTs of owner
+		_, err = handler.GetNFTsOfOwner(ctx, ncli, address)
+		return err
+	},
+}
@@ -65,9 +65,13 @@ func handleTx(ncli *nrpc.JSONRPCClient, tx *chain.Transaction, result *chain.Res
 	status := "❌" //nolint:ineffassign // reason for ignoring
 	if result.Success {
 		status = "✅"
+		output := result.Output
+		if actions.ChangesEmission(tx.Action) {
+			_, output, _ = actions.SplitEmissionOutput(result.Output)
+		}
 		switch action := tx.Action.(type) {
 		case *actions.Transfer:
-			_, symbol, decimals, _, _, _, _, err := ncli.Asset(context.TODO(), action.Asset, true)
+			_, symbol, decimals, _, _, _, _, _, _, err := ncli.Asset(context.TODO(), action.Asset, true)
 			if err != nil {
 				utils.Outf("{{red}}could not fetch asset info:{{/}} %v", err)
 				return
@@ -78,10 +82,24 @@ func handleTx(ncli *nrpc.JSONRPCClient, tx *chain.Transaction, result *chain.Res
 				summaryStr += fmt.Sprintf(" (memo: %s)", action.Memo)
 			}
 
+		case *actions.MultiTransfer:
+			totals := map[ids.ID]uint64{}
+			for _, transfer := range action.Transfers {
+				totals[transfer.Asset] += transfer.Value
+			}
+			summaryStr = fmt.Sprintf("transfers: %d", len(action.Transfers))
+			for assetID, total := range totals {
+				_, symbol, decimals, _, _, _, _, _, _, err := ncli.Asset(context.TODO(), assetID, true)
+				if err != nil {
+					utils.Outf("{{red}}could not fetch asset info:{{/}} %v", err)
+					return
+				}
+				summaryStr += fmt.Sprintf(" | %s %s", utils.FormatBalance(total, decimals), symbol)
+			}
 		case *actions.CreateAsset:
-			summaryStr = fmt.Sprintf("assetID: %s symbol: %s decimals: %d metadata: %s", tx.ID(), action.Symbol, action.Decimals, action.Metadata)
+			summaryStr = fmt.Sprintf("assetID: %s symbol: %s decimals: %d metadata: %s maxSupply: %d", tx.ID(), action.Symbol, action.Decimals, action.Metadata, action.MaxSupply)
 		case *actions.MintAsset:
-			_, symbol, decimals, _, _, _, _, err := ncli.Asset(context.TODO(), action.Asset, true)
+			_, symbol, decimals, _, _, _, _, _, _, err := ncli.Asset(context.TODO(), action.Asset, true)
 			if err != nil {
 				utils.Outf("{{red}}could not fetch asset info:{{/}} %v", err)
 				return
@@ -90,6 +108,43 @@ func handleTx(ncli *nrpc.JSONRPCClient, tx *chain.Transaction, result *chain.Res
 			summaryStr = fmt.Sprintf("%s %s -> %s", amountStr, symbol, codec.MustAddressBech32(nconsts.HRP, action.To))
 		case *actions.BurnAsset:
 			summaryStr = fmt.Sprintf("%d %s -> 🔥", action.Value, action.Asset)
+		case *actions.UpdateAsset:
+			ownerStr := "renounced"
+			if action.Owner != codec.EmptyAddress {
+				ownerStr = codec.MustAddressBech32(nconsts.HRP, action.Owner)
+			}
+			summaryStr = fmt.Sprintf("assetID: %s owner: %s", action.Asset, ownerStr)
+			if len(action.Metadata) > 0 {
+				summaryStr += fmt.Sprintf(" metadata: %s", action.Metadata)
+			}
+			if action.FreezeMint {
+				summaryStr += " (minting frozen)"
+			}
+		case *actions.CreateNFTCollection:
+			summaryStr = fmt.Sprintf("collectionID: %s symbol: %s metadata: %s", tx.ID(), action.Symbol, action.Metadata)
+		case *actions.MintNFT:
+			summaryStr = fmt.Sprintf("collectionID: %s id: %d metadata: %s -> %s", action.Collection, action.ID, action.Metadata, codec.MustAddressBech32(nconsts.HRP, action.To))
+		case *actions.TransferNFT:
+			summaryStr = fmt.Sprintf("collectionID: %s id: %d -> %s", action.Collection, action.ID, codec.MustAddressBech32(nconsts.HRP, action.To))
+		case *actions.BurnNFT:
+			summaryStr = fmt.Sprintf("collectionID: %s id: %d -> 🔥", action.Collection, action.ID)
+		case *actions.ApproveAsset:
+			_, symbol, decimals, _, _, _, _, _, _, err := ncli.Asset(context.TODO(), action.Asset, true)
+			if err != nil {
+				utils.Outf("{{red}}could not fetch asset info:{{/}} %v", err)
+				return
+			}
+			summaryStr = fmt.Sprintf("spender: %s allowance: %s %s", codec.MustAddressBech32(nconsts.HRP, action.Spender), utils.FormatBalance(action.Value, decimals), symbol)
+			if action.ExpiryBlock != 0 {
+				summaryStr += fmt.Sprintf(" (expires at block %d)", action.ExpiryBlock)
+			}
+		case *actions.TransferFrom:
+			_, symbol, decimals, _, _, _, _, _, _, err := ncli.Asset(context.TODO(), action.Asset, true)
+			if err != nil {
+				utils.Outf("{{red}}could not fetch asset info:{{/}} %v", err)
+				return
+			}
+			summaryStr = fmt.Sprintf("%s %s %s -> %s", utils.FormatBalance(action.Value, decimals), symbol, codec.MustAddressBech32(nconsts.HRP, action.From), codec.MustAddressBech32(nconsts.HRP, action.To))
 		case *actions.ImportAsset:
 			wm := tx.WarpMessage
 			signers, _ := wm.Signature.NumSigners()
@@ -104,7 +159,7 @@ func handleTx(ncli *nrpc.JSONRPCClient, tx *chain.Transaction, result *chain.Res
 				summaryStr += fmt.Sprintf(" | reward: %s", utils.FormatBalance(wt.Reward, wt.Decimals))
 			}
 			if wt.SwapIn > 0 {
-				_, outSymbol, outDecimals, _, _, _, _, err := ncli.Asset(context.TODO(), wt.AssetOut, true)
+				_, outSymbol, outDecimals, _, _, _, _, _, _, err := ncli.Asset(context.TODO(), wt.AssetOut, true)
 				if err != nil {
 					utils.Outf("{{red}}could not fetch asset info:{{/}} %v", err)
 					return
@@ -126,7 +181,7 @@ func handleTx(ncli *nrpc.JSONRPCClient, tx *chain.Transaction, result *chain.Res
 				summaryStr += fmt.Sprintf(" | reward: %s", utils.FormatBalance(wt.Reward, wt.Decimals))
 			}
 			if wt.SwapIn > 0 {
-				_, outSymbol, outDecimals, _, _, _, _, err := ncli.Asset(context.TODO(), wt.AssetOut, true)
+				_, outSymbol, outDecimals, _, _, _, _, _, _, err := ncli.Asset(context.TODO(), wt.AssetOut, true)
 				if err != nil {
 					utils.Outf("{{red}}could not fetch asset info:{{/}} %v", err)
 					return
@@ -153,6 +208,27 @@ func handleTx(ncli *nrpc.JSONRPCClient, tx *chain.Transaction, result *chain.Res
 		case *actions.UndelegateUserStake:
 			nodeID, _ := ids.ToNodeID(action.NodeID)
 			summaryStr = fmt.Sprintf("nodeID: %s rewardAddress: %s", nodeID.String(), codec.MustAddressBech32(nconsts.HRP, action.RewardAddress))
+		case *actions.RedelegateUserStake:
+			fromNodeID, _ := ids.ToNodeID(action.FromNodeID)
+			toNodeID, _ := ids.ToNodeID(action.ToNodeID)
+			stakeResult, _ := actions.UnmarshalRedelegateUserStakeResult(output)
+			summaryStr = fmt.Sprintf("fromNodeID: %s toNodeID: %s stakedAmount: %s rewardAmount: %s stakeEndBlock: %d", fromNodeID.String(), toNodeID.String(), utils.FormatBalance(stakeResult.StakedAmount, nconsts.Decimals), utils.FormatBalance(stakeResult.RewardAmount, nconsts.Decimals), action.StakeEndBlock)
+		case *actions.IncreaseDelegation, *actions.DecreaseDelegation, *actions.IncreaseValidatorStake, *actions.DecreaseValidatorStake:
+			stakeResult, _ := actions.UnmarshalChangeStakeResult(output)
+			summaryStr = fmt.Sprintf("stakedAmount: %s rewardAmount: %s", utils.FormatBalance(stakeResult.StakedAmount, nconsts.Decimals), utils.FormatBalance(stakeResult.RewardAmount, nconsts.Decimals))
+		case *actions.UpdateValidatorStake:
+			nodeID, _ := ids.ToNodeID(action.NodeID)
+			updateResult, _ := actions.UnmarshalUpdateValidatorStakeResult(output)
+			summaryStr = fmt.Sprintf("nodeID: %s stakeEndBlock: %d delegationFeeRate: %d (from block %d) rewardAddress: %s", nodeID.String(), action.StakeEndBlock, action.DelegationFeeRate, updateResult.FeeRateEffectiveBlock, codec.MustAddressBech32(nconsts.HRP, action.RewardAddress))
+		case *actions.ValidatorHeartbeat:
+			nodeID, _ := ids.ToNodeID(action.NodeID)
+			summaryStr = fmt.Sprintf("nodeID: %s", nodeID.String())
+		case *actions.ReleaseUnbondedStake:
+			releaseResult, _ := actions.UnmarshalReleaseUnbondedStakeResult(output)
+			summaryStr = fmt.Sprintf("releasedAmount: %s", utils.FormatBalance(releaseResult.ReleasedAmount, nconsts.Decimals))
+		case *actions.ClaimEmissionFees:
+			feeResult, _ := actions.UnmarshalClaimRewardsResult(output)
+			summaryStr = fmt.Sprintf("feeAmount: %s", utils.FormatBalance(feeResult.RewardAmount, nconsts.Decimals))
 		}
 		utils.Outf(
 			"%s {{yellow}}%s{{/}} {{yellow}}actor:{{/}} %s {{yellow}}summary (%s):{{/}} [%s] {{yellow}}fee (max %.2f%%):{{/}} %s %s {{yellow}}consumed:{{/}} [%s]\n",
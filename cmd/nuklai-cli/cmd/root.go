@@ -38,6 +38,10 @@ var (
 	startPrometheus       bool
 	maxFee                int64
 	numCores              int
+	rewardsFromEpoch      uint64
+	rewardsToEpoch        uint64
+	rewardsCSV            bool
+	simulateJSON          bool
 
 	rootCmd = &cobra.Command{
 		Use:        "nuklai-cli",
@@ -54,6 +58,7 @@ func init() {
 		chainCmd,
 		actionCmd,
 		emissionCmd,
+		nftCmd,
 		spamCmd,
 		prometheusCmd,
 	)
@@ -131,6 +136,7 @@ func init() {
 		importKeyCmd,
 		setKeyCmd,
 		balanceKeyCmd,
+		allowanceKeyCmd,
 		vanityAddressCmd,
 	)
 
@@ -153,22 +159,46 @@ func init() {
 	// actions
 	actionCmd.AddCommand(
 		transferCmd,
+		multiTransferCmd,
 
 		createAssetCmd,
 		mintAssetCmd,
+		updateAssetCmd,
 		// burnAssetCmd,
 		importAssetCmd,
 		exportAssetCmd,
+		approveAssetCmd,
+		transferFromCmd,
+
+		createNFTCollectionCmd,
+		mintNFTCmd,
+		transferNFTCmd,
+		burnNFTCmd,
 
 		registerValidatorStakeCmd,
 		getValidatorStakeCmd,
 		claimValidatorStakeRewardCmd,
 		withdrawValidatorStakeCmd,
+		updateValidatorStakeCmd,
+		increaseValidatorStakeCmd,
+		decreaseValidatorStakeCmd,
+		validatorHeartbeatCmd,
 
 		delegateUserStakeCmd,
 		getUserStakeCmd,
 		claimUserStakeRewardCmd,
 		undelegateUserStakeCmd,
+		redelegateUserStakeCmd,
+		increaseDelegationCmd,
+		decreaseDelegationCmd,
+		releaseUnbondedStakeCmd,
+	)
+
+	// nft
+	nftCmd.AddCommand(
+		nftCollectionCmd,
+		nftInfoCmd,
+		nftOwnedCmd,
 	)
 
 	// emission
@@ -176,6 +206,35 @@ func init() {
 		emissionInfoCmd,
 		emissionAllValidatorsCmd,
 		emissionStakedValidatorsCmd,
+		emissionClaimFeesCmd,
+		emissionSlashHistoryCmd,
+		emissionRewardsCmd,
+		emissionInvariantCheckCmd,
+		emissionSimulateCmd,
+	)
+	emissionRewardsCmd.PersistentFlags().Uint64Var(
+		&rewardsFromEpoch,
+		"from-epoch",
+		0,
+		"first epoch of the reward history",
+	)
+	emissionRewardsCmd.PersistentFlags().Uint64Var(
+		&rewardsToEpoch,
+		"to-epoch",
+		0,
+		"last epoch of the reward history (0 for up to 1024 epochs from the first)",
+	)
+	emissionRewardsCmd.PersistentFlags().BoolVar(
+		&rewardsCSV,
+		"csv",
+		false,
+		"print the reward history as CSV",
+	)
+	emissionSimulateCmd.PersistentFlags().BoolVar(
+		&simulateJSON,
+		"json",
+		false,
+		"print the simulation as JSON instead of CSV",
 	)
 
 	// spam
@@ -0,0 +1,342 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/hypersdk/codec"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/emission"
+	"github.com/nuklai/nuklaivm/genesis"
+)
+
+// simulationScenario describes the stakes and fees `nuklai-cli emission
+// simulate` runs the emission balancer against. Amounts are in base units.
+type simulationScenario struct {
+	Blocks         uint64                 `json:"blocks"`         // Number of blocks to simulate
+	BlockTime      int64                  `json:"blockTime"`      // Time between blocks, in milliseconds
+	StartTimestamp int64                  `json:"startTimestamp"` // Timestamp of the first block, in milliseconds. Defaults to now
+	Validators     []*simulationValidator `json:"validators"`
+	Delegators     []*simulationDelegator `json:"delegators"`
+	Fees           []*simulationFees      `json:"fees"`
+}
+
+type simulationValidator struct {
+	Name              string `json:"name"`
+	StakeStartBlock   uint64 `json:"stakeStartBlock"`
+	StakeEndBlock     uint64 `json:"stakeEndBlock"`
+	ExitBlock         uint64 `json:"exitBlock"` // Block the stake is withdrawn at, 0 to never withdraw it
+	StakedAmount      uint64 `json:"stakedAmount"`
+	DelegationFeeRate uint64 `json:"delegationFeeRate"`
+}
+
+type simulationDelegator struct {
+	Name            string `json:"name"`
+	Validator       string `json:"validator"` // Name of the validator the stake is delegated to
+	StakeStartBlock uint64 `json:"stakeStartBlock"`
+	StakeEndBlock   uint64 `json:"stakeEndBlock"`
+	ExitBlock       uint64 `json:"exitBlock"` // Block the stake is undelegated at, 0 to never undelegate it
+	StakedAmount    uint64 `json:"stakedAmount"`
+}
+
+type simulationFees struct {
+	FromBlock   uint64 `json:"fromBlock"`
+	ToBlock     uint64 `json:"toBlock"` // 0 for every block from FromBlock onwards
+	FeePerBlock uint64 `json:"feePerBlock"`
+}
+
+// simulationEpoch is the state of the emission balancer at the end of an epoch.
+type simulationEpoch struct {
+	Epoch       uint64            `json:"epoch"`
+	BlockHeight uint64            `json:"blockHeight"`
+	Timestamp   int64             `json:"timestamp"`
+	TotalSupply uint64            `json:"totalSupply"`
+	TotalStaked uint64            `json:"totalStaked"`
+	APR         uint64            `json:"apr"`     // In basis points
+	Rewards     map[string]uint64 `json:"rewards"` // Rewards earned so far by every participant, claimed or not
+}
+
+func (s *simulationScenario) verify() error {
+	if s.Blocks == 0 || s.BlockTime <= 0 {
+		return fmt.Errorf("%w: blocks and block time must be over 0", ErrInvalidScenario)
+	}
+	names := map[string]bool{}
+	for _, v := range s.Validators {
+		if names[v.Name] {
+			return fmt.Errorf("%w: duplicate participant %q", ErrInvalidScenario, v.Name)
+		}
+		if v.StakeStartBlock == 0 || v.StakeStartBlock >= v.StakeEndBlock {
+			return fmt.Errorf("%w: stake of %q must start after block 0 and before it ends", ErrInvalidScenario, v.Name)
+		}
+		names[v.Name] = true
+	}
+	for _, d := range s.Delegators {
+		if names[d.Name] {
+			return fmt.Errorf("%w: duplicate participant %q", ErrInvalidScenario, d.Name)
+		}
+		if d.StakeStartBlock == 0 || d.StakeStartBlock >= d.StakeEndBlock {
+			return fmt.Errorf("%w: stake of %q must start after block 0 and before it ends", ErrInvalidScenario, d.Name)
+		}
+		validator := false
+		for _, v := range s.Validators {
+			validator = validator || v.Name == d.Validator
+		}
+		if !validator {
+			return fmt.Errorf("%w: %q delegates to unknown validator %q", ErrInvalidScenario, d.Name, d.Validator)
+		}
+		names[d.Name] = true
+	}
+	return nil
+}
+
+// feeAt returns the fees paid in block [height].
+func (s *simulationScenario) feeAt(height uint64) uint64 {
+	fee := uint64(0)
+	for _, f := range s.Fees {
+		if height >= f.FromBlock && (f.ToBlock == 0 || height <= f.ToBlock) {
+			fee += f.FeePerBlock
+		}
+	}
+	return fee
+}
+
+// simulationNodeID and simulationAddress derive the node ID and address of a
+// participant from its name.
+func simulationNodeID(name string) ids.NodeID {
+	return ids.NodeID(hashing.ComputeHash160Array([]byte(name)))
+}
+
+func simulationAddress(name string) codec.Address {
+	return codec.CreateAddress(nconsts.ED25519ID, hashing.ComputeHash256Array([]byte(name)))
+}
+
+// simulationDelegation is the delegation of a delegator in a simulation, as
+// it would be kept in state.
+type simulationDelegation struct {
+	rewardWeight uint64
+	rewardIndex  emission.RewardIndex
+}
+
+// simulationValidatorState is a validator in a simulation: its stake, nil
+// once it is withdrawn, and its record, as they would be kept in state.
+type simulationValidatorState struct {
+	stake  *emission.Stake
+	record *emission.ValidatorRecord
+}
+
+// runSimulation runs the emission balancer of [g] block by block through
+// [s], the same way the transactions of every block do, and returns its state
+// at the end of every epoch. The fees of every block are swept into the
+// emission balancer at the next epoch boundary, as if they were all collected
+// in the fee shard swept at it.
+func runSimulation(g *genesis.Genesis, s *simulationScenario) ([]*simulationEpoch, error) {
+	emissionAddr, err := codec.ParseAddressBech32(nconsts.HRP, g.EmissionBalancer.EmissionAddress)
+	if err != nil {
+		return nil, err
+	}
+	supply := uint64(0)
+	for _, alloc := range g.CustomAllocation {
+		supply += alloc.Balance
+	}
+	startTimestamp := s.StartTimestamp
+	if startTimestamp == 0 {
+		startTimestamp = time.Now().UnixMilli()
+	}
+
+	header := emission.NewHeader(g.EmissionBalancer.MaxSupply, emissionAddr)
+	activeSet := &emission.ActiveSet{}
+	loadActiveSet := func() (*emission.ActiveSet, error) { return activeSet, nil }
+	validators := map[string]*simulationValidatorState{}
+	delegations := map[string]*simulationDelegation{}
+	claimed := map[string]uint64{}
+	unsweptFees := uint64(0)
+	epochs := []*simulationEpoch{}
+	for height := uint64(1); height <= s.Blocks; height++ {
+		timestamp := startTimestamp + int64(height-1)*s.BlockTime
+		rules := g.Rules(timestamp, 0, ids.Empty)
+		stakingConfig := emission.GetStakingConfig(rules)
+		epochTracker := emission.GetEpochTracker(rules)
+		epochLength := epochTracker.EpochLength
+		if header.ProcessesBoundary(height, epochTracker) {
+			header.Process(height, timestamp, supply, epochTracker)
+			header.CollectFees(unsweptFees, emission.GetFeeSplit(rules))
+			supply -= unsweptFees
+			unsweptFees = 0
+		} else {
+			header.Process(height, timestamp, 0, epochTracker)
+		}
+		unsweptFees += s.feeAt(height)
+		for _, v := range s.Validators {
+			if validator := validators[v.Name]; validator != nil {
+				validator.record.Sync(simulationNodeID(v.Name), validator.stake, header, stakingConfig, epochTracker, &emission.Ledger{})
+				supply -= validator.record.SettleSlash(validator.stake, header)
+			}
+		}
+
+		// Stakes join and leave after the epoch boundary of the block is
+		// processed, as if their transactions were included in it
+		for _, v := range s.Validators {
+			nodeID := simulationNodeID(v.Name)
+			switch height {
+			case v.StakeStartBlock:
+				validators[v.Name] = &simulationValidatorState{
+					stake: &emission.Stake{
+						StakeStartBlock:   v.StakeStartBlock,
+						StakeEndBlock:     v.StakeEndBlock,
+						StakedAmount:      v.StakedAmount,
+						DelegationFeeRate: v.DelegationFeeRate,
+					},
+					record: emission.NewValidatorRecord(header, epochTracker),
+				}
+			case v.ExitBlock:
+				validator := validators[v.Name]
+				if validator == nil || validator.stake == nil {
+					continue
+				}
+				rewardAmount, err := validator.record.Withdraw(nodeID, height-1, header, loadActiveSet, stakingConfig, epochTracker)
+				if err != nil {
+					return nil, err
+				}
+				supply += rewardAmount
+				validator.stake = nil
+				if validator.record.Close(header) {
+					delete(validators, v.Name)
+				}
+				claimed[v.Name] += rewardAmount
+			}
+		}
+		for _, d := range s.Delegators {
+			nodeID := simulationNodeID(d.Validator)
+			validator := validators[d.Validator]
+			if validator == nil {
+				continue
+			}
+			switch height {
+			case d.StakeStartBlock:
+				if validator.stake == nil {
+					continue
+				}
+				rewardWeight := stakingConfig.StakeWeight(d.StakedAmount, d.StakeStartBlock, d.StakeEndBlock, stakingConfig.MaxDelegatorStakeDuration)
+				rewardIndex, _ := validator.record.Delegate(d.StakedAmount, rewardWeight, epochLength)
+				if err := validator.record.UpdateRegistration(nodeID, validator.stake, header, loadActiveSet, stakingConfig, epochTracker); err != nil {
+					return nil, err
+				}
+				delegations[d.Name] = &simulationDelegation{
+					rewardWeight: rewardWeight,
+					rewardIndex:  rewardIndex,
+				}
+			case d.ExitBlock:
+				delegation := delegations[d.Name]
+				if delegation == nil {
+					continue
+				}
+				rewardAmount, _, _ := validator.record.SettleDelegation(header, delegation.rewardWeight, delegation.rewardIndex, d.StakeStartBlock, epochLength)
+				validator.record.Undelegate(d.StakedAmount, delegation.rewardWeight, d.StakeStartBlock)
+				if err := validator.record.UpdateRegistration(nodeID, validator.stake, header, loadActiveSet, stakingConfig, epochTracker); err != nil {
+					return nil, err
+				}
+				if validator.stake == nil && validator.record.Close(header) {
+					delete(validators, d.Validator)
+				}
+				supply += rewardAmount
+				delete(delegations, d.Name)
+				claimed[d.Name] += rewardAmount
+			}
+		}
+
+		// Simulated validators never miss a heartbeat, and register for every
+		// epoch boundary with it
+		for _, v := range s.Validators {
+			validator := validators[v.Name]
+			if validator == nil || validator.stake == nil {
+				continue
+			}
+			validator.record.RecordHeartbeat(height, stakingConfig, epochTracker)
+			if err := validator.record.Register(simulationNodeID(v.Name), validator.stake, height, header, loadActiveSet, stakingConfig, epochTracker); err != nil {
+				return nil, err
+			}
+		}
+
+		if height%epochLength != 0 {
+			continue
+		}
+		totalSupply := header.TotalSupply(supply)
+		epoch := &simulationEpoch{
+			Epoch:       height / epochLength,
+			BlockHeight: height,
+			Timestamp:   timestamp,
+			TotalSupply: totalSupply,
+			TotalStaked: header.RegisteredStake,
+			APR:         header.GetAPRForValidators(totalSupply, epochTracker),
+			Rewards:     make(map[string]uint64, len(s.Validators)+len(s.Delegators)),
+		}
+		for _, v := range s.Validators {
+			// Participants that left or did not join yet have no pending rewards
+			pending := uint64(0)
+			if validator := validators[v.Name]; validator != nil {
+				pending = validator.record.AccumulatedStakedReward
+			}
+			epoch.Rewards[v.Name] = claimed[v.Name] + pending
+		}
+		for _, d := range s.Delegators {
+			pending := uint64(0)
+			if delegation := delegations[d.Name]; delegation != nil {
+				pending = validators[d.Validator].record.PendingDelegationRewards(delegation.rewardWeight, delegation.rewardIndex, d.StakeStartBlock)
+			}
+			epoch.Rewards[d.Name] = claimed[d.Name] + pending
+		}
+		epochs = append(epochs, epoch)
+	}
+	return epochs, nil
+}
+
+// writeSimulation writes the state of the emission balancer at the end of every
+// epoch to [w] as JSON, or as CSV with a column per participant.
+func writeSimulation(w io.Writer, s *simulationScenario, epochs []*simulationEpoch, asJSON bool) error {
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(epochs)
+	}
+
+	names := make([]string, 0, len(s.Validators)+len(s.Delegators))
+	for _, v := range s.Validators {
+		names = append(names, v.Name)
+	}
+	for _, d := range s.Delegators {
+		names = append(names, d.Name)
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(append([]string{"epoch", "blockHeight", "timestamp", "totalSupply", "totalStaked", "apr"}, names...)); err != nil {
+		return err
+	}
+	for _, epoch := range epochs {
+		row := []string{
+			strconv.FormatUint(epoch.Epoch, 10),
+			strconv.FormatUint(epoch.BlockHeight, 10),
+			strconv.FormatInt(epoch.Timestamp, 10),
+			strconv.FormatUint(epoch.TotalSupply, 10),
+			strconv.FormatUint(epoch.TotalStaked, 10),
+			strconv.FormatUint(epoch.APR, 10),
+		}
+		for _, name := range names {
+			row = append(row, strconv.FormatUint(epoch.Rewards[name], 10))
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
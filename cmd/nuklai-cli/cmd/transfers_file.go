@@ -0,0 +1,130 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+	hutils "github.com/ava-labs/hypersdk/utils"
+
+	"github.com/nuklai/nuklaivm/actions"
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	nrpc "github.com/nuklai/nuklaivm/rpc"
+)
+
+// transferRecord is a single transfer of a transfers file. [Asset] is either
+// an assetID or NAI (empty defaults to NAI) and [Amount] is formatted with the
+// decimals of the asset, like in the prompts.
+type transferRecord struct {
+	To     string `json:"to"`
+	Asset  string `json:"asset"`
+	Amount string `json:"amount"`
+	Memo   string `json:"memo"`
+}
+
+// readTransfersFile reads the transfers of a JSON file (an array of
+// [transferRecord]) or of a CSV file (to,asset,amount[,memo] rows with an
+// optional header).
+func readTransfersFile(path string) ([]*transferRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		records := []*transferRecord{}
+		if err := json.NewDecoder(f).Decode(&records); err != nil {
+			return nil, err
+		}
+		return records, nil
+	}
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	records := []*transferRecord{}
+	for line := 1; ; line++ {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if line == 1 && strings.EqualFold(row[0], "to") {
+			continue
+		}
+		if len(row) < 3 || len(row) > 4 {
+			return nil, fmt.Errorf("%w: line %d must be to,asset,amount[,memo]", ErrInvalidArgs, line)
+		}
+		record := &transferRecord{To: row[0], Asset: row[1], Amount: row[2]}
+		if len(row) == 4 {
+			record.Memo = row[3]
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// parseTransfers converts [records] into the entries of a [actions.MultiTransfer]
+// and returns the total sent of each asset.
+func parseTransfers(
+	ctx context.Context,
+	ncli *nrpc.JSONRPCClient,
+	records []*transferRecord,
+) ([]*actions.TransferEntry, map[ids.ID]uint64, error) {
+	decimals := map[ids.ID]uint8{}
+	totals := map[ids.ID]uint64{}
+	entries := make([]*actions.TransferEntry, 0, len(records))
+	for i, record := range records {
+		to, err := codec.ParseAddressBech32(nconsts.HRP, record.To)
+		if err != nil {
+			return nil, nil, fmt.Errorf("transfer %d: %w", i, err)
+		}
+		assetID := ids.Empty
+		if record.Asset != "" && record.Asset != nconsts.Symbol {
+			assetID, err = ids.FromString(record.Asset)
+			if err != nil {
+				return nil, nil, fmt.Errorf("transfer %d: %w", i, err)
+			}
+		}
+		assetDecimals, ok := decimals[assetID]
+		if !ok {
+			exists, _, d, _, _, _, _, _, _, err := ncli.Asset(ctx, assetID, true)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !exists {
+				return nil, nil, fmt.Errorf("transfer %d: %s does not exist", i, assetID)
+			}
+			assetDecimals = d
+			decimals[assetID] = d
+		}
+		amount, err := hutils.ParseBalance(record.Amount, assetDecimals)
+		if err != nil {
+			return nil, nil, fmt.Errorf("transfer %d: %w", i, err)
+		}
+		if len(record.Memo) > actions.MaxMemoSize {
+			return nil, nil, fmt.Errorf("transfer %d: memo is too large", i)
+		}
+		totals[assetID] += amount
+		entries = append(entries, &actions.TransferEntry{
+			To:    to,
+			Asset: assetID,
+			Value: amount,
+			Memo:  []byte(record.Memo),
+		})
+	}
+	return entries, totals, nil
+}
@@ -61,10 +61,11 @@ type Config struct {
 	MempoolExemptSponsors []string `json:"mempoolExemptSponsors"`
 
 	// Misc
-	VerifyAuth        bool          `json:"verifyAuth"`
-	StoreTransactions bool          `json:"storeTransactions"`
-	TestMode          bool          `json:"testMode"` // makes gossip/building manual
-	LogLevel          logging.Level `json:"logLevel"`
+	VerifyAuth           bool          `json:"verifyAuth"`
+	StoreTransactions    bool          `json:"storeTransactions"`
+	EnableInvariantCheck bool          `json:"enableInvariantCheck"` // serves invariantCheck, which walks all of state
+	TestMode             bool          `json:"testMode"`             // makes gossip/building manual
+	LogLevel             logging.Level `json:"logLevel"`
 
 	// State Sync
 	StateSyncServerDelay time.Duration `json:"stateSyncServerDelay"` // for testing
@@ -149,6 +150,7 @@ func (c *Config) GetContinuousProfilerConfig() *profiler.Config {
 		MaxNumFiles: defaultContinuousProfilerMaxFiles,
 	}
 }
-func (c *Config) GetVerifyAuth() bool        { return c.VerifyAuth }
-func (c *Config) GetStoreTransactions() bool { return c.StoreTransactions }
-func (c *Config) Loaded() bool               { return c.loaded }
+func (c *Config) GetVerifyAuth() bool           { return c.VerifyAuth }
+func (c *Config) GetStoreTransactions() bool    { return c.StoreTransactions }
+func (c *Config) GetEnableInvariantCheck() bool { return c.EnableInvariantCheck }
+func (c *Config) Loaded() bool                  { return c.loaded }
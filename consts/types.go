@@ -20,6 +20,27 @@ const (
 	DelegateUserStakeID          uint8 = 9
 	ClaimDelegationStakeRewards  uint8 = 10
 	UndelegateUserStakeID        uint8 = 11
+	ClaimEmissionFeesID          uint8 = 12
+	ValidatorHeartbeatID         uint8 = 13
+	ReleaseUnbondedStakeID       uint8 = 14
+	RedelegateUserStakeID        uint8 = 15
+	IncreaseDelegationID         uint8 = 16
+	DecreaseDelegationID         uint8 = 17
+	IncreaseValidatorStakeID     uint8 = 18
+	DecreaseValidatorStakeID     uint8 = 19
+	UpdateValidatorStakeID       uint8 = 20
+
+	UpdateAssetID uint8 = 21
+
+	CreateNFTCollectionID uint8 = 22
+	MintNFTID             uint8 = 23
+	TransferNFTID         uint8 = 24
+	BurnNFTID             uint8 = 25
+
+	ApproveAssetID uint8 = 26
+	TransferFromID uint8 = 27
+
+	MultiTransferID uint8 = 28
 
 	// Auth TypeIDs
 	ED25519ID   uint8 = 0
@@ -10,6 +10,7 @@ import (
 
 	ametrics "github.com/ava-labs/avalanchego/api/metrics"
 	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/hypersdk/builder"
 	"github.com/ava-labs/hypersdk/chain"
@@ -145,23 +146,12 @@ func (c *Controller) Initialize(
 	}
 
 	// Initialize emission balancer
-	emissionAddr, err := codec.ParseAddressBech32(nconsts.HRP, c.genesis.EmissionBalancer.EmissionAddress)
-	if err != nil {
-		return nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, err
-	}
-
-	// Get the total supply from the custom allocations
-	totalSupply := uint64(0)
-	for _, alloc := range c.genesis.CustomAllocation {
-		totalSupply += alloc.Balance
-	}
-	c.emission = emission.New(c, c.inner, totalSupply, c.genesis.EmissionBalancer.MaxSupply, emissionAddr)
+	c.emission = emission.New(c, c.inner)
 
 	return c.config, c.genesis, build, gossip, blockDB, stateDB, apis, nconsts.ActionRegistry, nconsts.AuthRegistry, auth.Engines(), nil
 }
 
 func (c *Controller) Rules(t int64) chain.Rules {
-	// TODO: extend with [UpgradeBytes]
 	return c.genesis.Rules(t, c.snowCtx.NetworkID, c.snowCtx.ChainID)
 }
 
@@ -173,6 +163,13 @@ func (c *Controller) Accepted(ctx context.Context, blk *chain.StatelessBlock) er
 	batch := c.metaDB.NewBatch()
 	defer batch.Reset()
 
+	// Rewards paid out to delegations are recorded in the ledger at the epoch
+	// of the block that settled them
+	rules := c.Rules(blk.Tmstmp)
+	epoch := blk.Hght / emission.GetEpochTracker(rules).EpochLength
+	settled := &delegatorRewards{}
+	ledger := &emission.Ledger{}
+
 	totalFee := uint64(0)
 	results := blk.Results()
 	for i, tx := range blk.Txs {
@@ -192,10 +189,26 @@ func (c *Controller) Accepted(ctx context.Context, blk *chain.StatelessBlock) er
 			}
 		}
 		totalFee += result.Fee
+
 		if result.Success {
+			// Transactions that changed the emission balancer return what it
+			// distributed and slashed ahead of their own output
+			output := result.Output
+			if actions.ChangesEmission(tx.Action) {
+				txLedger, actionOutput, err := actions.SplitEmissionOutput(result.Output)
+				if err != nil {
+					// This should never happen
+					return err
+				}
+				ledger.Merge(txLedger)
+				output = actionOutput
+			}
+
 			switch action := tx.Action.(type) {
 			case *actions.Transfer:
 				c.metrics.transfer.Inc()
+			case *actions.MultiTransfer:
+				c.metrics.multiTransfer.Inc()
 			case *actions.CreateAsset:
 				c.metrics.createAsset.Inc()
 			case *actions.MintAsset:
@@ -206,6 +219,20 @@ func (c *Controller) Accepted(ctx context.Context, blk *chain.StatelessBlock) er
 				c.metrics.importAsset.Inc()
 			case *actions.ExportAsset:
 				c.metrics.exportAsset.Inc()
+			case *actions.UpdateAsset:
+				c.metrics.updateAsset.Inc()
+			case *actions.CreateNFTCollection:
+				c.metrics.createNFTCollection.Inc()
+			case *actions.MintNFT:
+				c.metrics.mintNFT.Inc()
+			case *actions.TransferNFT:
+				c.metrics.transferNFT.Inc()
+			case *actions.BurnNFT:
+				c.metrics.burnNFT.Inc()
+			case *actions.ApproveAsset:
+				c.metrics.approveAsset.Inc()
+			case *actions.TransferFrom:
+				c.metrics.transferFrom.Inc()
 			case *actions.RegisterValidatorStake:
 				stakeInfo, err := actions.UnmarshalValidatorStakeInfo(action.StakeInfo)
 				if err != nil {
@@ -215,7 +242,7 @@ func (c *Controller) Accepted(ctx context.Context, blk *chain.StatelessBlock) er
 				c.metrics.validatorStakeAmount.Add(float64(stakeInfo.StakedAmount))
 				c.metrics.registerValidatorStake.Inc()
 			case *actions.ClaimValidatorStakeRewards:
-				rewardResult, err := actions.UnmarshalClaimRewardsResult(result.Output)
+				rewardResult, err := actions.UnmarshalClaimRewardsResult(output)
 				if err != nil {
 					// This should never happen
 					return err
@@ -224,7 +251,7 @@ func (c *Controller) Accepted(ctx context.Context, blk *chain.StatelessBlock) er
 				c.metrics.rewardAmount.Add(float64(rewardResult.RewardAmount))
 				c.metrics.claimStakingRewards.Inc()
 			case *actions.WithdrawValidatorStake:
-				stakeResult, err := actions.UnmarshalWithdrawValidatorStakeResult(result.Output)
+				stakeResult, err := actions.UnmarshalWithdrawValidatorStakeResult(output)
 				if err != nil {
 					// This should never happen
 					return err
@@ -238,7 +265,7 @@ func (c *Controller) Accepted(ctx context.Context, blk *chain.StatelessBlock) er
 				c.metrics.delegatorStakeAmount.Add(float64(action.StakedAmount))
 				c.metrics.delegateUserStake.Inc()
 			case *actions.ClaimDelegationStakeRewards:
-				rewardResult, err := actions.UnmarshalClaimRewardsResult(result.Output)
+				rewardResult, err := actions.UnmarshalClaimRewardsResult(output)
 				if err != nil {
 					// This should never happen
 					return err
@@ -246,8 +273,12 @@ func (c *Controller) Accepted(ctx context.Context, blk *chain.StatelessBlock) er
 				c.metrics.mintedNAI.Add(float64(rewardResult.RewardAmount))
 				c.metrics.rewardAmount.Add(float64(rewardResult.RewardAmount))
 				c.metrics.claimStakingRewards.Inc()
+				if err := settled.add(action.UserStakeAddress, action.NodeID, epoch, rewardResult.RewardAmount); err != nil {
+					// This should never happen
+					return err
+				}
 			case *actions.UndelegateUserStake:
-				stakeResult, err := actions.UnmarshalUndelegateUserStakeResult(result.Output)
+				stakeResult, err := actions.UnmarshalUndelegateUserStakeResult(output)
 				if err != nil {
 					// This should never happen
 					return err
@@ -257,29 +288,201 @@ func (c *Controller) Accepted(ctx context.Context, blk *chain.StatelessBlock) er
 				c.metrics.rewardAmount.Add(float64(stakeResult.RewardAmount))
 				c.metrics.claimStakingRewards.Inc()
 				c.metrics.undelegateUserStake.Inc()
+				if err := settled.add(tx.Auth.Actor(), action.NodeID, epoch, stakeResult.RewardAmount); err != nil {
+					// This should never happen
+					return err
+				}
+			case *actions.ClaimEmissionFees:
+				feeResult, err := actions.UnmarshalClaimRewardsResult(output)
+				if err != nil {
+					// This should never happen
+					return err
+				}
+				c.metrics.feesClaimed.Add(float64(feeResult.RewardAmount))
+				c.metrics.claimEmissionFees.Inc()
+			case *actions.ValidatorHeartbeat:
+				c.metrics.validatorHeartbeat.Inc()
+			case *actions.ReleaseUnbondedStake:
+				c.metrics.releaseUnbondedStake.Inc()
+			case *actions.RedelegateUserStake:
+				stakeResult, err := actions.UnmarshalRedelegateUserStakeResult(output)
+				if err != nil {
+					// This should never happen
+					return err
+				}
+				c.metrics.delegatorStakeAmount.Add(float64(stakeResult.RewardAmount))
+				c.metrics.redelegateUserStake.Inc()
+				if err := settled.add(tx.Auth.Actor(), action.FromNodeID, epoch, stakeResult.RewardAmount); err != nil {
+					// This should never happen
+					return err
+				}
+			case *actions.IncreaseDelegation:
+				stakeResult, err := actions.UnmarshalChangeStakeResult(output)
+				if err != nil {
+					// This should never happen
+					return err
+				}
+				c.metrics.delegatorStakeAmount.Add(float64(action.Amount))
+				c.metrics.mintedNAI.Add(float64(stakeResult.RewardAmount))
+				c.metrics.rewardAmount.Add(float64(stakeResult.RewardAmount))
+				c.metrics.claimStakingRewards.Inc()
+				c.metrics.increaseDelegation.Inc()
+				if err := settled.add(tx.Auth.Actor(), action.NodeID, epoch, stakeResult.RewardAmount); err != nil {
+					// This should never happen
+					return err
+				}
+			case *actions.DecreaseDelegation:
+				stakeResult, err := actions.UnmarshalChangeStakeResult(output)
+				if err != nil {
+					// This should never happen
+					return err
+				}
+				c.metrics.delegatorStakeAmount.Sub(float64(action.Amount))
+				c.metrics.mintedNAI.Add(float64(stakeResult.RewardAmount))
+				c.metrics.rewardAmount.Add(float64(stakeResult.RewardAmount))
+				c.metrics.claimStakingRewards.Inc()
+				c.metrics.decreaseDelegation.Inc()
+				if err := settled.add(tx.Auth.Actor(), action.NodeID, epoch, stakeResult.RewardAmount); err != nil {
+					// This should never happen
+					return err
+				}
+			case *actions.IncreaseValidatorStake:
+				stakeResult, err := actions.UnmarshalChangeStakeResult(output)
+				if err != nil {
+					// This should never happen
+					return err
+				}
+				c.metrics.validatorStakeAmount.Add(float64(action.Amount))
+				c.metrics.mintedNAI.Add(float64(stakeResult.RewardAmount))
+				c.metrics.rewardAmount.Add(float64(stakeResult.RewardAmount))
+				c.metrics.claimStakingRewards.Inc()
+				c.metrics.increaseValidatorStake.Inc()
+			case *actions.DecreaseValidatorStake:
+				stakeResult, err := actions.UnmarshalChangeStakeResult(output)
+				if err != nil {
+					// This should never happen
+					return err
+				}
+				c.metrics.validatorStakeAmount.Sub(float64(action.Amount))
+				c.metrics.mintedNAI.Add(float64(stakeResult.RewardAmount))
+				c.metrics.rewardAmount.Add(float64(stakeResult.RewardAmount))
+				c.metrics.claimStakingRewards.Inc()
+				c.metrics.decreaseValidatorStake.Inc()
+			case *actions.UpdateValidatorStake:
+				c.metrics.updateValidatorStake.Inc()
 			}
 		}
 	}
 
-	// Distribute fees
+	// The fees are collected by the emission balancer when a transaction
+	// changes it next, split according to the rules of this block
 	if totalFee > 0 {
-		c.emission.DistributeFees(totalFee)
-		emissionAddress, err := codec.AddressBech32(nconsts.HRP, c.emission.EmissionAccount.Address)
+		burnedFee := emission.GetFeeSplit(rules).BurnShare(totalFee)
+		c.metrics.feesDistributed.Add(float64(totalFee - burnedFee))
+		c.metrics.feesBurned.Add(float64(burnedFee))
+	}
+
+	for _, epochReward := range ledger.EpochRewards {
+		if err := c.storeEpochReward(ctx, batch, epochReward); err != nil {
+			return err
+		}
+	}
+	for _, slash := range ledger.Slashes {
+		v, err := slash.Marshal()
 		if err != nil {
-			return err // This should never happen
+			return err
+		}
+		if err := storage.StoreSlashEvent(ctx, batch, slash.NodeID, slash.Epoch, v); err != nil {
+			return err
+		}
+	}
+	for _, settledReward := range settled.rewards {
+		if err := c.storeDelegatorReward(ctx, batch, settledReward.owner, settledReward.reward); err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
+
+// delegatorRewards are the rewards paid out to delegations by the
+// transactions of a block, merged per delegation.
+type delegatorRewards struct {
+	rewards []*delegatorReward
+}
+
+type delegatorReward struct {
+	owner  codec.Address
+	reward *emission.DelegatorEpochReward
+}
+
+// add records [amount] paid out to the delegation of [owner] to [nodeID] in
+// [epoch].
+func (d *delegatorRewards) add(owner codec.Address, nodeIDBytes []byte, epoch uint64, amount uint64) error {
+	if amount == 0 {
+		return nil
+	}
+	nodeID, err := ids.ToNodeID(nodeIDBytes)
+	if err != nil {
+		return err
+	}
+	for _, settledReward := range d.rewards {
+		if settledReward.owner == owner && settledReward.reward.NodeID == nodeID {
+			settledReward.reward.Reward += amount
+			return nil
 		}
-		c.inner.Logger().Info("distributed fees to Emission and Validators", zap.Uint64("current block height", c.inner.LastAcceptedBlock().Height()), zap.Uint64("total fee", totalFee), zap.Uint64("total supply", c.emission.TotalSupply), zap.Uint64("max supply", c.emission.MaxSupply), zap.Uint64("rewards per epock", c.emission.GetRewardsPerEpoch()), zap.String("emission address", emissionAddress), zap.Uint64("emission address accumulated reward", c.emission.EmissionAccount.AccumulatedReward))
-		c.metrics.feesDistributed.Add(float64(totalFee))
 	}
+	d.rewards = append(d.rewards, &delegatorReward{
+		owner: owner,
+		reward: &emission.DelegatorEpochReward{
+			NodeID: nodeID,
+			Epoch:  epoch,
+			Reward: amount,
+		},
+	})
+	return nil
+}
 
-	// Mint new NAI if needed
-	mintNewNAI := c.emission.MintNewNAI()
-	if mintNewNAI > 0 {
-		c.emission.AddToTotalSupply(mintNewNAI)
-		c.inner.Logger().Info("minted new NAI", zap.Uint64("current block height", c.inner.LastAcceptedBlock().Height()), zap.Uint64("newly minted NAI", mintNewNAI), zap.Uint64("total supply", c.emission.TotalSupply), zap.Uint64("max supply", c.emission.MaxSupply))
+// storeDelegatorReward adds [delegatorReward] to the ledger entry of the
+// delegation of [owner] for its epoch.
+func (c *Controller) storeDelegatorReward(ctx context.Context, batch database.Batch, owner codec.Address, delegatorReward *emission.DelegatorEpochReward) error {
+	exists, v, err := storage.GetDelegatorReward(ctx, c.metaDB, owner, delegatorReward.Epoch, delegatorReward.NodeID)
+	if err != nil {
+		return err
 	}
+	if exists {
+		stored, err := emission.UnmarshalDelegatorEpochReward(v)
+		if err != nil {
+			return err
+		}
+		delegatorReward.Reward += stored.Reward
+	}
+	v, err = delegatorReward.Marshal()
+	if err != nil {
+		return err
+	}
+	return storage.StoreDelegatorReward(ctx, batch, owner, delegatorReward.Epoch, delegatorReward.NodeID, v)
+}
 
-	return batch.Write()
+// storeEpochReward adds [epochReward] to the reward ledger entry of its
+// validator for its epoch.
+func (c *Controller) storeEpochReward(ctx context.Context, batch database.Batch, epochReward *emission.EpochReward) error {
+	exists, v, err := storage.GetEpochReward(ctx, c.metaDB, epochReward.NodeID, epochReward.Epoch)
+	if err != nil {
+		return err
+	}
+	if exists {
+		stored, err := emission.UnmarshalEpochReward(v)
+		if err != nil {
+			return err
+		}
+		stored.Add(epochReward)
+		epochReward = stored
+	}
+	v, err = epochReward.Marshal()
+	if err != nil {
+		return err
+	}
+	return storage.StoreEpochReward(ctx, batch, epochReward.NodeID, epochReward.Epoch, v)
 }
 
 func (*Controller) Rejected(context.Context, *chain.StatelessBlock) error {
@@ -12,15 +12,27 @@ import (
 
 type metrics struct {
 	feesDistributed prometheus.Counter
+	feesBurned      prometheus.Counter
+	feesClaimed     prometheus.Counter
 	mintedNAI       prometheus.Counter
 
-	transfer prometheus.Counter
+	transfer      prometheus.Counter
+	multiTransfer prometheus.Counter
 
 	createAsset prometheus.Counter
 	mintAsset   prometheus.Counter
 	burnAsset   prometheus.Counter
 	importAsset prometheus.Counter
 	exportAsset prometheus.Counter
+	updateAsset prometheus.Counter
+
+	createNFTCollection prometheus.Counter
+	mintNFT             prometheus.Counter
+	transferNFT         prometheus.Counter
+	burnNFT             prometheus.Counter
+
+	approveAsset prometheus.Counter
+	transferFrom prometheus.Counter
 
 	validatorStakeAmount   prometheus.Gauge
 	registerValidatorStake prometheus.Counter
@@ -30,6 +42,15 @@ type metrics struct {
 	undelegateUserStake    prometheus.Counter
 	rewardAmount           prometheus.Gauge
 	claimStakingRewards    prometheus.Counter
+	claimEmissionFees      prometheus.Counter
+	validatorHeartbeat     prometheus.Counter
+	releaseUnbondedStake   prometheus.Counter
+	redelegateUserStake    prometheus.Counter
+	increaseDelegation     prometheus.Counter
+	decreaseDelegation     prometheus.Counter
+	increaseValidatorStake prometheus.Counter
+	decreaseValidatorStake prometheus.Counter
+	updateValidatorStake   prometheus.Counter
 }
 
 func newMetrics(gatherer ametrics.MultiGatherer) (*metrics, error) {
@@ -39,6 +60,16 @@ func newMetrics(gatherer ametrics.MultiGatherer) (*metrics, error) {
 			Name:      "feesDistributed",
 			Help:      "number of NAI tokens distributed as fees",
 		}),
+		feesBurned: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "feesBurned",
+			Help:      "number of NAI tokens burned from fees",
+		}),
+		feesClaimed: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "feesClaimed",
+			Help:      "number of NAI tokens claimed from the emission account",
+		}),
 		mintedNAI: prometheus.NewCounter(prometheus.CounterOpts{
 			Namespace: "actions",
 			Name:      "mintedNAI",
@@ -50,6 +81,11 @@ func newMetrics(gatherer ametrics.MultiGatherer) (*metrics, error) {
 			Name:      "transfer",
 			Help:      "number of transfer actions",
 		}),
+		multiTransfer: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "multi_transfer",
+			Help:      "number of multi transfer actions",
+		}),
 
 		createAsset: prometheus.NewCounter(prometheus.CounterOpts{
 			Namespace: "actions",
@@ -76,6 +112,42 @@ func newMetrics(gatherer ametrics.MultiGatherer) (*metrics, error) {
 			Name:      "export_asset",
 			Help:      "number of export asset actions",
 		}),
+		updateAsset: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "update_asset",
+			Help:      "number of update asset actions",
+		}),
+		createNFTCollection: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "create_nft_collection",
+			Help:      "number of create nft collection actions",
+		}),
+		mintNFT: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "mint_nft",
+			Help:      "number of mint nft actions",
+		}),
+		transferNFT: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "transfer_nft",
+			Help:      "number of transfer nft actions",
+		}),
+		burnNFT: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "burn_nft",
+			Help:      "number of burn nft actions",
+		}),
+
+		approveAsset: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "approve_asset",
+			Help:      "number of approve asset actions",
+		}),
+		transferFrom: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "transfer_from",
+			Help:      "number of transfer from actions",
+		}),
 
 		validatorStakeAmount: prometheus.NewGauge(prometheus.GaugeOpts{
 			Namespace: "actions",
@@ -117,20 +189,77 @@ func newMetrics(gatherer ametrics.MultiGatherer) (*metrics, error) {
 			Name:      "claim_staking_rewards",
 			Help:      "number of claim staking rewards actions",
 		}),
+		claimEmissionFees: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "claim_emission_fees",
+			Help:      "number of claim emission fees actions",
+		}),
+		validatorHeartbeat: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "validator_heartbeat",
+			Help:      "number of validator heartbeat actions",
+		}),
+		releaseUnbondedStake: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "release_unbonded_stake",
+			Help:      "number of release unbonded stake actions",
+		}),
+		redelegateUserStake: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "redelegate_user_stake",
+			Help:      "number of redelegate user stake actions",
+		}),
+		increaseDelegation: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "increase_delegation",
+			Help:      "number of increase delegation actions",
+		}),
+		decreaseDelegation: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "decrease_delegation",
+			Help:      "number of decrease delegation actions",
+		}),
+		increaseValidatorStake: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "increase_validator_stake",
+			Help:      "number of increase validator stake actions",
+		}),
+		decreaseValidatorStake: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "decrease_validator_stake",
+			Help:      "number of decrease validator stake actions",
+		}),
+		updateValidatorStake: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "update_validator_stake",
+			Help:      "number of update validator stake actions",
+		}),
 	}
 	r := prometheus.NewRegistry()
 	errs := wrappers.Errs{}
 	errs.Add(
 		r.Register(m.feesDistributed),
+		r.Register(m.feesBurned),
+		r.Register(m.feesClaimed),
 		r.Register(m.mintedNAI),
 
 		r.Register(m.transfer),
+		r.Register(m.multiTransfer),
 
 		r.Register(m.createAsset),
 		r.Register(m.mintAsset),
 		r.Register(m.burnAsset),
 		r.Register(m.importAsset),
 		r.Register(m.exportAsset),
+		r.Register(m.updateAsset),
+
+		r.Register(m.createNFTCollection),
+		r.Register(m.mintNFT),
+		r.Register(m.transferNFT),
+		r.Register(m.burnNFT),
+
+		r.Register(m.approveAsset),
+		r.Register(m.transferFrom),
 
 		r.Register(m.validatorStakeAmount),
 		r.Register(m.registerValidatorStake),
@@ -140,6 +269,15 @@ func newMetrics(gatherer ametrics.MultiGatherer) (*metrics, error) {
 		r.Register(m.undelegateUserStake),
 		r.Register(m.rewardAmount),
 		r.Register(m.claimStakingRewards),
+		r.Register(m.claimEmissionFees),
+		r.Register(m.validatorHeartbeat),
+		r.Register(m.releaseUnbondedStake),
+		r.Register(m.redelegateUserStake),
+		r.Register(m.increaseDelegation),
+		r.Register(m.decreaseDelegation),
+		r.Register(m.increaseValidatorStake),
+		r.Register(m.decreaseValidatorStake),
+		r.Register(m.updateValidatorStake),
 
 		gatherer.Register(consts.Name, r),
 	)
@@ -5,6 +5,7 @@ package controller
 
 import (
 	"context"
+	"sort"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/trace"
@@ -38,7 +39,7 @@ func (c *Controller) GetTransaction(
 func (c *Controller) GetAssetFromState(
 	ctx context.Context,
 	asset ids.ID,
-) (bool, []byte, uint8, []byte, uint64, codec.Address, bool, error) {
+) (bool, []byte, uint8, []byte, uint64, uint64, codec.Address, bool, bool, error) {
 	return storage.GetAssetFromState(ctx, c.inner.ReadState, asset)
 }
 
@@ -58,21 +59,509 @@ func (c *Controller) GetLoanFromState(
 	return storage.GetLoanFromState(ctx, c.inner.ReadState, asset, destination)
 }
 
-func (c *Controller) GetEmissionInfo() (uint64, uint64, uint64, uint64, uint64, emission.EmissionAccount, emission.EpochTracker, error) {
-	return c.emission.GetLastAcceptedBlockHeight(), c.emission.TotalSupply, c.emission.MaxSupply, c.emission.TotalStaked, c.emission.GetRewardsPerEpoch(), c.emission.EmissionAccount, c.emission.EpochTracker, nil
+func (c *Controller) GetNFTCollectionFromState(
+	ctx context.Context,
+	collection ids.ID,
+) (bool, []byte, []byte, uint64, codec.Address, error) {
+	return storage.GetNFTCollectionFromState(ctx, c.inner.ReadState, collection)
+}
+
+func (c *Controller) GetNFTFromState(
+	ctx context.Context,
+	collection ids.ID,
+	id uint64,
+) (bool, codec.Address, []byte, error) {
+	return storage.GetNFTFromState(ctx, c.inner.ReadState, collection, id)
+}
+
+func (c *Controller) GetNFTsOfOwner(
+	owner codec.Address,
+	start *storage.OwnedNFT,
+	limit int,
+) ([]*storage.OwnedNFT, *storage.OwnedNFT, error) {
+	db, err := c.inner.State()
+	if err != nil {
+		return nil, nil, err
+	}
+	return storage.GetNFTsOfOwner(db, owner, start, limit)
+}
+
+// GetAllowanceFromState returns the allowance of [spender] on the [asset]
+// balance of [owner], its expiry block and whether it has expired for the
+// block after the last accepted one.
+func (c *Controller) GetAllowanceFromState(
+	ctx context.Context,
+	owner codec.Address,
+	spender codec.Address,
+	asset ids.ID,
+) (uint64, uint64, bool, error) {
+	amount, expiryBlock, err := storage.GetAllowanceFromState(ctx, c.inner.ReadState, owner, spender, asset)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	expired := expiryBlock != 0 && c.inner.LastAcceptedBlock().Hght+1 >= expiryBlock
+	return amount, expiryBlock, expired, nil
+}
+
+// getEmission returns the header of the emission balancer as of the last
+// accepted block, processed up to it so that the epoch boundaries no
+// transaction processed yet are included. The rules in effect at the block
+// are returned too.
+func (c *Controller) getEmission(ctx context.Context) (*emission.Header, chain.Rules, error) {
+	v, err := storage.GetEmissionFromState(ctx, c.inner.ReadState)
+	if err != nil {
+		return nil, nil, err
+	}
+	header, err := emission.UnmarshalHeader(v)
+	if err != nil {
+		return nil, nil, err
+	}
+	blk := c.inner.LastAcceptedBlock()
+	rules := c.Rules(blk.Tmstmp)
+	epochTracker := emission.GetEpochTracker(rules)
+	supply := uint64(0)
+	if header.ProcessesBoundary(blk.Hght, epochTracker) {
+		_, _, _, _, supply, _, _, _, _, err = storage.GetAssetFromState(ctx, c.inner.ReadState, ids.Empty)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	header.Process(blk.Hght, blk.Tmstmp, supply, epochTracker)
+	return header, rules, nil
+}
+
+// getActiveSet returns the active set registered so far.
+func (c *Controller) getActiveSet(ctx context.Context) (*emission.ActiveSet, error) {
+	exists, v, err := storage.GetActiveSetFromState(ctx, c.inner.ReadState)
+	if err != nil || !exists {
+		return &emission.ActiveSet{}, err
+	}
+	return emission.UnmarshalActiveSet(v)
+}
+
+// getStake returns the stake of the validator with [nodeID], nil if it has no
+// stake registered.
+func (c *Controller) getStake(ctx context.Context, nodeID ids.NodeID) (*emission.Stake, error) {
+	exists, stakeStartBlock, stakeEndBlock, stakedAmount, delegationFeeRate, pendingDelegationFeeRate, feeRateEffectiveBlock, _, _, err := storage.GetRegisterValidatorStakeFromState(ctx, c.inner.ReadState, nodeID)
+	if err != nil || !exists {
+		return nil, err
+	}
+	return &emission.Stake{
+		StakeStartBlock:          stakeStartBlock,
+		StakeEndBlock:            stakeEndBlock,
+		StakedAmount:             stakedAmount,
+		DelegationFeeRate:        delegationFeeRate,
+		PendingDelegationFeeRate: pendingDelegationFeeRate,
+		FeeRateEffectiveBlock:    feeRateEffectiveBlock,
+	}, nil
+}
+
+// syncValidator syncs [record], the record of the validator with [nodeID],
+// with the epoch boundaries of [h] no transaction of the validator processed
+// yet. It returns the stake of the validator along with what these
+// boundaries distributed and slashed.
+func (c *Controller) syncValidator(ctx context.Context, h *emission.Header, rules chain.Rules, nodeID ids.NodeID, record *emission.ValidatorRecord) (*emission.Stake, *emission.Ledger, error) {
+	stake, err := c.getStake(ctx, nodeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	ledger := &emission.Ledger{}
+	record.Sync(nodeID, stake, h, emission.GetStakingConfig(rules), emission.GetEpochTracker(rules), ledger)
+	return stake, ledger, nil
+}
+
+// getValidator returns the record of the validator with [nodeID], synced up
+// to the last accepted block, along with its stake and what was distributed
+// and slashed since a transaction of the validator synced it last.
+func (c *Controller) getValidator(ctx context.Context, h *emission.Header, rules chain.Rules, nodeID ids.NodeID) (*emission.ValidatorRecord, *emission.Stake, *emission.Ledger, error) {
+	exists, v, err := storage.GetValidatorRecordFromState(ctx, c.inner.ReadState, nodeID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if !exists {
+		return nil, nil, nil, emission.ErrValidatorNotFound
+	}
+	record, err := emission.UnmarshalValidatorRecord(v)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	stake, ledger, err := c.syncValidator(ctx, h, rules, nodeID, record)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return record, stake, ledger, nil
+}
+
+// getValidatorRecords returns the records of all validators, as stored in
+// state.
+func (c *Controller) getValidatorRecords() (map[ids.NodeID]*emission.ValidatorRecord, []ids.NodeID, error) {
+	db, err := c.inner.State()
+	if err != nil {
+		return nil, nil, err
+	}
+	records := map[ids.NodeID]*emission.ValidatorRecord{}
+	nodeIDs := []ids.NodeID{}
+	err = storage.IterateValidatorRecords(db, func(nodeID ids.NodeID, v []byte) error {
+		record, err := emission.UnmarshalValidatorRecord(v)
+		if err != nil {
+			return err
+		}
+		records[nodeID] = record
+		nodeIDs = append(nodeIDs, nodeID)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return records, nodeIDs, nil
+}
+
+func (c *Controller) GetEmissionInfo(ctx context.Context) (uint64, uint64, uint64, uint64, uint64, emission.EmissionAccount, emission.EpochTracker, error) {
+	header, rules, err := c.getEmission(ctx)
+	if err != nil {
+		return 0, 0, 0, 0, 0, emission.EmissionAccount{}, emission.EpochTracker{}, err
+	}
+	_, _, _, _, nativeSupply, _, _, _, _, err := storage.GetAssetFromState(ctx, c.inner.ReadState, ids.Empty)
+	if err != nil {
+		return 0, 0, 0, 0, 0, emission.EmissionAccount{}, emission.EpochTracker{}, err
+	}
+	blk := c.inner.LastAcceptedBlock()
+	epochTracker := emission.GetEpochTracker(rules)
+	totalSupply := header.TotalSupply(nativeSupply)
+	return blk.Hght, totalSupply, header.MaxSupply, header.RegisteredStake, header.GetRewardsPerEpoch(blk.Hght, blk.Tmstmp, totalSupply, epochTracker), header.EmissionAccount, epochTracker, nil
 }
 
 func (c *Controller) GetValidators(ctx context.Context, staked bool) ([]*emission.Validator, error) {
-	if staked {
-		return c.emission.GetStakedValidator(ids.EmptyNodeID), nil
-	} else {
-		return c.emission.GetAllValidators(ctx), nil
+	currentValidators, err := c.emission.GetAllValidators(ctx)
+	if err != nil {
+		return nil, err
 	}
+	if !staked {
+		return currentValidators, nil
+	}
+	header, rules, err := c.getEmission(ctx)
+	if err != nil {
+		return nil, err
+	}
+	activeSet, err := c.getActiveSet(ctx)
+	if err != nil {
+		return nil, err
+	}
+	records, nodeIDs, err := c.getValidatorRecords()
+	if err != nil {
+		return nil, err
+	}
+	blk := c.inner.LastAcceptedBlock()
+	validators := make([]*emission.Validator, 0, len(nodeIDs))
+	for _, nodeID := range nodeIDs {
+		record := records[nodeID]
+		stake, _, err := c.syncValidator(ctx, header, rules, nodeID, record)
+		if err != nil {
+			return nil, err
+		}
+		validator := emission.NewValidator(nodeID, stake, record, header, activeSet, blk.Hght, emission.GetEpochTracker(rules))
+		for _, currentValidator := range currentValidators {
+			if currentValidator.NodeID == nodeID {
+				validator.PublicKey = currentValidator.PublicKey
+			}
+		}
+		validators = append(validators, validator)
+	}
+	return validators, nil
 }
 
-func (c *Controller) GetStakedValidatorInfo(nodeID ids.NodeID) (*emission.Validator, error) {
-	validators := c.emission.GetStakedValidator(nodeID)
-	return validators[0], nil
+func (c *Controller) GetStakedValidatorInfo(ctx context.Context, nodeID ids.NodeID) (*emission.Validator, error) {
+	header, rules, err := c.getEmission(ctx)
+	if err != nil {
+		return nil, err
+	}
+	record, stake, _, err := c.getValidator(ctx, header, rules, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	activeSet, err := c.getActiveSet(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return emission.NewValidator(nodeID, stake, record, header, activeSet, c.inner.LastAcceptedBlock().Hght, emission.GetEpochTracker(rules)), nil
+}
+
+func (c *Controller) GetUnbondingFromState(ctx context.Context, owner codec.Address) ([]*storage.UnbondingEntry, error) {
+	return storage.GetUnbondingFromState(ctx, c.inner.ReadState, owner)
+}
+
+func (c *Controller) GetValidatorUnbondingFromState(ctx context.Context, nodeID ids.NodeID) ([]*storage.ValidatorUnbondingEntry, error) {
+	return storage.GetValidatorUnbondingFromState(ctx, c.inner.ReadState, nodeID)
+}
+
+func (c *Controller) GetSlashHistory(ctx context.Context, nodeID ids.NodeID) ([]*emission.SlashEvent, error) {
+	values, err := storage.GetSlashEvents(ctx, c.metaDB, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	slashEvents := make([]*emission.SlashEvent, 0, len(values))
+	for _, v := range values {
+		slashEvent, err := emission.UnmarshalSlashEvent(v)
+		if err != nil {
+			return nil, err
+		}
+		slashEvents = append(slashEvents, slashEvent)
+	}
+	return slashEvents, nil
+}
+
+func (c *Controller) GetRewardHistory(ctx context.Context, nodeID ids.NodeID, fromEpoch, toEpoch uint64) ([]*emission.EpochReward, error) {
+	values, err := storage.GetEpochRewards(ctx, c.metaDB, nodeID, fromEpoch, toEpoch)
+	if err != nil {
+		return nil, err
+	}
+	epochRewards := make([]*emission.EpochReward, 0, len(values))
+	for _, v := range values {
+		epochReward, err := emission.UnmarshalEpochReward(v)
+		if err != nil {
+			return nil, err
+		}
+		epochRewards = append(epochRewards, epochReward)
+	}
+	return epochRewards, nil
+}
+
+// GetDelegatorRewardHistory returns the rewards paid out to the delegations
+// of [owner] from [fromEpoch] to [toEpoch], both included, as recorded in the
+// ledger when they were settled, together with what their current delegations
+// earned in every epoch since they were last settled, sorted by epoch.
+func (c *Controller) GetDelegatorRewardHistory(ctx context.Context, owner codec.Address, fromEpoch, toEpoch uint64) ([]*emission.DelegatorEpochReward, error) {
+	values, err := storage.GetDelegatorRewards(ctx, c.metaDB, owner, fromEpoch, toEpoch)
+	if err != nil {
+		return nil, err
+	}
+	delegatorRewards := make([]*emission.DelegatorEpochReward, 0, len(values))
+	for _, v := range values {
+		delegatorReward, err := emission.UnmarshalDelegatorEpochReward(v)
+		if err != nil {
+			return nil, err
+		}
+		delegatorRewards = append(delegatorRewards, delegatorReward)
+	}
+
+	header, rules, err := c.getEmission(ctx)
+	if err != nil {
+		return nil, err
+	}
+	records, nodeIDs, err := c.getValidatorRecords()
+	if err != nil {
+		return nil, err
+	}
+	epochLength := emission.GetEpochTracker(rules).EpochLength
+	for _, nodeID := range nodeIDs {
+		exists, stakeStartBlock, stakeEndBlock, _, _, _, rewardWeight, _, rewardHeight, err := storage.GetDelegateUserStakeFromState(ctx, c.inner.ReadState, owner, nodeID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+		_, ledger, err := c.syncValidator(ctx, header, rules, nodeID, records[nodeID])
+		if err != nil {
+			return nil, err
+		}
+		// The weight of a delegation only changes when it is settled, so it
+		// earned with its current weight in every epoch since then
+		epochRewards, err := c.getEpochRewards(ctx, header, ledger, nodeID, rewardHeight, epochLength)
+		if err != nil {
+			return nil, err
+		}
+		for _, epochReward := range epochRewards {
+			// Only the epoch boundaries within the stake period earn rewards
+			boundary := epochReward.Epoch * epochLength
+			if epochReward.Epoch < fromEpoch || epochReward.Epoch > toEpoch || boundary < stakeStartBlock || boundary >= stakeEndBlock {
+				continue
+			}
+			reward := epochReward.DelegatorReward(rewardWeight)
+			if reward == 0 {
+				continue
+			}
+			delegatorRewards = append(delegatorRewards, &emission.DelegatorEpochReward{
+				NodeID: nodeID,
+				Epoch:  epochReward.Epoch,
+				Reward: reward,
+			})
+		}
+	}
+	sort.SliceStable(delegatorRewards, func(i, j int) bool {
+		return delegatorRewards[i].Epoch < delegatorRewards[j].Epoch
+	})
+	return delegatorRewards, nil
+}
+
+// getEpochRewards returns the reward ledger entries of [nodeID] for the epoch
+// boundaries after [rewardHeight], including those in [ledger] that no
+// transaction of the validator synced yet.
+func (c *Controller) getEpochRewards(ctx context.Context, h *emission.Header, ledger *emission.Ledger, nodeID ids.NodeID, rewardHeight, epochLength uint64) ([]*emission.EpochReward, error) {
+	epochRewards := []*emission.EpochReward{}
+	fromEpoch := rewardHeight/epochLength + 1
+	if toEpoch := h.Epoch(epochLength); fromEpoch <= toEpoch {
+		var err error
+		epochRewards, err = c.GetRewardHistory(ctx, nodeID, fromEpoch, toEpoch)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, epochReward := range ledger.EpochRewards {
+		if epochReward.NodeID == nodeID && epochReward.Epoch >= fromEpoch {
+			epochRewards = append(epochRewards, epochReward)
+		}
+	}
+	return epochRewards, nil
+}
+
+// GetPendingValidatorRewards returns the rewards a validator would be paid
+// out by claiming them, broken down per epoch, without claiming them.
+func (c *Controller) GetPendingValidatorRewards(ctx context.Context, nodeID ids.NodeID) (*emission.PendingRewards, error) {
+	header, rules, err := c.getEmission(ctx)
+	if err != nil {
+		return nil, err
+	}
+	record, stake, ledger, err := c.getValidator(ctx, header, rules, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	epochRewards, err := c.getEpochRewards(ctx, header, ledger, nodeID, record.RewardHeight, emission.GetEpochTracker(rules).EpochLength)
+	if err != nil {
+		return nil, err
+	}
+	pendingEpochRewards := []*emission.PendingEpochReward{}
+	for _, epochReward := range epochRewards {
+		if epochReward.ValidatorReward > 0 {
+			pendingEpochRewards = append(pendingEpochRewards, &emission.PendingEpochReward{
+				Epoch:  epochReward.Epoch,
+				Reward: epochReward.ValidatorReward,
+			})
+		}
+	}
+	claimableHeight := uint64(0)
+	if stake != nil {
+		claimableHeight = stake.StakeEndBlock
+	}
+	return &emission.PendingRewards{
+		TotalReward:     record.AccumulatedStakedReward,
+		ClaimableHeight: claimableHeight,
+		EpochRewards:    pendingEpochRewards,
+	}, nil
+}
+
+// GetPendingDelegationRewards returns the rewards a delegator would be paid
+// out by claiming them, broken down per epoch, without claiming them.
+func (c *Controller) GetPendingDelegationRewards(ctx context.Context, nodeID ids.NodeID, owner codec.Address) (*emission.PendingRewards, error) {
+	exists, stakeStartBlock, _, _, _, _, rewardWeight, rewardIndex, rewardHeight, err := storage.GetDelegateUserStakeFromState(ctx, c.inner.ReadState, owner, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, emission.ErrDelegatorNotFound
+	}
+	header, rules, err := c.getEmission(ctx)
+	if err != nil {
+		return nil, err
+	}
+	record, _, ledger, err := c.getValidator(ctx, header, rules, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	epochLength := emission.GetEpochTracker(rules).EpochLength
+	totalReward := record.PendingDelegationRewards(rewardWeight, rewardIndex, stakeStartBlock)
+	epochRewards, err := c.getEpochRewards(ctx, header, ledger, nodeID, rewardHeight, epochLength)
+	if err != nil {
+		return nil, err
+	}
+	pendingEpochRewards := []*emission.PendingEpochReward{}
+	for _, epochReward := range epochRewards {
+		// Delegations to a withdrawn stake stopped earning when it was
+		// withdrawn
+		if record.IsWithdrawn(stakeStartBlock) && epochReward.Epoch*epochLength > record.WithdrawnHeight {
+			continue
+		}
+		if reward := epochReward.DelegatorReward(rewardWeight); reward > 0 {
+			pendingEpochRewards = append(pendingEpochRewards, &emission.PendingEpochReward{
+				Epoch:  epochReward.Epoch,
+				Reward: reward,
+			})
+		}
+	}
+	return &emission.PendingRewards{
+		TotalReward:     totalReward,
+		ClaimableHeight: stakeStartBlock,
+		EpochRewards:    pendingEpochRewards,
+	}, nil
+}
+
+// InvariantCheckEnabled returns whether the supply of NAI can be checked
+// against the NAI held in state, which walks all of state.
+func (c *Controller) InvariantCheckEnabled() bool {
+	return c.config.GetEnableInvariantCheck()
+}
+
+// GetSupplyInfo returns the total supply of NAI, the supply of NAI held in
+// state, the rewards and fees not paid out yet and where the NAI held in state
+// is.
+func (c *Controller) GetSupplyInfo(ctx context.Context) (uint64, uint64, uint64, *storage.NAIHoldings, error) {
+	db, err := c.inner.State()
+	if err != nil {
+		return 0, 0, 0, nil, err
+	}
+	_, _, _, _, nativeSupply, _, _, _, _, err := storage.GetAssetFromState(ctx, c.inner.ReadState, ids.Empty)
+	if err != nil {
+		return 0, 0, 0, nil, err
+	}
+	holdings, err := storage.GetNAIHoldings(db)
+	if err != nil {
+		return 0, 0, 0, nil, err
+	}
+	v, err := storage.GetEmissionFromState(ctx, c.inner.ReadState)
+	if err != nil {
+		return 0, 0, 0, nil, err
+	}
+	header, err := emission.UnmarshalHeader(v)
+	if err != nil {
+		return 0, 0, 0, nil, err
+	}
+	records, _, err := c.getValidatorRecords()
+	if err != nil {
+		return 0, 0, 0, nil, err
+	}
+	unclaimedRewards := header.Unpaid()
+	for _, record := range records {
+		unclaimedRewards += record.Unpaid()
+	}
+	return header.TotalSupply(nativeSupply), nativeSupply, unclaimedRewards, holdings, nil
+}
+
+// GetLockupMultiplier returns the reward weight multiplier, in basis points,
+// of a stake from [stakeStartBlock] to [stakeEndBlock] under the staking
+// config in effect at the last accepted block. [delegated] tells whether it
+// is a delegation or the stake of a validator.
+func (c *Controller) GetLockupMultiplier(stakeStartBlock, stakeEndBlock uint64, delegated bool) uint64 {
+	stakingConfig := emission.GetStakingConfig(c.Rules(c.inner.LastAcceptedBlock().Tmstmp))
+	maxDuration := stakingConfig.MaxValidatorStakeDuration
+	if delegated {
+		maxDuration = stakingConfig.MaxDelegatorStakeDuration
+	}
+	multiplier, _ := stakingConfig.LockupMultiplier(stakeEndBlock-stakeStartBlock, maxDuration)
+	return multiplier
+}
+
+// GetDelegationCapacity returns the stake that can still be delegated to
+// [nodeID] under the staking config in effect at the last accepted block.
+func (c *Controller) GetDelegationCapacity(ctx context.Context, nodeID ids.NodeID) (uint64, error) {
+	header, rules, err := c.getEmission(ctx)
+	if err != nil {
+		return 0, err
+	}
+	record, stake, _, err := c.getValidator(ctx, header, rules, nodeID)
+	if err != nil {
+		return 0, err
+	}
+	return record.DelegationCapacity(stake, emission.GetStakingConfig(rules)), nil
 }
 
 func (c *Controller) GetValidatorStakeFromState(ctx context.Context, nodeID ids.NodeID) (
@@ -85,7 +574,11 @@ func (c *Controller) GetValidatorStakeFromState(ctx context.Context, nodeID ids.
 	codec.Address, // OwnerAddress
 	error,
 ) {
-	return storage.GetRegisterValidatorStakeFromState(ctx, c.inner.ReadState, nodeID)
+	exists, stakeStartBlock, stakeEndBlock, stakedAmount, delegationFeeRate, pendingDelegationFeeRate, feeRateEffectiveBlock, rewardAddress, ownerAddress, err := storage.GetRegisterValidatorStakeFromState(ctx, c.inner.ReadState, nodeID)
+	// Report the delegation fee rate in effect now, which may be a pending
+	// increase whose notice period is over
+	delegationFeeRate, _, _ = emission.EffectiveDelegationFeeRate(delegationFeeRate, pendingDelegationFeeRate, feeRateEffectiveBlock, c.inner.LastAcceptedBlock().Height())
+	return exists, stakeStartBlock, stakeEndBlock, stakedAmount, delegationFeeRate, rewardAddress, ownerAddress, err
 }
 
 func (c *Controller) GetDelegatedUserStakeFromState(ctx context.Context, owner codec.Address, nodeID ids.NodeID) (
@@ -97,5 +590,6 @@ func (c *Controller) GetDelegatedUserStakeFromState(ctx context.Context, owner c
 	codec.Address, // OwnerAddress
 	error,
 ) {
-	return storage.GetDelegateUserStakeFromState(ctx, c.inner.ReadState, owner, nodeID)
+	exists, stakeStartBlock, stakeEndBlock, stakedAmount, rewardAddress, ownerAddress, _, _, _, err := storage.GetDelegateUserStakeFromState(ctx, c.inner.ReadState, owner, nodeID)
+	return exists, stakeStartBlock, stakeEndBlock, stakedAmount, rewardAddress, ownerAddress, err
 }
@@ -39,8 +39,11 @@ func (*StateManager) OutgoingWarpKeyPrefix(txID ids.ID) []byte {
 }
 
 func (*StateManager) SponsorStateKeys(addr codec.Address) []string {
+	// Fees are collected in one of several fee shards, so that transactions
+	// paid for by different sponsors rarely conflict
 	return []string{
 		string(storage.BalanceKey(addr, ids.Empty)),
+		string(storage.FeeShardKey(storage.FeeShard(addr))),
 	}
 }
 
@@ -66,7 +69,7 @@ func (*StateManager) Deduct(
 	mu state.Mutable,
 	amount uint64,
 ) error {
-	return storage.SubBalance(ctx, mu, addr, ids.Empty, amount)
+	return storage.ChargeFee(ctx, mu, addr, amount)
 }
 
 func (*StateManager) Refund(
@@ -76,5 +79,5 @@ func (*StateManager) Refund(
 	amount uint64,
 ) error {
 	// Don't create account if it doesn't exist (may have sent all funds).
-	return storage.AddBalance(ctx, mu, addr, ids.Empty, amount, false)
+	return storage.RefundFee(ctx, mu, addr, amount)
 }
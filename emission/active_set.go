@@ -0,0 +1,190 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package emission
+
+import (
+	"sort"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+)
+
+// The validators registered for an epoch boundary are ranked in its active set
+// by their stake, including their delegations, with ties broken by node ID.
+// The stake a validator is ranked by only goes up until the boundary, so that
+// a validator can't be pushed out and let back in within an epoch. Only the
+// top [EpochTracker.MaxActiveValidators] are in the active set: a validator
+// that ranks above the lowest one of a full set takes its place. The header
+// keeps the highest ranked validator that was left out, so that every
+// validator can tell whether it was in the active set at the boundary without
+// reading it.
+
+const (
+	// MaxActiveSetSize is the maximum number of validators in an active set.
+	MaxActiveSetSize = 128
+
+	// MaxActiveSetLen is the maximum length of a serialized active set.
+	MaxActiveSetLen = hconsts.Uint64Len + hconsts.IntLen + MaxActiveSetSize*activeSetEntryLen
+
+	activeSetEntryLen = hconsts.NodeIDLen + 3*hconsts.Uint64Len
+)
+
+// ActiveSetEntry is a validator in an active set.
+type ActiveSetEntry struct {
+	NodeID    ids.NodeID `json:"nodeID"`    // Node ID of the validator
+	RankStake uint64     `json:"rankStake"` // Stake the validator is ranked by: the highest stake, including delegations, it had since it registered
+	Stake     uint64     `json:"stake"`     // Stake of the validator, including delegations
+	Weight    uint64     `json:"weight"`    // Reward weight of the validator, including delegations
+}
+
+// ActiveSet is the active set registered for an epoch boundary.
+type ActiveSet struct {
+	Epoch   uint64            `json:"epoch"`   // Epoch of the boundary
+	Entries []*ActiveSetEntry `json:"entries"` // Validators in the active set, highest ranked first
+}
+
+// ActiveSetLoader returns the active set registered so far. Active sets are
+// only loaded when a registration changes, so that most transactions don't
+// read them.
+type ActiveSetLoader func() (*ActiveSet, error)
+
+// ranksAbove returns whether a validator ranked by [stakeA] ranks above a
+// validator ranked by [stakeB].
+func ranksAbove(stakeA uint64, nodeIDA ids.NodeID, stakeB uint64, nodeIDB ids.NodeID) bool {
+	if stakeA != stakeB {
+		return stakeA > stakeB
+	}
+	return nodeIDA.Compare(nodeIDB) < 0
+}
+
+// activeSet loads the active set registered for the next epoch boundary. An
+// active set left over from a previous epoch is reset.
+func (h *Header) activeSet(load ActiveSetLoader, epochTracker EpochTracker) (*ActiveSet, error) {
+	s, err := load()
+	if err != nil {
+		return nil, err
+	}
+	if epoch := h.Epoch(epochTracker.EpochLength) + 1; s.Epoch != epoch {
+		s.Epoch = epoch
+		s.Entries = nil
+	}
+	return s, nil
+}
+
+// Rank returns the rank of the validator with [nodeID] in the active set,
+// starting at 1, or 0 if it is not in it.
+func (s *ActiveSet) Rank(nodeID ids.NodeID) uint64 {
+	return uint64(s.search(nodeID) + 1)
+}
+
+func (s *ActiveSet) search(nodeID ids.NodeID) int {
+	for i, entry := range s.Entries {
+		if entry.NodeID == nodeID {
+			return i
+		}
+	}
+	return -1
+}
+
+// put adds [entry] to the active set, or ranks the validator again if it is
+// in it already, and keeps the registered stake, weight and threshold of [h]
+// in line. A validator that does not rank above the threshold, or above the
+// lowest validator of a full set, is left out. It returns whether the
+// validator is in the active set.
+func (s *ActiveSet) put(h *Header, entry *ActiveSetEntry, maxActiveValidators uint64) bool {
+	if i := s.search(entry.NodeID); i >= 0 {
+		entry.RankStake = max(entry.RankStake, s.Entries[i].RankStake)
+		s.removeAt(h, i)
+	} else if !h.Threshold.admits(entry.RankStake, entry.NodeID) {
+		return false
+	}
+
+	// The lowest ranked validators are pushed out of a full set, or of a set
+	// that is over the max after it was lowered
+	for len(s.Entries) > 0 && uint64(len(s.Entries)) >= maxActiveValidators {
+		lowest := s.Entries[len(s.Entries)-1]
+		if uint64(len(s.Entries)) == maxActiveValidators && !ranksAbove(entry.RankStake, entry.NodeID, lowest.RankStake, lowest.NodeID) {
+			h.leaveOut(entry.RankStake, entry.NodeID)
+			return false
+		}
+		s.removeAt(h, len(s.Entries)-1)
+		h.leaveOut(lowest.RankStake, lowest.NodeID)
+	}
+
+	i := sort.Search(len(s.Entries), func(i int) bool {
+		return ranksAbove(entry.RankStake, entry.NodeID, s.Entries[i].RankStake, s.Entries[i].NodeID)
+	})
+	s.Entries = append(s.Entries, nil)
+	copy(s.Entries[i+1:], s.Entries[i:])
+	s.Entries[i] = entry
+	h.RegisteredWeight += entry.Weight
+	h.RegisteredStake += entry.Stake
+	return true
+}
+
+// remove takes the validator with [nodeID] out of the active set, if it is in
+// it, and its stake and weight off the registered stake and weight of [h].
+func (s *ActiveSet) remove(h *Header, nodeID ids.NodeID) {
+	if i := s.search(nodeID); i >= 0 {
+		s.removeAt(h, i)
+	}
+}
+
+func (s *ActiveSet) removeAt(h *Header, i int) {
+	entry := s.Entries[i]
+	h.RegisteredWeight -= min(entry.Weight, h.RegisteredWeight)
+	h.RegisteredStake -= min(entry.Stake, h.RegisteredStake)
+	s.Entries = append(s.Entries[:i], s.Entries[i+1:]...)
+}
+
+// leaveOut raises the threshold to a validator ranked by [stake] that was
+// left out of the active set, if it ranks above it.
+func (h *Header) leaveOut(stake uint64, nodeID ids.NodeID) {
+	if h.Threshold.admits(stake, nodeID) {
+		h.Threshold = Threshold{Set: true, Stake: stake, NodeID: nodeID}
+	}
+}
+
+func (s *ActiveSet) Marshal() ([]byte, error) {
+	size := hconsts.Uint64Len + hconsts.IntLen + len(s.Entries)*activeSetEntryLen
+	p := codec.NewWriter(size, MaxActiveSetLen)
+	p.PackUint64(s.Epoch)
+	p.PackInt(len(s.Entries))
+	for _, entry := range s.Entries {
+		p.PackFixedBytes(entry.NodeID.Bytes())
+		p.PackUint64(entry.RankStake)
+		p.PackUint64(entry.Stake)
+		p.PackUint64(entry.Weight)
+	}
+	return p.Bytes(), p.Err()
+}
+
+func UnmarshalActiveSet(b []byte) (*ActiveSet, error) {
+	p := codec.NewReader(b, MaxActiveSetLen)
+	s := &ActiveSet{}
+	s.Epoch = p.UnpackUint64(false)
+	numEntries := p.UnpackInt(false)
+	if numEntries > MaxActiveSetSize {
+		return nil, ErrInvalidRecord
+	}
+	s.Entries = make([]*ActiveSetEntry, 0, numEntries)
+	for i := 0; i < numEntries && p.Err() == nil; i++ {
+		entry := &ActiveSetEntry{}
+		nodeIDBytes := make([]byte, hconsts.NodeIDLen)
+		p.UnpackFixedBytes(hconsts.NodeIDLen, &nodeIDBytes)
+		copy(entry.NodeID[:], nodeIDBytes)
+		entry.RankStake = p.UnpackUint64(false)
+		entry.Stake = p.UnpackUint64(false)
+		entry.Weight = p.UnpackUint64(false)
+		s.Entries = append(s.Entries, entry)
+	}
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	if !p.Empty() {
+		return nil, ErrInvalidRecord
+	}
+	return s, nil
+}
@@ -0,0 +1,141 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package emission
+
+import (
+	"math/big"
+)
+
+// Every validator keeps a reward index: the delegation rewards it distributed
+// so far per unit of delegated weight. A delegation earns its weight times the
+// growth of the index since it was last settled, so delegations never need to
+// be visited when rewards are distributed. The weight of a delegation is added
+// to the delegated weight of its validator when it is delegated, and it is
+// removed when it is undelegated or the validator withdraws its stake, see
+// [ValidatorRecord.Withdraw], so the index only grows by what the
+// delegations of the validator earned at the epoch boundaries in between. A
+// delegation can't start after the next epoch boundary, so it is staked at
+// every boundary it earns at. Once its end block passes, it is no longer locked
+// up but it stays delegated, and keeps earning, until it is undelegated.
+
+// RewardIndexLen is the length of a [RewardIndex].
+const RewardIndexLen = 16
+
+// RewardIndex is a 128-bit unsigned integer, scaled by [rewardIndexScale],
+// that wraps around. Only the difference between two indices of the same
+// validator is meaningful.
+type RewardIndex [RewardIndexLen]byte
+
+var (
+	rewardIndexScale   = new(big.Int).SetUint64(1_000_000_000_000_000_000)
+	rewardIndexModulus = new(big.Int).Lsh(big.NewInt(1), 8*RewardIndexLen)
+)
+
+func newRewardIndex(v *big.Int) RewardIndex {
+	var index RewardIndex
+	v.Mod(v, rewardIndexModulus).FillBytes(index[:])
+	return index
+}
+
+func (i RewardIndex) big() *big.Int {
+	return new(big.Int).SetBytes(i[:])
+}
+
+// add returns the index after [amount] was distributed to [weight].
+func (i RewardIndex) add(amount, weight uint64) RewardIndex {
+	v := new(big.Int).Mul(new(big.Int).SetUint64(amount), rewardIndexScale)
+	v.Quo(v, new(big.Int).SetUint64(weight))
+	return newRewardIndex(v.Add(v, i.big()))
+}
+
+// earned returns what [weight] earned while the index grew from [checkpoint]
+// to [i], rounded down.
+func (i RewardIndex) earned(checkpoint RewardIndex, weight uint64) uint64 {
+	growth := new(big.Int).Sub(i.big(), checkpoint.big())
+	growth.Mod(growth, rewardIndexModulus)
+	earned := growth.Mul(growth, new(big.Int).SetUint64(weight))
+	earned.Quo(earned, rewardIndexScale)
+	if !earned.IsUint64() {
+		return ^uint64(0)
+	}
+	return earned.Uint64()
+}
+
+// Delegate adds a delegation of [amount], with [weight] in reward
+// distribution, to the validator. It returns the reward index and height the
+// delegation starts earning from.
+func (v *ValidatorRecord) Delegate(amount, weight, epochLength uint64) (RewardIndex, uint64) {
+	v.DelegatedAmount += amount
+	v.DelegatedWeight += weight
+	v.Delegations++
+	return v.RewardIndex, v.syncedHeight(epochLength)
+}
+
+// Undelegate removes a delegation of [amount], with [weight] in reward
+// distribution, that started at [stakeStartBlock] from the validator. It must
+// be settled beforehand.
+func (v *ValidatorRecord) Undelegate(amount, weight, stakeStartBlock uint64) {
+	switch {
+	case stakeStartBlock < v.ForfeitedHeight:
+	case stakeStartBlock < v.WithdrawnHeight:
+		v.WithdrawnDelegations -= min(1, v.WithdrawnDelegations)
+	default:
+		v.DelegatedAmount -= min(amount, v.DelegatedAmount)
+		v.DelegatedWeight -= min(weight, v.DelegatedWeight)
+		v.Delegations -= min(1, v.Delegations)
+	}
+}
+
+// IsWithdrawn returns whether a delegation that started at [stakeStartBlock]
+// was made to a stake the validator withdrew.
+func (v *ValidatorRecord) IsWithdrawn(stakeStartBlock uint64) bool {
+	return stakeStartBlock < v.WithdrawnHeight
+}
+
+// ChangeDelegation replaces a delegation of [oldAmount], with [oldWeight] in
+// reward distribution, by one of [newAmount] and [newWeight]. It must be
+// settled beforehand.
+func (v *ValidatorRecord) ChangeDelegation(oldAmount, oldWeight, newAmount, newWeight uint64) {
+	v.DelegatedAmount = v.DelegatedAmount - min(oldAmount, v.DelegatedAmount) + newAmount
+	v.DelegatedWeight = v.DelegatedWeight - min(oldWeight, v.DelegatedWeight) + newWeight
+}
+
+// SettleDelegation pays out the rewards earned by a delegation of [weight]
+// that started at [stakeStartBlock] since it was last settled at
+// [rewardIndex], and returns them along with the reward index and height it
+// earns from next.
+func (v *ValidatorRecord) SettleDelegation(h *Header, weight uint64, rewardIndex RewardIndex, stakeStartBlock, epochLength uint64) (uint64, RewardIndex, uint64) {
+	rewardAmount := v.PendingDelegationRewards(weight, rewardIndex, stakeStartBlock)
+	h.pay(rewardAmount)
+	switch {
+	case stakeStartBlock < v.ForfeitedHeight:
+		return 0, rewardIndex, v.syncedHeight(epochLength)
+	case stakeStartBlock < v.WithdrawnHeight:
+		v.WithdrawnReward -= rewardAmount
+		return rewardAmount, v.WithdrawnIndex, v.syncedHeight(epochLength)
+	default:
+		v.AccumulatedDelegatedReward -= rewardAmount
+		return rewardAmount, v.RewardIndex, v.syncedHeight(epochLength)
+	}
+}
+
+// PendingDelegationRewards returns the rewards [SettleDelegation] would pay
+// out. They are capped by the rewards accumulated by the delegations to the
+// same stake, which only differ because of rounding.
+func (v *ValidatorRecord) PendingDelegationRewards(weight uint64, rewardIndex RewardIndex, stakeStartBlock uint64) uint64 {
+	switch {
+	case stakeStartBlock < v.ForfeitedHeight:
+		return 0
+	case stakeStartBlock < v.WithdrawnHeight:
+		return min(v.WithdrawnIndex.earned(rewardIndex, weight), v.WithdrawnReward)
+	default:
+		return min(v.RewardIndex.earned(rewardIndex, weight), v.AccumulatedDelegatedReward)
+	}
+}
+
+// syncedHeight returns the height of the last epoch boundary the record was
+// synced up to.
+func (v *ValidatorRecord) syncedHeight(epochLength uint64) uint64 {
+	return v.SyncedEpoch * epochLength
+}
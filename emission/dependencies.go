@@ -9,8 +9,6 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/logging"
-	"github.com/ava-labs/avalanchego/x/merkledb"
-	"github.com/ava-labs/hypersdk/chain"
 )
 
 type Controller interface {
@@ -19,6 +17,4 @@ type Controller interface {
 
 type NuklaiVM interface {
 	CurrentValidators(ctx context.Context) (map[ids.NodeID]*validators.GetValidatorOutput, map[string]struct{})
-	LastAcceptedBlock() *chain.StatelessBlock
-	State() (merkledb.MerkleDB, error)
 }
@@ -3,102 +3,810 @@
 
 package emission
 
-/* import (
+import (
+	"sort"
 	"testing"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/hypersdk/codec"
-	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nuklai/nuklaivm/storage"
+)
+
+const blockGap = 3_000 // 3 seconds, in milliseconds
+
+var (
+	node1 = ids.NodeID{1}
+	node2 = ids.NodeID{2}
+	node3 = ids.NodeID{3}
+
+	emissionAddress = codec.CreateAddress(0, ids.ID{1})
 )
 
-// Assume that MockController and MockNuklaiVM are already implemented
-// with the necessary mocked methods.
+func testStakingConfig() StakingConfig {
+	stakingConfig := DefaultStakingConfig()
+	stakingConfig.LockupTiers = nil
+	return stakingConfig
+}
+
+func testEpochTracker() EpochTracker {
+	epochTracker := DefaultEpochTracker()
+	epochTracker.EpochLength = 10
+	return epochTracker
+}
+
+// testBalancer is the emission balancer as kept in state: its header, the
+// active set, and the stake and record of every validator, along with the
+// supply of NAI in state.
+type testBalancer struct {
+	header        *Header
+	activeSet     *ActiveSet
+	stakes        map[ids.NodeID]*Stake
+	records       map[ids.NodeID]*ValidatorRecord
+	supply        uint64
+	unsweptFees   uint64
+	stakingConfig StakingConfig
+	epochTracker  EpochTracker
+}
+
+// newTestBalancer returns a balancer that processed the block at height 1,
+// with a validator staked with [stakes] from block 0 to block 1000 for every
+// node ID in [nodeIDs].
+func newTestBalancer(nodeIDs []ids.NodeID, stakes []uint64, delegationFeeRate uint64, stakingConfig StakingConfig, epochTracker EpochTracker) *testBalancer {
+	b := &testBalancer{
+		header:        NewHeader(10_000_000_000_000, emissionAddress),
+		activeSet:     &ActiveSet{},
+		stakes:        map[ids.NodeID]*Stake{},
+		records:       map[ids.NodeID]*ValidatorRecord{},
+		supply:        1_000_000_000_000,
+		stakingConfig: stakingConfig,
+		epochTracker:  epochTracker,
+	}
+	b.process(1)
+	for i, nodeID := range nodeIDs {
+		b.stakes[nodeID] = &Stake{
+			StakeEndBlock:     1000,
+			StakedAmount:      stakes[i],
+			DelegationFeeRate: delegationFeeRate,
+		}
+		b.records[nodeID] = NewValidatorRecord(b.header, epochTracker)
+	}
+	return b
+}
+
+func (b *testBalancer) loadActiveSet() (*ActiveSet, error) {
+	return b.activeSet, nil
+}
+
+// process brings the header up to [height], sweeping the fees collected so
+// far into it at epoch boundaries. It returns the fees burned.
+func (b *testBalancer) process(height uint64) uint64 {
+	if !b.header.ProcessesBoundary(height, b.epochTracker) {
+		b.header.Process(height, int64(height)*blockGap, 0, b.epochTracker)
+		return 0
+	}
+	b.header.Process(height, int64(height)*blockGap, b.supply, b.epochTracker)
+	b.supply -= b.unsweptFees
+	burned := b.header.CollectFees(b.unsweptFees, DefaultFeeSplit())
+	b.unsweptFees = 0
+	return burned
+}
+
+// sync syncs the validator with [nodeID] and takes the stake slashed from it
+// off its stake and the supply, as its transactions do.
+func (b *testBalancer) sync(nodeID ids.NodeID, ledger *Ledger) {
+	record := b.records[nodeID]
+	record.Sync(nodeID, b.stakes[nodeID], b.header, b.stakingConfig, b.epochTracker, ledger)
+	b.supply -= record.SettleSlash(b.stakes[nodeID], b.header)
+}
+
+// heartbeat syncs the validator with [nodeID] and sends a heartbeat for it in
+// the block at [height].
+func (b *testBalancer) heartbeat(nodeID ids.NodeID, height uint64, ledger *Ledger) error {
+	b.sync(nodeID, ledger)
+	record := b.records[nodeID]
+	record.RecordHeartbeat(height, b.stakingConfig, b.epochTracker)
+	return record.Register(nodeID, b.stakes[nodeID], height, b.header, b.loadActiveSet, b.stakingConfig, b.epochTracker)
+}
+
+// processBlocks processes every block from the one after the last processed
+// height up to [height], every validator sending a heartbeat in every block,
+// and syncs every validator at the end. It returns the ledgers merged.
+func (b *testBalancer) processBlocks(t *testing.T, height uint64) *Ledger {
+	ledger := &Ledger{}
+	for h := b.header.ProcessedHeight + 1; h <= height; h++ {
+		b.process(h)
+		for _, nodeID := range b.nodeIDs() {
+			require.NoError(t, b.heartbeat(nodeID, h, ledger))
+		}
+	}
+	for _, nodeID := range b.nodeIDs() {
+		b.sync(nodeID, ledger)
+	}
+	return ledger
+}
+
+func (b *testBalancer) nodeIDs() []ids.NodeID {
+	nodeIDs := make([]ids.NodeID, 0, len(b.records))
+	for nodeID := range b.records {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	sort.Slice(nodeIDs, func(i, j int) bool {
+		return nodeIDs[i].Compare(nodeIDs[j]) < 0
+	})
+	return nodeIDs
+}
+
+func TestMarshal(t *testing.T) {
+	require := require.New(t)
 
-func TestRegisterAndUnregisterValidator(t *testing.T) {
-	c := &MockController{}
-	vm := &MockNuklaiVM{}
-	emission := New(c, vm, 10000, 20000, codec.Address{})
+	// The header, every record and a full active set must fit in the chunks
+	// allocated to them in state
+	require.LessOrEqual(HeaderLen, int(storage.EmissionChunks)*64)
+	require.LessOrEqual(ValidatorRecordLen, int(storage.ValidatorRecordChunks)*64)
+	require.LessOrEqual(MaxActiveSetLen, int(storage.ActiveSetChunks)*64)
 
-	nodeID := ids.GenerateTestNodeID()
-	publicKey := []byte("publicKey")
-	stakedAmount := uint64(100)
-	delegationFeeRate := uint64(10)
+	stakingConfig := testStakingConfig()
+	stakingConfig.MinUptime = 5000
+	stakingConfig.HeartbeatInterval = 5
+	b := newTestBalancer([]ids.NodeID{node1, node2}, []uint64{100_000_000_000, 50_000_000_000}, 10, stakingConfig, testEpochTracker())
+	b.records[node1].Delegate(25_000_000_000, 25_000_000_000, 10)
+	b.unsweptFees = 1_000
+	b.processBlocks(t, 25)
 
-	// Test new registration
-	err := emission.RegisterValidatorStake(nodeID, publicKey, stakedAmount, delegationFeeRate)
-	assert.NoError(t, err, "Registering new validator should not produce an error")
+	v, err := b.header.Marshal()
+	require.NoError(err)
+	header, err := UnmarshalHeader(v)
+	require.NoError(err)
+	require.Equal(b.header, header)
+	_, err = UnmarshalHeader(append(v, 0))
+	require.ErrorIs(err, ErrInvalidRecord)
+	v[0] = headerVersion + 1
+	_, err = UnmarshalHeader(v)
+	require.ErrorIs(err, ErrInvalidRecord)
 
-	// Ensure validator is marked as active
-	validator, exists := emission.validators[nodeID]
-	assert.True(t, exists, "Validator should exist after registration")
-	assert.True(t, validator.IsActive, "Validator should be marked as active after registration")
+	v, err = b.records[node1].Marshal()
+	require.NoError(err)
+	record, err := UnmarshalValidatorRecord(v)
+	require.NoError(err)
+	require.Equal(b.records[node1], record)
+	_, err = UnmarshalValidatorRecord(append(v, 0))
+	require.ErrorIs(err, ErrInvalidRecord)
+	v[0] = validatorRecordVersion + 1
+	_, err = UnmarshalValidatorRecord(v)
+	require.ErrorIs(err, ErrInvalidRecord)
 
-	// Test unregistration
-	err = emission.UnregisterValidatorStake(nodeID)
-	assert.NoError(t, err, "Unregistering validator should not produce an error")
+	require.Len(b.activeSet.Entries, 2)
+	v, err = b.activeSet.Marshal()
+	require.NoError(err)
+	activeSet, err := UnmarshalActiveSet(v)
+	require.NoError(err)
+	require.Equal(b.activeSet, activeSet)
+	_, err = UnmarshalActiveSet(append(v, 0))
+	require.ErrorIs(err, ErrInvalidRecord)
+}
 
-	// Ensure validator is marked as inactive
-	validator, exists = emission.validators[nodeID]
-	assert.True(t, exists, "Validator should still exist after unregistration")
-	assert.False(t, validator.IsActive, "Validator should be marked as inactive after unregistration")
+func TestSyncLazily(t *testing.T) {
+	tests := []struct {
+		name   string
+		height uint64
+	}{
+		{
+			name:   "within the first epoch",
+			height: 9,
+		},
+		{
+			name:   "across a few epochs",
+			height: 45,
+		},
+		{
+			name:   "across more epochs than the header keeps",
+			height: 10*RewardEpochs + 25,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			stakingConfig := testStakingConfig()
+			epochTracker := testEpochTracker()
 
-	// Test re-registration
-	err = emission.RegisterValidatorStake(nodeID, publicKey, stakedAmount, delegationFeeRate)
-	assert.NoError(t, err, "Re-registering validator should not produce an error")
+			// A validator synced by every block ends up where a validator
+			// synced once per epoch, by its heartbeat, does
+			eager := newTestBalancer([]ids.NodeID{node1, node2}, []uint64{100_000_000_000, 50_000_000_000}, 10, stakingConfig, epochTracker)
+			lazy := newTestBalancer([]ids.NodeID{node1, node2}, []uint64{100_000_000_000, 50_000_000_000}, 10, stakingConfig, epochTracker)
+			eager.records[node2].Delegate(25_000_000_000, 25_000_000_000, epochTracker.EpochLength)
+			lazy.records[node2].Delegate(25_000_000_000, 25_000_000_000, epochTracker.EpochLength)
 
-	// Ensure validator is marked as active again
-	validator, exists = emission.validators[nodeID]
-	assert.True(t, exists, "Validator should exist after re-registration")
-	assert.True(t, validator.IsActive, "Validator should be marked as active after re-registration")
+			eagerLedger := &Ledger{}
+			lazyLedger := &Ledger{}
+			for h := uint64(2); h <= tt.height; h++ {
+				eager.process(h)
+				lazy.process(h)
+				for _, nodeID := range []ids.NodeID{node1, node2} {
+					require.NoError(eager.heartbeat(nodeID, h, eagerLedger))
+					if h%epochTracker.EpochLength == 2 {
+						require.NoError(lazy.heartbeat(nodeID, h, lazyLedger))
+					}
+				}
+			}
+			for _, nodeID := range []ids.NodeID{node1, node2} {
+				eager.sync(nodeID, eagerLedger)
+				lazy.sync(nodeID, lazyLedger)
+			}
+			require.Equal(eager.header, lazy.header)
+			require.Equal(eager.records, lazy.records)
+			require.ElementsMatch(eagerLedger.EpochRewards, lazyLedger.EpochRewards)
+		})
+	}
 }
 
-func TestDelegateAndUndelegateStake(t *testing.T) {
-	c := &MockController{}
-	vm := &MockNuklaiVM{}
-	emission := New(c, vm, 10000, 20000, codec.Address{})
+func TestProcessConservation(t *testing.T) {
+	tests := []struct {
+		name              string
+		stakes            []uint64
+		delegations       []uint64
+		jailed            []bool
+		delegationFeeRate uint64
+	}{
+		{
+			name:   "single validator",
+			stakes: []uint64{100_000_000_000},
+		},
+		{
+			name:              "validators with delegations",
+			stakes:            []uint64{100_000_000_000, 33_333_333_333, 7_777_777_777},
+			delegations:       []uint64{0, 11_111_111_111, 1},
+			delegationFeeRate: 13,
+		},
+		{
+			name:   "jailed validator",
+			stakes: []uint64{100_000_000_000, 33_333_333_333},
+			jailed: []bool{false, true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			epochTracker := testEpochTracker()
+
+			nodeIDs := []ids.NodeID{node1, node2, node3}[:len(tt.stakes)]
+			b := newTestBalancer(nodeIDs, tt.stakes, tt.delegationFeeRate, testStakingConfig(), epochTracker)
+			for i, amount := range tt.delegations {
+				if amount > 0 {
+					b.records[nodeIDs[i]].Delegate(amount, amount, epochTracker.EpochLength)
+				}
+			}
+			for i, jailed := range tt.jailed {
+				if jailed {
+					b.records[nodeIDs[i]].JailedUntil = 1000
+				}
+			}
+			totalSupply := b.header.TotalSupply(b.supply)
+
+			burned := uint64(0)
+			ledger := &Ledger{}
+			for h := uint64(2); h <= 100; h++ {
+				b.unsweptFees += 1_001
+				burned += b.process(h)
+				for _, nodeID := range nodeIDs {
+					require.NoError(b.heartbeat(nodeID, h, ledger))
+				}
+			}
 
-	nodeID := ids.GenerateTestNodeID()
-	publicKey := []byte("publicKey")
-	stakedAmount := uint64(100)
-	delegationFeeRate := uint64(10)
+			minted, distributed := uint64(0), uint64(0)
+			for _, reward := range ledger.EpochRewards {
+				require.Equal(reward.MintedReward+reward.FeeReward, reward.ValidatorReward+reward.DelegationReward)
+				minted += reward.MintedReward
+				distributed += reward.ValidatorReward + reward.DelegationReward
+			}
+			require.Positive(minted)
 
-	// Register a validator
-	err := emission.RegisterValidatorStake(nodeID, publicKey, stakedAmount, delegationFeeRate)
-	assert.NoError(t, err)
+			// Only what was minted and burned changes the total supply, and
+			// what could not be distributed because of rounding is carried
+			// over
+			mintedTotal := uint64(0)
+			for _, d := range b.header.Distributions {
+				mintedTotal += d.Minted
+			}
+			require.LessOrEqual(minted, mintedTotal)
+			require.Equal(totalSupply+mintedTotal-burned, b.header.TotalSupply(b.supply))
+			require.Less(b.header.RewardDust, uint64(len(tt.stakes)))
 
-	delegatorAddr := codec.Address{1} // Example delegator address
-	delegationAmount := uint64(50)
+			// Everything minted or swept is held until it is paid out
+			unpaid := b.header.Unpaid()
+			accumulated := uint64(0)
+			for _, record := range b.records {
+				accumulated += record.Unpaid()
+			}
+			require.Equal(b.header.Held, unpaid+accumulated)
+			require.Equal(distributed, accumulated)
+		})
+	}
+}
 
-	// Delegate stake
-	err = emission.DelegateUserStake(nodeID, delegatorAddr, delegationAmount)
-	assert.NoError(t, err, "Delegating stake should not produce an error")
+func TestDelegationFeeRateNoticePeriod(t *testing.T) {
+	tests := []struct {
+		name                     string
+		height                   uint64
+		delegationFeeRate        uint64
+		pendingDelegationFeeRate uint64
+		feeRateEffectiveBlock    uint64
+	}{
+		{
+			name:                     "increase pending before the notice period is over",
+			height:                   24,
+			delegationFeeRate:        10,
+			pendingDelegationFeeRate: 20,
+			feeRateEffectiveBlock:    25,
+		},
+		{
+			name:              "increase takes effect at the end of the notice period",
+			height:            25,
+			delegationFeeRate: 20,
+		},
+		{
+			name:              "increase in effect after the notice period",
+			height:            40,
+			delegationFeeRate: 20,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			stakingConfig := testStakingConfig()
+			epochTracker := testEpochTracker()
 
-	// Check updated delegated amount
-	validator, _ := emission.validators[nodeID]
-	assert.Equal(t, delegationAmount, validator.DelegatedAmount, "Delegated amount should be updated after delegation")
+			b := newTestBalancer([]ids.NodeID{node1}, []uint64{100_000_000_000}, 10, stakingConfig, epochTracker)
+			b.records[node1].Delegate(50_000_000_000, 50_000_000_000, epochTracker.EpochLength)
+			b.stakes[node1].PendingDelegationFeeRate = 20
+			b.stakes[node1].FeeRateEffectiveBlock = 25
+			ledger := b.processBlocks(t, tt.height)
 
-	// Undelegate stake
-	_, err = emission.UndelegateUserStake(nodeID, delegatorAddr, delegationAmount)
-	assert.NoError(t, err, "Undelegating stake should not produce an error")
+			validator := NewValidator(node1, b.stakes[node1], b.records[node1], b.header, b.activeSet, tt.height, epochTracker)
+			require.Equal(tt.delegationFeeRate, validator.DelegationFeeRate)
+			require.Equal(tt.pendingDelegationFeeRate, validator.PendingDelegationFeeRate)
+			require.Equal(tt.feeRateEffectiveBlock, validator.FeeRateEffectiveBlock)
 
-	// Check updated delegated amount
-	validator, _ = emission.validators[nodeID]
-	assert.Equal(t, uint64(0), validator.DelegatedAmount, "Delegated amount should be zero after undelegation")
+			// Every epoch boundary shares the rewards at the rate in effect
+			// then
+			require.NotEmpty(ledger.EpochRewards)
+			for _, reward := range ledger.EpochRewards {
+				rate := uint64(10)
+				if reward.Epoch*epochTracker.EpochLength >= 25 {
+					rate = 20
+				}
+				require.Equal(mulDiv(reward.MintedReward+reward.FeeReward, rate, 100), reward.DelegationReward)
+			}
+		})
+	}
 }
 
-func TestClaimRewards(t *testing.T) {
-	// This test would depend heavily on the implementation details of your
-	// reward calculation and distribution logic. You'll need to simulate an
-	// environment where rewards are generated (perhaps by calling MintNewNAI
-	// multiple times) and then ensure that validators and delegators can
-	// claim their expected rewards.
+func TestEffectiveDelegationFeeRate(t *testing.T) {
+	tests := []struct {
+		name                     string
+		pendingDelegationFeeRate uint64
+		feeRateEffectiveBlock    uint64
+		blockHeight              uint64
+		expectedRate             uint64
+		expectedPendingRate      uint64
+		expectedEffectiveBlock   uint64
+	}{
+		{
+			name:         "no pending increase",
+			blockHeight:  100,
+			expectedRate: 10,
+		},
+		{
+			name:                     "pending increase",
+			pendingDelegationFeeRate: 20,
+			feeRateEffectiveBlock:    101,
+			blockHeight:              100,
+			expectedRate:             10,
+			expectedPendingRate:      20,
+			expectedEffectiveBlock:   101,
+		},
+		{
+			name:                     "increase in effect",
+			pendingDelegationFeeRate: 20,
+			feeRateEffectiveBlock:    100,
+			blockHeight:              100,
+			expectedRate:             20,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, pendingRate, effectiveBlock := EffectiveDelegationFeeRate(10, tt.pendingDelegationFeeRate, tt.feeRateEffectiveBlock, tt.blockHeight)
+			require.Equal(t, tt.expectedRate, rate)
+			require.Equal(t, tt.expectedPendingRate, pendingRate)
+			require.Equal(t, tt.expectedEffectiveBlock, effectiveBlock)
+		})
+	}
 }
 
-// Additional tests should be written to cover more scenarios such as:
-// - Validators re-registering after all delegators have left
-// - Delegators staking and unstaking across multiple epochs
-// - Edge cases where actions are taken at the boundary of an epoch
+func TestProcessLockupWeights(t *testing.T) {
+	require := require.New(t)
+	stakingConfig := testStakingConfig()
+	stakingConfig.LockupTiers = []LockupTier{
+		{MinDuration: 10, Multiplier: 10_000},
+		{MinDuration: 500, Multiplier: 15_000},
+	}
+	epochTracker := testEpochTracker()
+
+	// Equal stakes, one of them locked up for longer
+	b := newTestBalancer([]ids.NodeID{node1, node2}, []uint64{100_000_000_000, 100_000_000_000}, 0, stakingConfig, epochTracker)
+	b.stakes[node1].StakeEndBlock = 100
+	ledger := b.processBlocks(t, 10)
+
+	// Both shares are rounded down
+	require.Len(ledger.EpochRewards, 2)
+	require.Equal(node1, ledger.EpochRewards[0].NodeID)
+	require.Equal(node2, ledger.EpochRewards[1].NodeID)
+	require.InDelta(float64(ledger.EpochRewards[0].MintedReward)*1.5, float64(ledger.EpochRewards[1].MintedReward), 2)
+}
 
-// Remember to implement cleanup logic in your tests if necessary, especially
-// for global or persistent state that might affect subsequent tests.
-*/
+func TestWaitlistRanking(t *testing.T) {
+	tests := []struct {
+		name                string
+		stakes              []uint64
+		stakeEndBlocks      []uint64
+		maxActiveValidators uint64
+		expectedRanks       []uint64
+		expectedWaitlisted  []bool
+	}{
+		{
+			name:                "all in the active set",
+			stakes:              []uint64{100_000_000_000, 300_000_000_000, 200_000_000_000},
+			maxActiveValidators: 3,
+			expectedRanks:       []uint64{3, 1, 2},
+			expectedWaitlisted:  []bool{false, false, false},
+		},
+		{
+			name:                "smallest stake waitlisted",
+			stakes:              []uint64{100_000_000_000, 300_000_000_000, 200_000_000_000},
+			maxActiveValidators: 2,
+			expectedRanks:       []uint64{0, 1, 2},
+			expectedWaitlisted:  []bool{true, false, false},
+		},
+		{
+			name:                "ties broken by node ID",
+			stakes:              []uint64{200_000_000_000, 200_000_000_000, 200_000_000_000},
+			maxActiveValidators: 1,
+			expectedRanks:       []uint64{1, 0, 0},
+			expectedWaitlisted:  []bool{false, true, true},
+		},
+		{
+			name:                "validators whose stake ends before the boundary not ranked",
+			stakes:              []uint64{100_000_000_000, 300_000_000_000, 200_000_000_000},
+			stakeEndBlocks:      []uint64{1000, 25, 1000},
+			maxActiveValidators: 1,
+			expectedRanks:       []uint64{0, 0, 1},
+			expectedWaitlisted:  []bool{true, false, false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			epochTracker := testEpochTracker()
+			epochTracker.MaxActiveValidators = tt.maxActiveValidators
+
+			nodeIDs := []ids.NodeID{node1, node2, node3}
+			b := newTestBalancer(nodeIDs, tt.stakes, 0, testStakingConfig(), epochTracker)
+			for i, endBlock := range tt.stakeEndBlocks {
+				b.stakes[nodeIDs[i]].StakeEndBlock = endBlock
+			}
+			ledger := b.processBlocks(t, 20)
+
+			for i, nodeID := range nodeIDs {
+				validator := NewValidator(nodeID, b.stakes[nodeID], b.records[nodeID], b.header, b.activeSet, 20, epochTracker)
+				require.Equal(tt.expectedRanks[i], validator.Rank)
+				require.Equal(tt.expectedWaitlisted[i], validator.Waitlisted)
+			}
+
+			// Waitlisted validators earn nothing
+			require.NotEmpty(ledger.EpochRewards)
+			for _, reward := range ledger.EpochRewards {
+				i := reward.NodeID[0] - 1
+				require.False(tt.expectedWaitlisted[i])
+			}
+		})
+	}
+}
+
+func TestUptimeSlashing(t *testing.T) {
+	tests := []struct {
+		name             string
+		heartbeats       []bool // Whether the validator sends heartbeats in every epoch
+		expectedOffences uint64
+		expectedStake    uint64
+	}{
+		{
+			name:          "online in every epoch",
+			heartbeats:    []bool{true, true, true},
+			expectedStake: 1_000_000,
+		},
+		{
+			name:             "jailed below the slashing threshold",
+			heartbeats:       []bool{true, false, true},
+			expectedOffences: 1,
+			expectedStake:    1_000_000,
+		},
+		{
+			name:             "slashed from the second offence",
+			heartbeats:       []bool{false, true, false},
+			expectedOffences: 2,
+			expectedStake:    900_000,
+		},
+		{
+			name:             "slashed for every offence after that",
+			heartbeats:       []bool{false, false, false},
+			expectedOffences: 3,
+			expectedStake:    810_000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			stakingConfig := testStakingConfig()
+			stakingConfig.MinUptime = 5000
+			stakingConfig.HeartbeatInterval = 5
+			stakingConfig.JailDuration = 0
+			stakingConfig.SlashOffences = 2
+			stakingConfig.SlashRate = 10
+			epochTracker := testEpochTracker()
+			b := newTestBalancer([]ids.NodeID{node1}, []uint64{1_000_000}, 10, stakingConfig, epochTracker)
+			supply := b.supply
+
+			ledger := &Ledger{}
+			for epoch, online := range tt.heartbeats {
+				for h := b.header.ProcessedHeight + 1; h < uint64(epoch+1)*10; h++ {
+					b.process(h)
+					if online {
+						require.NoError(b.heartbeat(node1, h, ledger))
+					}
+				}
+			}
+			ledger.Merge(b.processBlocks(t, 31))
+
+			// The slashed stake is taken off the stake and the supply in state
+			// as soon as the validator is synced, and held by the emission
+			// account
+			record := b.records[node1]
+			require.Equal(tt.expectedOffences, record.Offences)
+			require.Equal(tt.expectedStake, b.stakes[node1].StakedAmount)
+			require.Zero(record.SlashedAmount)
+			require.Len(ledger.Slashes, int(tt.expectedOffences))
+			slashedAmount := uint64(0)
+			for _, slash := range ledger.Slashes {
+				require.Equal(node1, slash.NodeID)
+				require.Zero(slash.Uptime)
+				slashedAmount += slash.SlashedAmount
+			}
+			require.Equal(1_000_000-tt.expectedStake, slashedAmount)
+			require.Equal(supply-slashedAmount, b.supply)
+			require.Equal(slashedAmount, b.header.EmissionAccount.AccumulatedReward)
+		})
+	}
+}
+
+func TestSlashUnbonding(t *testing.T) {
+	stakingConfig := testStakingConfig()
+	stakingConfig.SlashOffences = 2
+	stakingConfig.SlashRate = 10
+	epochLength := testEpochTracker().EpochLength
+
+	tests := []struct {
+		name            string
+		offences        uint64
+		unbondedBlock   uint64
+		expectedSlashed uint64
+	}{
+		{
+			name:          "offence below the slashing threshold",
+			offences:      1,
+			unbondedBlock: 25,
+		},
+		{
+			name:          "unbonded before the epoch of the offence",
+			offences:      2,
+			unbondedBlock: 15,
+		},
+		{
+			name:          "unbonded in the first block of the epoch of the offence",
+			offences:      2,
+			unbondedBlock: 20,
+		},
+		{
+			name:            "unbonded during the epoch of the offence",
+			offences:        2,
+			unbondedBlock:   25,
+			expectedSlashed: 100_000,
+		},
+		{
+			name:            "unbonded after the epoch of the offence",
+			offences:        3,
+			unbondedBlock:   35,
+			expectedSlashed: 100_000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			slash := &SlashEvent{
+				NodeID:        node1,
+				BlockHeight:   30,
+				Epoch:         2,
+				Offences:      tt.offences,
+				SlashedAmount: 5_000,
+			}
+			require.Equal(tt.expectedSlashed, slash.SlashUnbonding(1_000_000, tt.unbondedBlock, stakingConfig, epochLength))
+			require.Equal(5_000+tt.expectedSlashed, slash.SlashedAmount)
+		})
+	}
+}
+
+func TestDelegationRewards(t *testing.T) {
+	require := require.New(t)
+	stakingConfig := testStakingConfig()
+	epochTracker := testEpochTracker()
+	epochLength := epochTracker.EpochLength
+
+	b := newTestBalancer([]ids.NodeID{node1}, []uint64{100_000_000_000}, 10, stakingConfig, epochTracker)
+	record := b.records[node1]
+	ledger := &Ledger{}
+	run := func(height uint64) {
+		for h := b.header.ProcessedHeight + 1; h <= height; h++ {
+			// Every epoch collects different fees, so that no two
+			// boundaries distribute the same rewards
+			b.unsweptFees += h * h * 1_000_000
+			b.process(h)
+			require.NoError(b.heartbeat(node1, h, ledger))
+		}
+	}
+
+	// The first delegation earns from the first boundary until it is
+	// undelegated in the 8th epoch, the second one from the boundary after it
+	// is delegated in the 4th epoch
+	weight1, weight2 := uint64(25_000_000_000), uint64(50_000_000_000)
+	index1, _ := record.Delegate(weight1, weight1, epochLength)
+	run(35)
+	index2, _ := record.Delegate(weight2, weight2, epochLength)
+	require.NoError(record.UpdateRegistration(node1, b.stakes[node1], b.header, b.loadActiveSet, stakingConfig, epochTracker))
+	run(75)
+	reward1, _, _ := record.SettleDelegation(b.header, weight1, index1, 1, epochLength)
+	record.Undelegate(weight1, weight1, 1)
+	require.NoError(record.UpdateRegistration(node1, b.stakes[node1], b.header, b.loadActiveSet, stakingConfig, epochTracker))
+	run(105)
+	reward2, _, _ := record.SettleDelegation(b.header, weight2, index2, 35, epochLength)
+
+	// Both delegations earn exactly their share of every boundary they were
+	// delegated at, up to rounding
+	expected1, expected2, distributed := uint64(0), uint64(0), uint64(0)
+	delegationRewards := map[uint64]bool{}
+	for _, reward := range ledger.EpochRewards {
+		if reward.Epoch <= 7 {
+			expected1 += reward.DelegatorReward(weight1)
+		}
+		if reward.Epoch >= 4 {
+			expected2 += reward.DelegatorReward(weight2)
+		}
+		distributed += reward.DelegationReward
+		delegationRewards[reward.DelegationReward] = true
+	}
+	require.Len(ledger.EpochRewards, 10)
+	require.Len(delegationRewards, 10)
+	require.InDelta(expected1, reward1, 7)
+	require.InDelta(expected2, reward2, 7)
+	require.Equal(distributed, reward1+reward2+record.AccumulatedDelegatedReward)
+}
+
+func TestWithdrawnDelegations(t *testing.T) {
+	require := require.New(t)
+	stakingConfig := testStakingConfig()
+	epochTracker := testEpochTracker()
+	epochLength := epochTracker.EpochLength
+
+	b := newTestBalancer([]ids.NodeID{node1}, []uint64{100_000_000_000}, 10, stakingConfig, epochTracker)
+	record := b.records[node1]
+	weight1, weight2 := uint64(25_000_000_000), uint64(50_000_000_000)
+	index1, _ := record.Delegate(weight1, weight1, epochLength)
+	index2, _ := record.Delegate(weight2, weight2, epochLength)
+	b.processBlocks(t, 35)
+
+	// Withdrawing drops the delegations from the validator, and they keep what
+	// they earned so far
+	pending1 := record.PendingDelegationRewards(weight1, index1, 1)
+	pending2 := record.PendingDelegationRewards(weight2, index2, 1)
+	require.Positive(pending1)
+	capacity := record.DelegationCapacity(b.stakes[node1], stakingConfig)
+	_, err := record.Withdraw(node1, 35, b.header, b.loadActiveSet, stakingConfig, epochTracker)
+	require.NoError(err)
+	require.Zero(record.DelegatedAmount)
+	require.Zero(record.DelegatedWeight)
+	require.Zero(record.Delegations)
+	require.False(record.Close(b.header))
+
+	// The validator registers a new stake right away, with its full capacity,
+	// and the delegations to its withdrawn stake don't earn from it
+	b.stakes[node1] = &Stake{
+		StakeStartBlock:   35,
+		StakeEndBlock:     1000,
+		StakedAmount:      100_000_000_000,
+		DelegationFeeRate: 10,
+	}
+	require.Greater(record.DelegationCapacity(b.stakes[node1], stakingConfig), capacity)
+	index3, _ := record.Delegate(weight1, weight1, epochLength)
+	b.processBlocks(t, 75)
+	require.Equal(pending1, record.PendingDelegationRewards(weight1, index1, 1))
+	require.Positive(record.PendingDelegationRewards(weight1, index3, 36))
+
+	reward1, _, _ := record.SettleDelegation(b.header, weight1, index1, 1, epochLength)
+	require.Equal(pending1, reward1)
+	record.Undelegate(weight1, weight1, 1)
+	require.Equal(uint64(1), record.WithdrawnDelegations)
+	require.Equal(weight1, record.DelegatedWeight)
+
+	// Delegations still left when the validator withdraws its next stake
+	// forfeit their rewards to the emission account
+	feesBefore := b.header.EmissionAccount.AccumulatedReward
+	_, err = record.Withdraw(node1, 75, b.header, b.loadActiveSet, stakingConfig, epochTracker)
+	require.NoError(err)
+	require.GreaterOrEqual(b.header.EmissionAccount.AccumulatedReward, feesBefore+pending2)
+	require.Zero(record.PendingDelegationRewards(weight2, index2, 1))
+	reward2, _, _ := record.SettleDelegation(b.header, weight2, index2, 1, epochLength)
+	require.Zero(reward2)
+	record.Undelegate(weight2, weight2, 1)
+	require.Equal(uint64(1), record.WithdrawnDelegations)
+
+	reward3, _, _ := record.SettleDelegation(b.header, weight1, index3, 36, epochLength)
+	require.Positive(reward3)
+	record.Undelegate(weight1, weight1, 36)
+	require.True(record.Close(b.header))
+
+	// Everything that was not paid out went to the emission account
+	require.Equal(b.header.Held, b.header.Unpaid())
+}
+
+func TestDecreaseBeforeEnd(t *testing.T) {
+	require := require.New(t)
+	stakingConfig := testStakingConfig()
+	epochTracker := testEpochTracker()
+	epochLength := epochTracker.EpochLength
+
+	b := newTestBalancer([]ids.NodeID{node1, node2}, []uint64{100_000_000_000, 50_000_000_000}, 10, stakingConfig, epochTracker)
+	record := b.records[node1]
+	record.Delegate(40_000_000_000, 40_000_000_000, epochLength)
+	b.processBlocks(t, 15)
+	require.True(record.IsRegistered(b.header, epochTracker))
+
+	// Decreasing a delegation and the validator stake well before their end
+	// block updates the registration for the next boundary right away
+	record.ChangeDelegation(40_000_000_000, 40_000_000_000, 30_000_000_000, 30_000_000_000)
+	b.stakes[node1].StakedAmount -= 20_000_000_000
+	require.NoError(record.UpdateRegistration(node1, b.stakes[node1], b.header, b.loadActiveSet, stakingConfig, epochTracker))
+
+	require.Equal(uint64(30_000_000_000), record.DelegatedAmount)
+	require.Equal(uint64(30_000_000_000), record.DelegatedWeight)
+	require.Equal(uint64(110_000_000_000), record.RegisteredStake)
+	require.Equal(uint64(110_000_000_000), record.RegisteredWeight)
+	require.Equal(uint64(30_000_000_000), record.RegisteredDelegatedWeight)
+	require.Equal(record.RegisteredStake+b.records[node2].RegisteredStake, b.header.RegisteredStake)
+	require.Equal(record.RegisteredWeight+b.records[node2].RegisteredWeight, b.header.RegisteredWeight)
+	for _, entry := range b.activeSet.Entries {
+		if entry.NodeID == node1 {
+			require.Equal(record.RegisteredStake, entry.Stake)
+			require.Equal(record.RegisteredWeight, entry.Weight)
+		}
+	}
+
+	// The lower stake is what earns at the boundary
+	ledger := b.processBlocks(t, 25)
+	collected := false
+	for _, reward := range ledger.EpochRewards {
+		if reward.NodeID == node1 && reward.Epoch == 2 {
+			require.Equal(uint64(30_000_000_000), reward.DelegatedWeight)
+			collected = true
+		}
+	}
+	require.True(collected)
+}
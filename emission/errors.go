@@ -17,9 +17,16 @@ var (
 	ErrDelegatorAlreadyClaimed    = errors.New("delegator already claimed")
 	ErrInvalidBlockHeight         = errors.New("invalid block height")
 	ErrValidatorNotActive         = errors.New("validator not active")
+	ErrInvalidRecord              = errors.New("invalid emission record")
+
+	ErrInvalidStakingConfig = errors.New("invalid staking config")
+	ErrInvalidEpochTracker  = errors.New("invalid epoch tracker")
+	ErrInvalidFeeSplit      = errors.New("invalid fee split")
 
 	ErrInvalidNodeID      = errors.New("invalid node id")
 	ErrStakeNotFound      = errors.New("stake not found")
 	ErrNotAValidator      = errors.New("not a validator")
 	ErrNotAValidatorOwner = errors.New("not a validator owner")
+
+	ErrValidatorsUnavailable = errors.New("current validators unavailable")
 )
@@ -0,0 +1,260 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package emission
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+)
+
+// The emission balancer is kept in state as a small header, a record per
+// validator and the active set of the current epoch, so that a transaction
+// only reads and writes the validators it changes. Validators register for the
+// rewards of the next epoch boundary with their first heartbeat of the epoch,
+// which ranks them in the active set and adds their weight to the header. At
+// the boundary, the header turns the rewards minted for the epoch and the fees
+// collected during it into a reward per unit of registered weight, which every
+// validator collects the next time one of its transactions syncs its record,
+// see [ValidatorRecord.Sync]. The rewards of every delegation are not kept in
+// the validator record either: validators keep a reward index instead, and
+// delegations keep the index they were last settled at in their own record.
+
+const (
+	// RewardEpochs is the number of epoch boundaries whose rewards the
+	// header keeps for the validators to collect. The rewards a validator did
+	// not collect by the time their slot is reused are carried over to the
+	// next boundary.
+	RewardEpochs = 16
+
+	// HeaderLen is the length of a serialized header.
+	HeaderLen = hconsts.ByteLen + 11*hconsts.Uint64Len + codec.AddressLen + thresholdLen + RewardEpochs*distributionLen
+
+	headerVersion   = byte(0x0)
+	thresholdLen    = hconsts.BoolLen + hconsts.Uint64Len + hconsts.NodeIDLen
+	distributionLen = 4*hconsts.Uint64Len + RewardIndexLen + thresholdLen
+)
+
+// Header is the global state of the emission balancer.
+type Header struct {
+	MaxSupply       uint64          `json:"maxSupply"`       // Max supply of NAI
+	Held            uint64          `json:"held"`            // NAI minted or swept from the fee shards that was not paid out yet, i.e. the part of the total supply that is not in state
+	RewardDust      uint64          `json:"rewardDust"`      // Rewards that could not be distributed, carried over to the next epoch boundary
+	FeePool         uint64          `json:"feePool"`         // Fees to be distributed to the validators at the next epoch boundary
+	EmissionAccount EmissionAccount `json:"emissionAccount"` // Emission Account Info
+
+	EpochStartHeight    uint64 `json:"epochStartHeight"`    // Height of the block the current epoch started at
+	EpochStartTimestamp int64  `json:"epochStartTimestamp"` // Timestamp, in milliseconds, of the block the current epoch started at
+	ProcessedHeight     uint64 `json:"processedHeight"`     // Height the header was processed up to
+	ProcessedTimestamp  int64  `json:"processedTimestamp"`  // Timestamp, in milliseconds, the header was processed up to
+
+	RegisteredWeight uint64    `json:"registeredWeight"` // Reward weight of the active set registered for the next epoch boundary
+	RegisteredStake  uint64    `json:"registeredStake"`  // Stake, including delegations, of the active set registered for the next epoch boundary
+	Threshold        Threshold `json:"threshold"`        // Highest ranked validator left out of that active set, if any
+
+	Distributions [RewardEpochs]Distribution `json:"distributions"` // Rewards of the last epoch boundaries, by epoch modulo RewardEpochs
+}
+
+// Threshold is the highest ranked validator that registered for an epoch
+// boundary but was left out of its active set, or pushed out of it. Only the
+// validators that rank above it are in the active set at the boundary.
+type Threshold struct {
+	Set    bool       `json:"set"`    // Indicates if any validator was left out of the active set
+	Stake  uint64     `json:"stake"`  // Stake the validator was ranked by
+	NodeID ids.NodeID `json:"nodeID"` // Node ID of the validator
+}
+
+// Distribution is what was distributed at an epoch boundary to the
+// validators in its active set.
+type Distribution struct {
+	Epoch     uint64      `json:"epoch"`     // Epoch the rewards and fees were distributed in, i.e. the epoch boundary divided by the epoch length
+	Index     RewardIndex `json:"-"`         // Rewards and fees distributed per unit of registered weight
+	Minted    uint64      `json:"minted"`    // Rewards minted for the epoch
+	Total     uint64      `json:"total"`     // Rewards minted, fees and rewards carried over
+	Remaining uint64      `json:"remaining"` // Part of the total that was not collected yet
+	Threshold Threshold   `json:"threshold"` // Threshold of the active set
+}
+
+// NewHeader returns the header of an emission balancer that did not process
+// any block yet.
+func NewHeader(maxSupply uint64, emissionAddress codec.Address) *Header {
+	if maxSupply == 0 {
+		maxSupply = DefaultStakingConfig().RewardConfig.SupplyCap // Use the staking config's supply cap if maxSupply is not specified
+	}
+	return &Header{
+		MaxSupply: maxSupply,
+		EmissionAccount: EmissionAccount{
+			Address: emissionAddress,
+		},
+	}
+}
+
+// Copy returns a copy of the header.
+func (h *Header) Copy() *Header {
+	c := *h
+	return &c
+}
+
+// TotalSupply returns the total supply of NAI, given [supply], the NAI held
+// in state.
+func (h *Header) TotalSupply(supply uint64) uint64 {
+	return supply + h.Held
+}
+
+// Epoch returns the epoch the header was processed up to, i.e. the epoch
+// whose boundary was distributed last. Validators register for the boundary
+// of the next one.
+func (h *Header) Epoch(epochLength uint64) uint64 {
+	return h.ProcessedHeight / epochLength
+}
+
+// NextBoundary returns the height of the epoch boundary validators register
+// for. Delegations can't start after it.
+func (h *Header) NextBoundary(epochLength uint64) uint64 {
+	return (h.Epoch(epochLength) + 1) * epochLength
+}
+
+// admits returns whether a validator ranked by [stake] ranks above the
+// threshold.
+func (t Threshold) admits(stake uint64, nodeID ids.NodeID) bool {
+	return !t.Set || ranksAbove(stake, nodeID, t.Stake, t.NodeID)
+}
+
+// distribution returns the rewards distributed at the boundary of [epoch], if
+// the header still keeps them.
+func (h *Header) distribution(epoch uint64) (*Distribution, bool) {
+	d := &h.Distributions[epoch%RewardEpochs]
+	return d, epoch > 0 && d.Epoch == epoch
+}
+
+// pay takes [amount], paid out to an account, off the NAI held by the
+// emission balancer.
+func (h *Header) pay(amount uint64) {
+	h.Held -= min(amount, h.Held)
+}
+
+// ClaimEmissionFees pays out the fees accumulated in the emission account and
+// returns them.
+func (h *Header) ClaimEmissionFees() uint64 {
+	feeAmount := h.EmissionAccount.AccumulatedReward
+	h.EmissionAccount.AccumulatedReward = 0
+	h.pay(feeAmount)
+	return feeAmount
+}
+
+// Unpaid returns the NAI held by the header that is owed to an account or
+// still to be distributed: the fee pool, the rewards carried over and not
+// collected yet and the fees of the emission account.
+func (h *Header) Unpaid() uint64 {
+	unpaid := h.FeePool + h.RewardDust + h.EmissionAccount.AccumulatedReward
+	for _, d := range h.Distributions {
+		unpaid += d.Remaining
+	}
+	return unpaid
+}
+
+// CollectSlash takes in [amount] of stake slashed from a validator, which
+// leaves the supply of NAI in state, and credits it to the emission account.
+func (h *Header) CollectSlash(amount uint64) {
+	h.Held += amount
+	h.EmissionAccount.AccumulatedReward += amount
+}
+
+// CollectFees takes in the transaction [fees] swept from a fee shard and
+// splits them according to [feeSplit]: the burned part leaves the total supply
+// and is returned, the part for the validators is distributed at the next
+// epoch boundary, and the rest is credited to the emission account.
+func (h *Header) CollectFees(fees uint64, feeSplit FeeSplit) uint64 {
+	burned := feeSplit.BurnShare(fees)
+
+	// Fees are not newly minted NAI, so unlike rewards they are not capped by
+	// the max supply
+	feesForValidators := min(mulDiv(fees, feeSplit.Validators, 100), fees-burned)
+	h.FeePool += feesForValidators
+	h.EmissionAccount.AccumulatedReward += fees - burned - feesForValidators
+	h.Held += fees - burned
+	return burned
+}
+
+func (t Threshold) marshal(p *codec.Packer) {
+	p.PackBool(t.Set)
+	p.PackUint64(t.Stake)
+	p.PackFixedBytes(t.NodeID.Bytes())
+}
+
+func unmarshalThreshold(p *codec.Packer) Threshold {
+	t := Threshold{}
+	t.Set = p.UnpackBool()
+	t.Stake = p.UnpackUint64(false)
+	nodeIDBytes := make([]byte, hconsts.NodeIDLen)
+	p.UnpackFixedBytes(hconsts.NodeIDLen, &nodeIDBytes)
+	copy(t.NodeID[:], nodeIDBytes)
+	return t
+}
+
+func (h *Header) Marshal() ([]byte, error) {
+	p := codec.NewWriter(HeaderLen, HeaderLen)
+	p.PackByte(headerVersion)
+	p.PackUint64(h.MaxSupply)
+	p.PackUint64(h.Held)
+	p.PackUint64(h.RewardDust)
+	p.PackUint64(h.FeePool)
+	p.PackAddress(h.EmissionAccount.Address)
+	p.PackUint64(h.EmissionAccount.AccumulatedReward)
+	p.PackUint64(h.EpochStartHeight)
+	p.PackInt64(h.EpochStartTimestamp)
+	p.PackUint64(h.ProcessedHeight)
+	p.PackInt64(h.ProcessedTimestamp)
+	p.PackUint64(h.RegisteredWeight)
+	p.PackUint64(h.RegisteredStake)
+	h.Threshold.marshal(p)
+	for _, d := range h.Distributions {
+		p.PackUint64(d.Epoch)
+		p.PackFixedBytes(d.Index[:])
+		p.PackUint64(d.Minted)
+		p.PackUint64(d.Total)
+		p.PackUint64(d.Remaining)
+		d.Threshold.marshal(p)
+	}
+	return p.Bytes(), p.Err()
+}
+
+func UnmarshalHeader(b []byte) (*Header, error) {
+	p := codec.NewReader(b, HeaderLen)
+	if version := p.UnpackByte(); p.Err() == nil && version != headerVersion {
+		return nil, ErrInvalidRecord
+	}
+	h := &Header{}
+	h.MaxSupply = p.UnpackUint64(false)
+	h.Held = p.UnpackUint64(false)
+	h.RewardDust = p.UnpackUint64(false)
+	h.FeePool = p.UnpackUint64(false)
+	p.UnpackAddress(&h.EmissionAccount.Address)
+	h.EmissionAccount.AccumulatedReward = p.UnpackUint64(false)
+	h.EpochStartHeight = p.UnpackUint64(false)
+	h.EpochStartTimestamp = p.UnpackInt64(false)
+	h.ProcessedHeight = p.UnpackUint64(false)
+	h.ProcessedTimestamp = p.UnpackInt64(false)
+	h.RegisteredWeight = p.UnpackUint64(false)
+	h.RegisteredStake = p.UnpackUint64(false)
+	h.Threshold = unmarshalThreshold(p)
+	for i := range h.Distributions {
+		d := &h.Distributions[i]
+		d.Epoch = p.UnpackUint64(false)
+		index := make([]byte, RewardIndexLen)
+		p.UnpackFixedBytes(RewardIndexLen, &index)
+		copy(d.Index[:], index)
+		d.Minted = p.UnpackUint64(false)
+		d.Total = p.UnpackUint64(false)
+		d.Remaining = p.UnpackUint64(false)
+		d.Threshold = unmarshalThreshold(p)
+	}
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	if !p.Empty() {
+		return nil, ErrInvalidRecord
+	}
+	return h, nil
+}
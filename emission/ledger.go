@@ -0,0 +1,186 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package emission
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+)
+
+// The rewards and fees distributed to every validator are recorded per epoch
+// in a ledger that the controller writes to its metadata database when blocks
+// are accepted. Transactions that process epoch boundaries return what they
+// distributed and slashed in their outputs, so that the ledger is built from
+// the blocks themselves. The rewards paid out to every delegation when it is
+// settled are recorded in the same way, from the outputs of the transactions
+// that settled it.
+
+const (
+	epochRewardLen          = hconsts.NodeIDLen + 6*hconsts.Uint64Len
+	delegatorEpochRewardLen = hconsts.NodeIDLen + 2*hconsts.Uint64Len
+)
+
+// EpochReward records what a validator earned in an epoch.
+type EpochReward struct {
+	NodeID           ids.NodeID `json:"nodeID"`           // Node ID of the validator
+	Epoch            uint64     `json:"epoch"`            // Epoch the rewards and fees were distributed in
+	MintedReward     uint64     `json:"mintedReward"`     // Share of the newly minted NAI earned by the validator and its delegators
+	FeeReward        uint64     `json:"feeReward"`        // Share of the transaction fees earned by the validator and its delegators
+	ValidatorReward  uint64     `json:"validatorReward"`  // Part of the minted NAI and fees kept by the validator
+	DelegationReward uint64     `json:"delegationReward"` // Part of the minted NAI and fees shared by the delegators
+	DelegatedWeight  uint64     `json:"delegatedWeight"`  // Total reward weight of the delegations the delegation reward was shared by
+}
+
+// DelegatorEpochReward records what a delegation was paid out in an epoch
+// when it was settled, or what it earned in an epoch since it was last
+// settled.
+type DelegatorEpochReward struct {
+	NodeID  ids.NodeID `json:"nodeID"`  // Node ID of the validator the stake is delegated to
+	Epoch   uint64     `json:"epoch"`   // Epoch the rewards were paid out or distributed in
+	Reward  uint64     `json:"reward"`  // Rewards paid out to or earned by the delegation
+	Settled bool       `json:"settled"` // Indicates if the rewards were paid out, or are still pending
+}
+
+// Ledger is what the validators collected and were slashed at the epoch
+// boundaries they were synced with, see [ValidatorRecord.Sync].
+type Ledger struct {
+	EpochRewards []*EpochReward // Rewards and fees distributed to every validator, merged per epoch
+	Slashes      []*SlashEvent  // Uptime offences, oldest first
+}
+
+// Size returns the length of the marshaled ledger.
+func (l *Ledger) Size() int {
+	return 2*hconsts.IntLen + len(l.EpochRewards)*epochRewardLen + len(l.Slashes)*slashEventLen
+}
+
+func (l *Ledger) Marshal(p *codec.Packer) error {
+	p.PackInt(len(l.EpochRewards))
+	for _, epochReward := range l.EpochRewards {
+		v, err := epochReward.Marshal()
+		if err != nil {
+			return err
+		}
+		p.PackFixedBytes(v)
+	}
+	p.PackInt(len(l.Slashes))
+	for _, slash := range l.Slashes {
+		v, err := slash.Marshal()
+		if err != nil {
+			return err
+		}
+		p.PackFixedBytes(v)
+	}
+	return p.Err()
+}
+
+func UnmarshalLedger(p *codec.Packer) (*Ledger, error) {
+	l := &Ledger{}
+	epochRewards := p.UnpackInt(false)
+	for i := 0; i < epochRewards && p.Err() == nil; i++ {
+		v := make([]byte, epochRewardLen)
+		p.UnpackFixedBytes(epochRewardLen, &v)
+		epochReward, err := UnmarshalEpochReward(v)
+		if err != nil {
+			return nil, err
+		}
+		l.EpochRewards = append(l.EpochRewards, epochReward)
+	}
+	slashes := p.UnpackInt(false)
+	for i := 0; i < slashes && p.Err() == nil; i++ {
+		v := make([]byte, slashEventLen)
+		p.UnpackFixedBytes(slashEventLen, &v)
+		slash, err := UnmarshalSlashEvent(v)
+		if err != nil {
+			return nil, err
+		}
+		l.Slashes = append(l.Slashes, slash)
+	}
+	return l, p.Err()
+}
+
+// Add adds the rewards and fees of [other], distributed later in the same
+// epoch, to [r].
+func (r *EpochReward) Add(other *EpochReward) {
+	r.MintedReward += other.MintedReward
+	r.FeeReward += other.FeeReward
+	r.ValidatorReward += other.ValidatorReward
+	r.DelegationReward += other.DelegationReward
+	r.DelegatedWeight = other.DelegatedWeight
+}
+
+// DelegatorReward returns the part of the delegation reward earned by a
+// delegation of [weight], rounded down.
+func (r *EpochReward) DelegatorReward(weight uint64) uint64 {
+	return mulDiv(r.DelegationReward, weight, r.DelegatedWeight)
+}
+
+func (r *EpochReward) Marshal() ([]byte, error) {
+	p := codec.NewWriter(epochRewardLen, epochRewardLen)
+	p.PackFixedBytes(r.NodeID.Bytes())
+	p.PackUint64(r.Epoch)
+	p.PackUint64(r.MintedReward)
+	p.PackUint64(r.FeeReward)
+	p.PackUint64(r.ValidatorReward)
+	p.PackUint64(r.DelegationReward)
+	p.PackUint64(r.DelegatedWeight)
+	return p.Bytes(), p.Err()
+}
+
+func UnmarshalEpochReward(b []byte) (*EpochReward, error) {
+	p := codec.NewReader(b, epochRewardLen)
+	r := &EpochReward{}
+	nodeIDBytes := make([]byte, hconsts.NodeIDLen)
+	p.UnpackFixedBytes(hconsts.NodeIDLen, &nodeIDBytes)
+	copy(r.NodeID[:], nodeIDBytes)
+	r.Epoch = p.UnpackUint64(false)
+	r.MintedReward = p.UnpackUint64(false)
+	r.FeeReward = p.UnpackUint64(false)
+	r.ValidatorReward = p.UnpackUint64(false)
+	r.DelegationReward = p.UnpackUint64(false)
+	r.DelegatedWeight = p.UnpackUint64(false)
+	return r, p.Err()
+}
+
+func (r *DelegatorEpochReward) Marshal() ([]byte, error) {
+	p := codec.NewWriter(delegatorEpochRewardLen, delegatorEpochRewardLen)
+	p.PackFixedBytes(r.NodeID.Bytes())
+	p.PackUint64(r.Epoch)
+	p.PackUint64(r.Reward)
+	return p.Bytes(), p.Err()
+}
+
+// UnmarshalDelegatorEpochReward unmarshals a reward paid out to a delegation,
+// as recorded in the ledger.
+func UnmarshalDelegatorEpochReward(b []byte) (*DelegatorEpochReward, error) {
+	p := codec.NewReader(b, delegatorEpochRewardLen)
+	r := &DelegatorEpochReward{Settled: true}
+	nodeIDBytes := make([]byte, hconsts.NodeIDLen)
+	p.UnpackFixedBytes(hconsts.NodeIDLen, &nodeIDBytes)
+	copy(r.NodeID[:], nodeIDBytes)
+	r.Epoch = p.UnpackUint64(false)
+	r.Reward = p.UnpackUint64(false)
+	return r, p.Err()
+}
+
+// Merge adds what was distributed and slashed in [other], later in the same
+// block, to [l].
+func (l *Ledger) Merge(other *Ledger) {
+	for _, epochReward := range other.EpochRewards {
+		l.addEpochReward(epochReward)
+	}
+	l.Slashes = append(l.Slashes, other.Slashes...)
+}
+
+// addEpochReward merges [reward] into the entry of the same validator and
+// epoch, if there is one.
+func (l *Ledger) addEpochReward(reward *EpochReward) {
+	for _, epochReward := range l.EpochRewards {
+		if epochReward.NodeID == reward.NodeID && epochReward.Epoch == reward.Epoch {
+			epochReward.Add(reward)
+			return
+		}
+	}
+	l.EpochRewards = append(l.EpochRewards, reward)
+}
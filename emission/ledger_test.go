@@ -0,0 +1,72 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package emission
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLedgerMarshal(t *testing.T) {
+	tests := []struct {
+		name   string
+		ledger *Ledger
+	}{
+		{
+			name:   "empty",
+			ledger: &Ledger{},
+		},
+		{
+			name: "rewards and slashes",
+			ledger: &Ledger{
+				EpochRewards: []*EpochReward{
+					{NodeID: ids.GenerateTestNodeID(), Epoch: 3, MintedReward: 100, FeeReward: 10, ValidatorReward: 60, DelegationReward: 50, DelegatedWeight: 7},
+					{NodeID: ids.GenerateTestNodeID(), Epoch: 3, MintedReward: 40},
+				},
+				Slashes: []*SlashEvent{
+					{NodeID: ids.GenerateTestNodeID(), BlockHeight: 40, Epoch: 3, Uptime: 2_500, Offences: 3, JailedUntil: 140, SlashedAmount: 5},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			p := codec.NewWriter(tt.ledger.Size(), hconsts.MaxInt)
+			require.NoError(tt.ledger.Marshal(p))
+			require.Len(p.Bytes(), tt.ledger.Size())
+
+			ledger, err := UnmarshalLedger(codec.NewReader(p.Bytes(), len(p.Bytes())))
+			require.NoError(err)
+			require.Equal(tt.ledger.EpochRewards, ledger.EpochRewards)
+			require.Equal(tt.ledger.Slashes, ledger.Slashes)
+		})
+	}
+}
+
+func TestLedgerMerge(t *testing.T) {
+	require := require.New(t)
+	nodeID := ids.GenerateTestNodeID()
+
+	ledger := &Ledger{
+		EpochRewards: []*EpochReward{{NodeID: nodeID, Epoch: 1, MintedReward: 10, DelegatedWeight: 1}},
+	}
+	ledger.Merge(&Ledger{
+		EpochRewards: []*EpochReward{
+			{NodeID: nodeID, Epoch: 1, MintedReward: 5, FeeReward: 2, DelegatedWeight: 2},
+			{NodeID: nodeID, Epoch: 2, MintedReward: 3},
+		},
+		Slashes: []*SlashEvent{{NodeID: nodeID, Epoch: 1}},
+	})
+	require.Equal([]*EpochReward{
+		{NodeID: nodeID, Epoch: 1, MintedReward: 15, FeeReward: 2, DelegatedWeight: 2},
+		{NodeID: nodeID, Epoch: 2, MintedReward: 3},
+	}, ledger.EpochRewards)
+	require.Len(ledger.Slashes, 1)
+}
@@ -4,8 +4,10 @@
 package emission
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/ava-labs/hypersdk/chain"
 	hutils "github.com/ava-labs/hypersdk/utils"
 
 	nconsts "github.com/nuklai/nuklaivm/consts"
@@ -24,6 +26,13 @@ type RewardConfig struct {
 	EmissionAddress string `json:"emissionAddress"`
 }
 
+// LockupTier boosts the weight in reward distribution of stakes that are
+// locked up for at least MinDuration blocks.
+type LockupTier struct {
+	MinDuration uint64 `json:"minDuration"` // Minimum lockup, in blocks
+	Multiplier  uint64 `json:"multiplier"`  // Reward weight multiplier, in basis points
+}
+
 type StakingConfig struct {
 	// Minimum stake, in NAI, required to validate the nuklai network
 	MinValidatorStake uint64 `json:"minValidatorStake"`
@@ -32,6 +41,12 @@ type StakingConfig struct {
 	MaxValidatorStake uint64 `json:"maxValidatorStake"`
 	// Minimum stake, in NAI, that can be delegated on the nuklai network
 	MinDelegatorStake uint64 `json:"minDelegatorStake"`
+	// Maximum stake that can be delegated to a validator, as a ratio of the
+	// validator's own stake in basis points. 0 disables the limit.
+	MaxDelegationRatio uint64 `json:"maxDelegationRatio"`
+	// Maximum stake, in NAI, that can be delegated to a single validator.
+	// 0 disables the limit.
+	MaxDelegatedStake uint64 `json:"maxDelegatedStake"`
 	// Minimum delegation fee, in the range [0, 100], that can be charged
 	// for delegation on the nuklai network.
 	MinDelegationFee uint64 `json:"minDelegationFee"`
@@ -41,13 +56,67 @@ type StakingConfig struct {
 	// MaxStakeDuration is the maximum amount of blocks a validator can validate
 	// for in a single period.
 	MaxValidatorStakeDuration uint64 `json:"maxValidatorStakeDuration"`
+	// MinDelegatorStakeDuration is the minimum amount of blocks a stake can be
+	// delegated for. Delegations can't end after the stake of their validator.
+	MinDelegatorStakeDuration uint64 `json:"minDelegatorStakeDuration"`
+	// MaxDelegatorStakeDuration is the maximum amount of blocks a stake can be
+	// delegated for.
+	MaxDelegatorStakeDuration uint64 `json:"maxDelegatorStakeDuration"`
+	// UnbondingPeriod is the number of blocks withdrawn and undelegated stake
+	// stays locked for before it can be released. 0 releases it immediately.
+	UnbondingPeriod uint64 `json:"unbondingPeriod"`
+	// MinUptime is the minimum uptime, in basis points, a validator must
+	// reach in an epoch to earn that epoch's rewards. Validators below it are
+	// jailed. 0 disables uptime tracking.
+	MinUptime uint64 `json:"minUptime"`
+	// HeartbeatInterval is the number of blocks in which a validator is
+	// expected to send at least one heartbeat.
+	HeartbeatInterval uint64 `json:"heartbeatInterval"`
+	// JailDuration is the number of blocks a validator is jailed for. Jailed
+	// validators earn no rewards or fees.
+	JailDuration uint64 `json:"jailDuration"`
+	// SlashOffences is the number of offences from which a validator's stake
+	// gets slashed every time it is jailed. 0 disables slashing.
+	SlashOffences uint64 `json:"slashOffences"`
+	// SlashRate is the percentage, in the range [0, 100], of the staked
+	// amount that is slashed to the emission account for every such offence.
+	SlashRate uint64 `json:"slashRate"`
+	// FeeRateNoticePeriod is the number of blocks after which an increase of
+	// a validator's delegation fee rate takes effect. Decreases take effect
+	// right away.
+	FeeRateNoticePeriod uint64 `json:"feeRateNoticePeriod"`
+	// LockupTiers boost the reward weight of stakes based on how long they
+	// are locked up for, i.e. StakeEndBlock - StakeStartBlock. They must be
+	// sorted by MinDuration. Stakes locked up for less than the first tier,
+	// or for longer than the max stake duration of their type, are rejected.
+	LockupTiers []LockupTier `json:"lockupTiers"`
 	// RewardConfig is the config for the reward function.
 	RewardConfig RewardConfig `json:"rewardConfig"`
 }
 
-func GetStakingConfig() StakingConfig {
-	// TODO: Enable this in production
-	// minValidatorStake, _ := hutils.ParseBalance("1500000", nconsts.Decimals)
+// FeeSplit is how the transaction fees of every block are split, in
+// percentages that add up to 100.
+type FeeSplit struct {
+	// EmissionAccount is the share of the fees credited to the emission
+	// account.
+	EmissionAccount uint64 `json:"emissionAccount"`
+	// Validators is the share of the fees distributed to the staked
+	// validators and their delegators. It is credited to the emission account
+	// instead when it cannot be distributed, e.g. when nobody is staked.
+	Validators uint64 `json:"validators"`
+	// Burn is the share of the fees that is burned, i.e. removed from the
+	// total supply of NAI.
+	Burn uint64 `json:"burn"`
+}
+
+// Keys used to fetch the emission parameters in effect from [chain.Rules]
+const (
+	StakingConfigKey = "stakingConfig"
+	EpochTrackerKey  = "epochTracker"
+	FeeSplitKey      = "feeSplit"
+)
+
+func DefaultStakingConfig() StakingConfig {
 	minValidatorStake, _ := hutils.ParseBalance("100", nconsts.Decimals)
 	maxValidatorStake, _ := hutils.ParseBalance("100000000", nconsts.Decimals) // 100 million NAI
 	minDelegatorStake, _ := hutils.ParseBalance("25", nconsts.Decimals)
@@ -56,10 +125,26 @@ func GetStakingConfig() StakingConfig {
 		MinValidatorStake:         minValidatorStake,
 		MaxValidatorStake:         maxValidatorStake,
 		MinDelegatorStake:         minDelegatorStake,
-		MinDelegationFee:          2,  // 2%
-		MinValidatorStakeDuration: 20, // 20 blocks which is roughly 1 minute with 3 second block time
-		// MinValidatorStakeDuration:  20 * 60 * 24 * 183 // 6 months TODO: Enable this in production
+		MaxDelegationRatio:        0,                  // Delegations are not limited by the validator's own stake by default
+		MaxDelegatedStake:         0,                  // Delegations are not capped by default
+		MinDelegationFee:          2,                  // 2%
+		MinValidatorStakeDuration: 20,                 // 20 blocks which is roughly 1 minute with 3 second block time
 		MaxValidatorStakeDuration: 20 * 60 * 24 * 364, // 1 year,
+		MinDelegatorStakeDuration: 20,                 // 20 blocks which is roughly 1 minute with 3 second block time
+		MaxDelegatorStakeDuration: 20 * 60 * 24 * 364, // 1 year
+		UnbondingPeriod:           20 * 60,            // 1 hour
+		MinUptime:                 5000,               // 50%, i.e. 2 of the 4 heartbeat slots of every epoch
+		HeartbeatInterval:         25,                 // 25 blocks which is roughly 75 seconds with 3 second block time
+		JailDuration:              20 * 60 * 24,       // 1 day
+		SlashOffences:             3,
+		SlashRate:                 5,            // 5%
+		FeeRateNoticePeriod:       20 * 60 * 24, // 1 day
+		LockupTiers: []LockupTier{
+			{MinDuration: 20, Multiplier: 10_000},                 // 1x from 1 minute
+			{MinDuration: 20 * 60 * 24 * 30, Multiplier: 11_000},  // 1.1x from 1 month
+			{MinDuration: 20 * 60 * 24 * 91, Multiplier: 12_500},  // 1.25x from 3 months
+			{MinDuration: 20 * 60 * 24 * 182, Multiplier: 15_000}, // 1.5x from 6 months
+		},
 		RewardConfig: RewardConfig{
 			MintingPeriod:   365 * 24 * time.Hour,
 			SupplyCap:       supplyCap,
@@ -67,3 +152,180 @@ func GetStakingConfig() StakingConfig {
 		},
 	}
 }
+
+// DefaultEpochTracker returns the default epoch tracker parameters. Every
+// validator registers for an epoch boundary with a heartbeat, so an epoch
+// spans enough blocks for the heartbeats of a full active set to fit in them
+// along with other transactions.
+func DefaultEpochTracker() EpochTracker {
+	return EpochTracker{
+		MaxAPR:              2500, // 25% APR
+		MinAPR:              500,  // 5% APR
+		TargetStakingRatio:  5000, // 50% of the total supply
+		DecayRate:           1000, // APR above MinAPR halves every 10% staked above the target
+		EpochLength:         100,  // 5 minutes with 3 second block time
+		MaxActiveValidators: 100,
+	}
+}
+
+func DefaultFeeSplit() FeeSplit {
+	return FeeSplit{
+		EmissionAccount: 50, // 50%
+		Validators:      50, // 50%
+		Burn:            0,  // Fees are not burned by default
+	}
+}
+
+// GetStakingConfig returns the staking config in effect for [r].
+func GetStakingConfig(r chain.Rules) StakingConfig {
+	if v, ok := r.FetchCustom(StakingConfigKey); ok {
+		if stakingConfig, ok := v.(StakingConfig); ok {
+			return stakingConfig
+		}
+	}
+	return DefaultStakingConfig()
+}
+
+// GetEpochTracker returns the epoch tracker parameters in effect for [r].
+func GetEpochTracker(r chain.Rules) EpochTracker {
+	if v, ok := r.FetchCustom(EpochTrackerKey); ok {
+		if epochTracker, ok := v.(EpochTracker); ok {
+			return epochTracker
+		}
+	}
+	return DefaultEpochTracker()
+}
+
+// GetFeeSplit returns the fee split in effect for [r].
+func GetFeeSplit(r chain.Rules) FeeSplit {
+	if v, ok := r.FetchCustom(FeeSplitKey); ok {
+		if feeSplit, ok := v.(FeeSplit); ok {
+			return feeSplit
+		}
+	}
+	return DefaultFeeSplit()
+}
+
+func (s StakingConfig) Verify() error {
+	if s.MinValidatorStake == 0 || s.MinValidatorStake > s.MaxValidatorStake {
+		return fmt.Errorf("%w: validator stake must be in the range [%d, %d]", ErrInvalidStakingConfig, s.MinValidatorStake, s.MaxValidatorStake)
+	}
+	if s.MinDelegatorStake == 0 {
+		return fmt.Errorf("%w: min delegator stake must be over 0", ErrInvalidStakingConfig)
+	}
+	if s.MinDelegationFee > 100 {
+		return fmt.Errorf("%w: min delegation fee must be in the range [0, 100]", ErrInvalidStakingConfig)
+	}
+	if s.MinValidatorStakeDuration == 0 || s.MinValidatorStakeDuration > s.MaxValidatorStakeDuration {
+		return fmt.Errorf("%w: validator stake duration must be in the range [%d, %d]", ErrInvalidStakingConfig, s.MinValidatorStakeDuration, s.MaxValidatorStakeDuration)
+	}
+	if s.MinDelegatorStakeDuration == 0 || s.MinDelegatorStakeDuration > s.MaxDelegatorStakeDuration {
+		return fmt.Errorf("%w: delegator stake duration must be in the range [%d, %d]", ErrInvalidStakingConfig, s.MinDelegatorStakeDuration, s.MaxDelegatorStakeDuration)
+	}
+	if s.MinUptime > basisPoints {
+		return fmt.Errorf("%w: min uptime must be in the range [0, %d]", ErrInvalidStakingConfig, basisPoints)
+	}
+	if s.MinUptime > 0 && s.HeartbeatInterval == 0 {
+		return fmt.Errorf("%w: heartbeat interval must be over 0", ErrInvalidStakingConfig)
+	}
+	if s.SlashRate > 100 {
+		return fmt.Errorf("%w: slash rate must be in the range [0, 100]", ErrInvalidStakingConfig)
+	}
+	for i, tier := range s.LockupTiers {
+		if tier.Multiplier == 0 {
+			return fmt.Errorf("%w: lockup tier multiplier must be over 0", ErrInvalidStakingConfig)
+		}
+		if i > 0 && tier.MinDuration <= s.LockupTiers[i-1].MinDuration {
+			return fmt.Errorf("%w: lockup tiers must be sorted by min duration", ErrInvalidStakingConfig)
+		}
+	}
+	return nil
+}
+
+// LockupMultiplier returns the reward weight multiplier, in basis points, of a
+// stake locked up for [duration] blocks, and whether such a lockup is allowed.
+// [maxDuration] is the max stake duration of the type of the stake, i.e.
+// MaxValidatorStakeDuration or MaxDelegatorStakeDuration. Without lockup
+// tiers every stake is weighted 1x.
+func (s StakingConfig) LockupMultiplier(duration, maxDuration uint64) (uint64, bool) {
+	if duration > maxDuration {
+		return basisPoints, false
+	}
+	if len(s.LockupTiers) == 0 {
+		return basisPoints, true
+	}
+	if duration < s.LockupTiers[0].MinDuration {
+		return basisPoints, false
+	}
+	multiplier := uint64(basisPoints)
+	for _, tier := range s.LockupTiers {
+		if duration < tier.MinDuration {
+			break
+		}
+		multiplier = tier.Multiplier
+	}
+	return multiplier, true
+}
+
+// StakeWeight returns the weight in reward distribution of [amount] staked
+// from [stakeStartBlock] to [stakeEndBlock], for a stake type whose max
+// duration is [maxDuration].
+func (s StakingConfig) StakeWeight(amount, stakeStartBlock, stakeEndBlock, maxDuration uint64) uint64 {
+	multiplier, _ := s.LockupMultiplier(stakeEndBlock-stakeStartBlock, maxDuration)
+	return mulDiv(amount, multiplier, basisPoints)
+}
+
+// DelegationCapacity returns the stake that can still be delegated to a
+// validator with [stakedAmount] of its own stake and [delegatedAmount]
+// delegated to it. The combined stake of a validator can't exceed
+// MaxValidatorStake either, and validators that withdrew their own stake can't
+// take delegations at all.
+func (s StakingConfig) DelegationCapacity(stakedAmount, delegatedAmount uint64) uint64 {
+	if stakedAmount == 0 {
+		return 0
+	}
+	capacity := s.MaxValidatorStake - min(stakedAmount+delegatedAmount, s.MaxValidatorStake)
+	if s.MaxDelegationRatio > 0 {
+		maxDelegated := mulDiv(stakedAmount, s.MaxDelegationRatio, basisPoints)
+		capacity = min(capacity, maxDelegated-min(delegatedAmount, maxDelegated))
+	}
+	if s.MaxDelegatedStake > 0 {
+		capacity = min(capacity, s.MaxDelegatedStake-min(delegatedAmount, s.MaxDelegatedStake))
+	}
+	return capacity
+}
+
+func (t EpochTracker) Verify() error {
+	if t.EpochLength == 0 {
+		return fmt.Errorf("%w: epoch length must be over 0", ErrInvalidEpochTracker)
+	}
+	if t.MinAPR > t.MaxAPR {
+		return fmt.Errorf("%w: min APR must be in the range [0, %d]", ErrInvalidEpochTracker, t.MaxAPR)
+	}
+	if t.TargetStakingRatio == 0 || t.TargetStakingRatio > basisPoints {
+		return fmt.Errorf("%w: target staking ratio must be in the range [1, %d]", ErrInvalidEpochTracker, basisPoints)
+	}
+	if t.DecayRate == 0 {
+		return fmt.Errorf("%w: decay rate must be over 0", ErrInvalidEpochTracker)
+	}
+	if t.MaxActiveValidators == 0 || t.MaxActiveValidators > MaxActiveSetSize {
+		return fmt.Errorf("%w: max active validators must be in the range [1, %d]", ErrInvalidEpochTracker, MaxActiveSetSize)
+	}
+	return nil
+}
+
+func (f FeeSplit) Verify() error {
+	// Every share is bounded first so that the sum can't overflow
+	if f.EmissionAccount > 100 || f.Validators > 100 || f.Burn > 100 {
+		return fmt.Errorf("%w: shares must be in the range [0, 100]", ErrInvalidFeeSplit)
+	}
+	if f.EmissionAccount+f.Validators+f.Burn != 100 {
+		return fmt.Errorf("%w: shares must add up to 100", ErrInvalidFeeSplit)
+	}
+	return nil
+}
+
+// BurnShare returns the share of [fee] that is burned, rounded down.
+func (f FeeSplit) BurnShare(fee uint64) uint64 {
+	return mulDiv(fee, f.Burn, 100)
+}
@@ -0,0 +1,131 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package emission
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeeSplitVerify(t *testing.T) {
+	tests := []struct {
+		name     string
+		feeSplit FeeSplit
+		err      error
+	}{
+		{
+			name:     "default",
+			feeSplit: DefaultFeeSplit(),
+		},
+		{
+			name:     "everything burned",
+			feeSplit: FeeSplit{Burn: 100},
+		},
+		{
+			name:     "shares under 100",
+			feeSplit: FeeSplit{EmissionAccount: 30, Validators: 30, Burn: 30},
+			err:      ErrInvalidFeeSplit,
+		},
+		{
+			name:     "shares over 100",
+			feeSplit: FeeSplit{EmissionAccount: 50, Validators: 50, Burn: 1},
+			err:      ErrInvalidFeeSplit,
+		},
+		{
+			name:     "shares overflowing to 100",
+			feeSplit: FeeSplit{EmissionAccount: math.MaxUint64, Validators: 101},
+			err:      ErrInvalidFeeSplit,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorIs(t, tt.feeSplit.Verify(), tt.err)
+		})
+	}
+}
+
+func TestLockupMultiplier(t *testing.T) {
+	const month = 20 * 60 * 24 * 30
+	tests := []struct {
+		name               string
+		lockupTiers        []LockupTier
+		duration           uint64
+		maxDuration        uint64
+		expectedMultiplier uint64
+		expectedAllowed    bool
+	}{
+		{
+			name:               "no lockup tiers",
+			duration:           1,
+			expectedMultiplier: basisPoints,
+			expectedAllowed:    true,
+		},
+		{
+			name:               "shorter than the first tier",
+			lockupTiers:        DefaultStakingConfig().LockupTiers,
+			duration:           19,
+			expectedMultiplier: basisPoints,
+		},
+		{
+			name:               "first tier",
+			lockupTiers:        DefaultStakingConfig().LockupTiers,
+			duration:           20,
+			expectedMultiplier: 10_000,
+			expectedAllowed:    true,
+		},
+		{
+			name:               "just below a tier",
+			lockupTiers:        DefaultStakingConfig().LockupTiers,
+			duration:           month - 1,
+			expectedMultiplier: 10_000,
+			expectedAllowed:    true,
+		},
+		{
+			name:               "from a tier",
+			lockupTiers:        DefaultStakingConfig().LockupTiers,
+			duration:           month,
+			expectedMultiplier: 11_000,
+			expectedAllowed:    true,
+		},
+		{
+			name:               "last tier",
+			lockupTiers:        DefaultStakingConfig().LockupTiers,
+			duration:           DefaultStakingConfig().MaxValidatorStakeDuration,
+			expectedMultiplier: 15_000,
+			expectedAllowed:    true,
+		},
+		{
+			name:               "longer than the max delegator stake duration",
+			lockupTiers:        DefaultStakingConfig().LockupTiers,
+			duration:           month + 1,
+			maxDuration:        month,
+			expectedMultiplier: basisPoints,
+		},
+		{
+			name:               "longer than the max stake duration",
+			lockupTiers:        DefaultStakingConfig().LockupTiers,
+			duration:           DefaultStakingConfig().MaxValidatorStakeDuration + 1,
+			expectedMultiplier: basisPoints,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			stakingConfig := DefaultStakingConfig()
+			stakingConfig.LockupTiers = tt.lockupTiers
+
+			maxDuration := stakingConfig.MaxValidatorStakeDuration
+			if tt.maxDuration > 0 {
+				maxDuration = tt.maxDuration
+			}
+
+			multiplier, allowed := stakingConfig.LockupMultiplier(tt.duration, maxDuration)
+			require.Equal(tt.expectedMultiplier, multiplier)
+			require.Equal(tt.expectedAllowed, allowed)
+			require.Equal(mulDiv(1_000_000, multiplier, basisPoints), stakingConfig.StakeWeight(1_000_000, 100, 100+tt.duration, maxDuration))
+		})
+	}
+}
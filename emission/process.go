@@ -0,0 +1,155 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package emission
+
+import "math/big"
+
+// ProcessesBoundary returns whether [Header.Process] distributes the rewards
+// of an epoch boundary when it brings the header up to [height]. Only the
+// transactions that do need the supply of NAI and sweep fees into the
+// emission balancer.
+func (h *Header) ProcessesBoundary(height uint64, epochTracker EpochTracker) bool {
+	if h.EpochStartTimestamp == 0 || height <= h.ProcessedHeight {
+		return false
+	}
+	return height/epochTracker.EpochLength > h.Epoch(epochTracker.EpochLength)
+}
+
+// Process brings the header up to [height], the height of the block being
+// executed, produced at [timestamp]. The rewards of the first epoch boundary
+// since the last processed height, along with the collected fees, are
+// distributed to the active set registered for it. Nobody is registered for
+// the boundaries after it, so they only move the start of the epoch along.
+// Boundaries that no block was executed at are dated by interpolating between
+// the timestamps of the blocks around them. [supply] is the NAI held in state,
+// it is only used when [ProcessesBoundary] is true.
+func (h *Header) Process(height uint64, timestamp int64, supply uint64, epochTracker EpochTracker) {
+	// The first epoch starts at the first block the header is processed at
+	if h.EpochStartTimestamp == 0 {
+		h.EpochStartHeight = height
+		h.EpochStartTimestamp = timestamp
+		h.ProcessedHeight = height
+		h.ProcessedTimestamp = timestamp
+		return
+	}
+	if height <= h.ProcessedHeight {
+		return
+	}
+
+	epochLength := epochTracker.EpochLength
+	lastHeight, lastTimestamp := h.ProcessedHeight, h.ProcessedTimestamp
+	boundaryTimestamp := func(boundary uint64) int64 {
+		if boundary == height {
+			return timestamp
+		}
+		elapsed := uint64(max(timestamp-lastTimestamp, 0))
+		return lastTimestamp + int64(mulDiv(elapsed, boundary-lastHeight, height-lastHeight))
+	}
+	if boundary := (lastHeight/epochLength + 1) * epochLength; boundary <= height {
+		h.processBoundary(boundary, boundaryTimestamp(boundary), supply, epochTracker)
+		if last := height / epochLength * epochLength; last > boundary {
+			h.EpochStartHeight = last
+			h.EpochStartTimestamp = boundaryTimestamp(last)
+		}
+	}
+	h.ProcessedHeight = height
+	h.ProcessedTimestamp = timestamp
+}
+
+// processBoundary distributes the rewards of the epoch boundary at
+// [blockHeight] and resets the registrations for the next one.
+func (h *Header) processBoundary(blockHeight uint64, timestamp int64, supply uint64, epochTracker EpochTracker) {
+	// The rewards are based on the time that actually passed since the epoch
+	// started
+	elapsed := uint64(max(timestamp-h.EpochStartTimestamp, 0))
+	h.EpochStartHeight = blockHeight
+	h.EpochStartTimestamp = timestamp
+
+	// The rewards of the boundary that used the same slot were collected by
+	// now, or are carried over
+	epoch := blockHeight / epochTracker.EpochLength
+	d := &h.Distributions[epoch%RewardEpochs]
+	h.RewardDust += d.Remaining
+	*d = Distribution{}
+
+	weight, threshold := h.RegisteredWeight, h.Threshold
+	mintedRewards := h.getRewards(h.TotalSupply(supply), elapsed, epochTracker)
+	h.RegisteredWeight, h.RegisteredStake, h.Threshold = 0, 0, Threshold{}
+
+	// Fees that can't be distributed because nobody registered go to the
+	// emission account, and the rewards are not minted at all
+	if weight == 0 {
+		h.EmissionAccount.AccumulatedReward += h.FeePool
+		h.FeePool = 0
+		return
+	}
+	h.Held += mintedRewards
+	total := mintedRewards + h.FeePool + h.RewardDust
+	h.FeePool, h.RewardDust = 0, 0
+	*d = Distribution{
+		Epoch:     epoch,
+		Index:     RewardIndex{}.add(total, weight),
+		Minted:    mintedRewards,
+		Total:     total,
+		Remaining: total,
+		Threshold: threshold,
+	}
+}
+
+// GetAPRForValidators calculates the Annual Percentage Rate (APR) for validators
+// based on the share of [totalSupply] that is staked in the active set. The
+// APR is expressed in basis points, e.g., 2500 for 25%.
+//
+// Up to the target staking ratio the APR is MaxAPR. Above it, the part of the
+// APR over MinAPR halves every DecayRate basis points of staking ratio. The
+// result is then scaled by the share of the max supply that is left to mint,
+// so that the total supply approaches the max supply asymptotically.
+func (h *Header) GetAPRForValidators(totalSupply uint64, epochTracker EpochTracker) uint64 {
+	if totalSupply == 0 || totalSupply >= h.MaxSupply {
+		return 0
+	}
+
+	apr := epochTracker.MaxAPR
+	stakingRatio := min(mulDiv(h.RegisteredStake, basisPoints, totalSupply), basisPoints)
+	if stakingRatio > epochTracker.TargetStakingRatio && epochTracker.MaxAPR > epochTracker.MinAPR {
+		// 2^(-excess/decayRate), in basis points, interpolated linearly between
+		// two halvings
+		excess := stakingRatio - epochTracker.TargetStakingRatio
+		halvings := excess / epochTracker.DecayRate
+		decay := uint64(0)
+		if halvings < 64 {
+			decay = uint64(basisPoints) >> halvings
+			decay -= mulDiv(decay, excess%epochTracker.DecayRate, 2*epochTracker.DecayRate)
+		}
+		apr = epochTracker.MinAPR + mulDiv(epochTracker.MaxAPR-epochTracker.MinAPR, decay, basisPoints)
+	}
+	return mulDiv(apr, h.MaxSupply-totalSupply, h.MaxSupply)
+}
+
+// GetRewardsPerEpoch estimates the rewards minted for the current epoch based
+// on the stake of the active set registered so far, the APR for validators
+// and the block time observed since the epoch started, up to the block at
+// [blockHeight] and [timestamp].
+func (h *Header) GetRewardsPerEpoch(blockHeight uint64, timestamp int64, totalSupply uint64, epochTracker EpochTracker) uint64 {
+	if h.EpochStartTimestamp == 0 || blockHeight <= h.EpochStartHeight {
+		return 0
+	}
+	elapsed := uint64(max(timestamp-h.EpochStartTimestamp, 0))
+	return h.getRewards(totalSupply, mulDiv(elapsed, epochTracker.EpochLength, blockHeight-h.EpochStartHeight), epochTracker)
+}
+
+// getRewards calculates the rewards earned by the stake of the active set at
+// the APR for validators over [elapsed] milliseconds.
+func (h *Header) getRewards(totalSupply uint64, elapsed uint64, epochTracker EpochTracker) uint64 {
+	// Rounded down: totalStaked * apr * elapsed / (basisPoints * millisecondsPerYear)
+	rewards := new(big.Int).SetUint64(h.RegisteredStake)
+	rewards.Mul(rewards, new(big.Int).SetUint64(h.GetAPRForValidators(totalSupply, epochTracker)))
+	rewards.Mul(rewards, new(big.Int).SetUint64(elapsed))
+	rewards.Quo(rewards, new(big.Int).SetUint64(basisPoints*secondsPerYear*1000))
+	mintable := h.MaxSupply - min(totalSupply, h.MaxSupply)
+	if !rewards.IsUint64() {
+		return mintable
+	}
+	return min(rewards.Uint64(), mintable) // Adjust to not exceed max supply
+}
@@ -0,0 +1,141 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package emission
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+)
+
+// Blocks don't record which validator proposed or voted for them, so the only
+// participation of a validator that accepted blocks carry is its heartbeats:
+// transactions signed with its node key. Every epoch is split into slots of
+// [StakingConfig.HeartbeatInterval] blocks, and the uptime of a validator is
+// the share of the slots in which an accepted block included one of its
+// heartbeats. A heartbeat counts in the slot of the block that includes it,
+// read from the height kept in state, and transactions expire after the
+// validity window, so heartbeats can't be signed ahead of time for later
+// slots.
+
+const slashEventLen = hconsts.NodeIDLen + 6*hconsts.Uint64Len
+
+// SlashEvent records a validator that was below the minimum uptime in an epoch.
+type SlashEvent struct {
+	NodeID        ids.NodeID `json:"nodeID"`        // Node ID of the validator
+	BlockHeight   uint64     `json:"blockHeight"`   // Block height the offence was recorded at
+	Epoch         uint64     `json:"epoch"`         // Epoch the validator was below the minimum uptime in
+	Uptime        uint64     `json:"uptime"`        // Uptime of the validator in the epoch, in basis points
+	Offences      uint64     `json:"offences"`      // Number of offences of the validator so far
+	JailedUntil   uint64     `json:"jailedUntil"`   // Block height until which the validator is jailed
+	SlashedAmount uint64     `json:"slashedAmount"` // Amount of stake slashed to the emission account
+}
+
+// RecordHeartbeat marks the validator as online in the heartbeat slot of
+// [blockHeight].
+func (v *ValidatorRecord) RecordHeartbeat(blockHeight uint64, stakingConfig StakingConfig, epochTracker EpochTracker) {
+	slotsPerEpoch := slotsPerEpoch(stakingConfig, epochTracker)
+	if slotsPerEpoch == 0 {
+		return
+	}
+
+	epoch := blockHeight / epochTracker.EpochLength
+	slot := epoch*slotsPerEpoch + (blockHeight%epochTracker.EpochLength)/stakingConfig.HeartbeatInterval
+	if v.LastHeartbeatSlot == slot+1 {
+		return
+	}
+	v.LastHeartbeatSlot = slot + 1
+	if v.HeartbeatEpoch != epoch {
+		v.HeartbeatEpoch = epoch
+		v.HeartbeatSlots = 0
+	}
+	v.HeartbeatSlots++
+}
+
+// slotsPerEpoch returns the number of heartbeat slots in an epoch, or 0 if
+// uptime is not tracked.
+func slotsPerEpoch(stakingConfig StakingConfig, epochTracker EpochTracker) uint64 {
+	if stakingConfig.MinUptime == 0 || stakingConfig.HeartbeatInterval == 0 {
+		return 0
+	}
+	return (epochTracker.EpochLength + stakingConfig.HeartbeatInterval - 1) / stakingConfig.HeartbeatInterval
+}
+
+// uptime returns the uptime of the validator, in basis points, in the epoch
+// that ended at the boundary of [epoch], and whether uptime is tracked at all.
+func (v *ValidatorRecord) uptime(epoch uint64, stakingConfig StakingConfig, epochTracker EpochTracker) (uint64, bool) {
+	slotsPerEpoch := slotsPerEpoch(stakingConfig, epochTracker)
+	if slotsPerEpoch == 0 {
+		return basisPoints, false
+	}
+	filledSlots := uint64(0)
+	if v.HeartbeatEpoch+1 == epoch {
+		filledSlots = v.HeartbeatSlots
+	}
+	return mulDiv(min(filledSlots, slotsPerEpoch), basisPoints, slotsPerEpoch), true
+}
+
+// commitOffence jails the validator for being below the minimum uptime in
+// the epoch that ended at the boundary of [epoch], and slashes its stake if it
+// is a repeat offender. The slashed stake is taken off its stake by
+// [ValidatorRecord.SettleSlash].
+func (v *ValidatorRecord) commitOffence(nodeID ids.NodeID, epoch, uptime uint64, stake *Stake, stakingConfig StakingConfig, epochLength uint64, ledger *Ledger) {
+	blockHeight := epoch * epochLength
+	v.Offences++
+	v.JailedUntil = blockHeight + stakingConfig.JailDuration
+	slashedAmount := uint64(0)
+	if stakingConfig.SlashOffences > 0 && v.Offences >= stakingConfig.SlashOffences {
+		slashedAmount = mulDiv(stake.StakedAmount-min(v.SlashedAmount, stake.StakedAmount), stakingConfig.SlashRate, 100)
+		v.SlashedAmount += slashedAmount
+	}
+	ledger.Slashes = append(ledger.Slashes, &SlashEvent{
+		NodeID:        nodeID,
+		BlockHeight:   blockHeight,
+		Epoch:         epoch - 1,
+		Uptime:        uptime,
+		Offences:      v.Offences,
+		JailedUntil:   v.JailedUntil,
+		SlashedAmount: slashedAmount,
+	})
+}
+
+// SlashUnbonding slashes [amount] of stake that was taken off the validator in
+// the block at [unbondedBlock] and is still unbonding, if the offence slashes
+// and the stake was staked during the epoch of the offence. It returns the
+// stake slashed, which is added to the amount slashed by the offence.
+func (s *SlashEvent) SlashUnbonding(amount, unbondedBlock uint64, stakingConfig StakingConfig, epochLength uint64) uint64 {
+	if stakingConfig.SlashOffences == 0 || s.Offences < stakingConfig.SlashOffences || unbondedBlock <= s.Epoch*epochLength {
+		return 0
+	}
+	slashedAmount := mulDiv(amount, stakingConfig.SlashRate, 100)
+	s.SlashedAmount += slashedAmount
+	return slashedAmount
+}
+
+func (s *SlashEvent) Marshal() ([]byte, error) {
+	p := codec.NewWriter(slashEventLen, slashEventLen)
+	p.PackFixedBytes(s.NodeID.Bytes())
+	p.PackUint64(s.BlockHeight)
+	p.PackUint64(s.Epoch)
+	p.PackUint64(s.Uptime)
+	p.PackUint64(s.Offences)
+	p.PackUint64(s.JailedUntil)
+	p.PackUint64(s.SlashedAmount)
+	return p.Bytes(), p.Err()
+}
+
+func UnmarshalSlashEvent(b []byte) (*SlashEvent, error) {
+	p := codec.NewReader(b, slashEventLen)
+	s := &SlashEvent{}
+	nodeIDBytes := make([]byte, hconsts.NodeIDLen)
+	p.UnpackFixedBytes(hconsts.NodeIDLen, &nodeIDBytes)
+	copy(s.NodeID[:], nodeIDBytes)
+	s.BlockHeight = p.UnpackUint64(false)
+	s.Epoch = p.UnpackUint64(false)
+	s.Uptime = p.UnpackUint64(false)
+	s.Offences = p.UnpackUint64(false)
+	s.JailedUntil = p.UnpackUint64(false)
+	s.SlashedAmount = p.UnpackUint64(false)
+	return s, p.Err()
+}
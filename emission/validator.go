@@ -0,0 +1,421 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package emission
+
+import (
+	"math"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+)
+
+const (
+	// ValidatorRecordLen is the length of a serialized validator record.
+	ValidatorRecordLen = hconsts.ByteLen + 22*hconsts.Uint64Len + 2*RewardIndexLen
+
+	// MaxOffencesPerSync is the maximum number of uptime offences a single
+	// call to [ValidatorRecord.Sync] records, so that the work done by a
+	// transaction is bounded. The epochs that are left are synced by the next
+	// transactions of the validator.
+	MaxOffencesPerSync = 16
+
+	validatorRecordVersion = byte(0x0)
+)
+
+// Stake is the stake of a validator, as kept in its stake record in state.
+type Stake struct {
+	StakeStartBlock          uint64 // Start block of the stake
+	StakeEndBlock            uint64 // End block of the stake
+	StakedAmount             uint64 // Amount staked by the validator
+	DelegationFeeRate        uint64 // Fee rate for delegations, in percent
+	PendingDelegationFeeRate uint64 // Fee rate for delegations that takes effect at FeeRateEffectiveBlock
+	FeeRateEffectiveBlock    uint64 // Block height at which the pending fee rate takes effect, 0 if none
+}
+
+// ValidatorRecord is what the emission balancer keeps for a validator, from
+// the moment it registers its stake until it withdrew it and all of its
+// delegations are undelegated. When the validator withdraws, the delegations
+// to its stake are set aside: their weight and amount are dropped from the
+// validator, and they keep the rewards they earned until they are undelegated,
+// even if the validator registers a new stake in the meantime. Delegations are
+// told apart by their start block, as a delegation always starts before the
+// validator stake it was made to can be withdrawn.
+type ValidatorRecord struct {
+	DelegatedAmount            uint64      `json:"delegatedAmount"`            // Total amount delegated to the validator by delegations that were not undelegated yet
+	DelegatedWeight            uint64      `json:"delegatedWeight"`            // Total reward weight of these delegations
+	Delegations                uint64      `json:"delegations"`                // Number of these delegations
+	AccumulatedStakedReward    uint64      `json:"accumulatedStakedReward"`    // Total rewards accumulated by the validator
+	AccumulatedDelegatedReward uint64      `json:"accumulatedDelegatedReward"` // Total rewards accumulated by the delegators of the validator
+	RewardIndex                RewardIndex `json:"-"`                          // Delegation rewards distributed per unit of delegated weight
+	RewardHeight               uint64      `json:"rewardHeight"`               // Height the rewards of the validator were last paid out at
+	JailedUntil                uint64      `json:"jailedUntil"`                // Block height until which the validator is jailed
+	Offences                   uint64      `json:"offences"`                   // Number of epochs the validator was below the minimum uptime
+	SlashedAmount              uint64      `json:"slashedAmount"`              // Amount slashed from the validator's stake during the last sync that was not taken off it yet
+	HeartbeatEpoch             uint64      `json:"heartbeatEpoch"`             // Epoch of the heartbeat slots filled
+	HeartbeatSlots             uint64      `json:"heartbeatSlots"`             // Heartbeat slots filled in that epoch
+	LastHeartbeatSlot          uint64      `json:"lastHeartbeatSlot"`          // Last heartbeat slot filled, plus one
+	RegisteredEpoch            uint64      `json:"registeredEpoch"`            // Epoch of the boundary the validator is registered for, 0 if none
+	RankStake                  uint64      `json:"rankStake"`                  // Stake the validator is ranked by in the active set it is registered for
+	RegisteredStake            uint64      `json:"registeredStake"`            // Stake, including delegations, the validator is registered with
+	RegisteredWeight           uint64      `json:"registeredWeight"`           // Reward weight, including delegations, the validator is registered with
+	RegisteredDelegatedWeight  uint64      `json:"registeredDelegatedWeight"`  // Part of that weight that is delegated
+	SyncedEpoch                uint64      `json:"syncedEpoch"`                // Epoch of the last boundary the record was synced up to
+	WithdrawnHeight            uint64      `json:"withdrawnHeight"`            // Height the validator last withdrew its stake at, delegations that started before it were made to a withdrawn stake
+	WithdrawnIndex             RewardIndex `json:"-"`                          // Reward index at that height
+	WithdrawnReward            uint64      `json:"withdrawnReward"`            // Rewards accumulated by the delegations to that stake that were not paid out yet
+	WithdrawnDelegations       uint64      `json:"withdrawnDelegations"`       // Number of these delegations that were not undelegated yet
+	ForfeitedHeight            uint64      `json:"forfeitedHeight"`            // Delegations that started before this height were made to an earlier withdrawn stake, and forfeited their rewards
+}
+
+// NewValidatorRecord returns the record of a validator that registers its
+// stake once [h] is processed up to the block being executed. Delegations that
+// are left from a stake the validator withdrew before its previous record was
+// removed earn nothing from it.
+func NewValidatorRecord(h *Header, epochTracker EpochTracker) *ValidatorRecord {
+	return &ValidatorRecord{
+		RewardHeight:    h.ProcessedHeight,
+		SyncedEpoch:     h.Epoch(epochTracker.EpochLength),
+		ForfeitedHeight: h.ProcessedHeight - min(1, h.ProcessedHeight),
+	}
+}
+
+// Copy returns a copy of the record.
+func (v *ValidatorRecord) Copy() *ValidatorRecord {
+	c := *v
+	return &c
+}
+
+// Sync settles the epoch boundaries distributed by [h] since the record was
+// last synced. The validator collects its share of the rewards of the boundary
+// it registered for, provided that it was in the active set and reached the
+// minimum uptime, and it is jailed, and slashed once it is a repeat offender,
+// for every epoch it was expected to be online in but was below the minimum
+// uptime. Only these boundaries are visited, so the work done does not grow
+// with the time since the last sync. [stake] is the stake of the validator,
+// nil if it was withdrawn. The stake slashed is kept in SlashedAmount until
+// [ValidatorRecord.SettleSlash] takes it off the stake.
+//
+// What was collected and slashed is recorded in [ledger].
+func (v *ValidatorRecord) Sync(nodeID ids.NodeID, stake *Stake, h *Header, stakingConfig StakingConfig, epochTracker EpochTracker, ledger *Ledger) {
+	epochLength := epochTracker.EpochLength
+	lastEpoch := h.Epoch(epochLength)
+	offences := 0
+	for epoch := v.nextEpoch(v.SyncedEpoch+1, stake, stakingConfig, epochTracker); epoch <= lastEpoch; epoch = v.nextEpoch(epoch+1, stake, stakingConfig, epochTracker) {
+		uptime, tracked := v.uptime(epoch, stakingConfig, epochTracker)
+		online := !tracked || uptime >= stakingConfig.MinUptime
+		if v.RegisteredEpoch == epoch {
+			if online {
+				v.collect(nodeID, epoch, stake, h, epochLength, ledger)
+			}
+			v.clearRegistration()
+		}
+		if online || !v.expected(epoch, stake, epochLength) {
+			continue
+		}
+		v.commitOffence(nodeID, epoch, uptime, stake, stakingConfig, epochLength, ledger)
+		if offences++; offences == MaxOffencesPerSync {
+			v.SyncedEpoch = epoch
+			return
+		}
+	}
+	v.SyncedEpoch = max(v.SyncedEpoch, lastEpoch)
+}
+
+// nextEpoch returns the first epoch from [from] whose boundary the validator
+// registered for or was expected to be online at.
+func (v *ValidatorRecord) nextEpoch(from uint64, stake *Stake, stakingConfig StakingConfig, epochTracker EpochTracker) uint64 {
+	next := uint64(math.MaxUint64)
+	if v.RegisteredEpoch >= from {
+		next = v.RegisteredEpoch
+	}
+	if stake == nil || stake.StakeEndBlock == 0 || slotsPerEpoch(stakingConfig, epochTracker) == 0 {
+		return next
+	}
+
+	// The validator is expected to be online in the epochs that start after
+	// its stake started and its jail ended, and end before its stake ends
+	epochLength := epochTracker.EpochLength
+	expected := max(from, ceilDiv(stake.StakeStartBlock, epochLength)+1, ceilDiv(v.JailedUntil, epochLength)+1)
+	if expected <= (stake.StakeEndBlock-1)/epochLength {
+		next = min(next, expected)
+	}
+	return next
+}
+
+// expected returns whether the validator was expected to be online in the
+// epoch that ended at the boundary of [epoch], i.e. it was staked and not
+// jailed for the whole epoch and still staked at the boundary.
+func (v *ValidatorRecord) expected(epoch uint64, stake *Stake, epochLength uint64) bool {
+	epochStart := (epoch - 1) * epochLength
+	return stake != nil && stake.StakedAmount > 0 &&
+		stake.StakeStartBlock <= epochStart && epoch*epochLength < stake.StakeEndBlock &&
+		v.JailedUntil <= epochStart
+}
+
+// collect credits the validator with its share of the rewards distributed at
+// the boundary of [epoch], if it was in the active set, and splits it with
+// its delegators.
+func (v *ValidatorRecord) collect(nodeID ids.NodeID, epoch uint64, stake *Stake, h *Header, epochLength uint64, ledger *Ledger) {
+	d, ok := h.distribution(epoch)
+	if !ok || !d.Threshold.admits(v.RankStake, nodeID) {
+		return
+	}
+	totalValidatorAmount := min(d.Index.earned(RewardIndex{}, v.RegisteredWeight), d.Remaining)
+	if totalValidatorAmount == 0 {
+		return
+	}
+	d.Remaining -= totalValidatorAmount
+
+	delegationFeeRate := uint64(0)
+	if stake != nil {
+		delegationFeeRate, _, _ = EffectiveDelegationFeeRate(stake.DelegationFeeRate, stake.PendingDelegationFeeRate, stake.FeeRateEffectiveBlock, epoch*epochLength)
+	}
+	validatorAmount, delegationAmount := distributeValidatorRewards(totalValidatorAmount, delegationFeeRate, v.RegisteredDelegatedWeight)
+	if delegationAmount > 0 {
+		v.RewardIndex = v.RewardIndex.add(delegationAmount, v.RegisteredDelegatedWeight)
+		v.AccumulatedDelegatedReward += delegationAmount
+	}
+	v.AccumulatedStakedReward += validatorAmount
+
+	mintedAmount := mulDiv(totalValidatorAmount, d.Minted, d.Total)
+	ledger.addEpochReward(&EpochReward{
+		NodeID:           nodeID,
+		Epoch:            epoch,
+		MintedReward:     mintedAmount,
+		FeeReward:        totalValidatorAmount - mintedAmount,
+		ValidatorReward:  validatorAmount,
+		DelegationReward: delegationAmount,
+		DelegatedWeight:  v.RegisteredDelegatedWeight,
+	})
+}
+
+// Register registers the validator for the rewards of the next epoch boundary
+// and ranks it in its active set, unless it is registered for it already.
+// Validators register with their first heartbeat of every epoch. The record
+// must be synced, the stake of the validator must have started by
+// [blockHeight] and cover the boundary, and the validator must not be jailed
+// at the boundary.
+func (v *ValidatorRecord) Register(nodeID ids.NodeID, stake *Stake, blockHeight uint64, h *Header, loadActiveSet ActiveSetLoader, stakingConfig StakingConfig, epochTracker EpochTracker) error {
+	epochLength := epochTracker.EpochLength
+	epoch := h.Epoch(epochLength) + 1
+	if v.RegisteredEpoch == epoch || v.SyncedEpoch+1 < epoch {
+		return nil
+	}
+	if stake == nil || stake.StakeStartBlock > blockHeight || stake.StakeEndBlock <= epoch*epochLength || v.JailedUntil > epoch*epochLength {
+		return nil
+	}
+	v.RegisteredEpoch = epoch
+	v.RankStake = 0
+	return v.UpdateRegistration(nodeID, stake, h, loadActiveSet, stakingConfig, epochTracker)
+}
+
+// UpdateRegistration brings the registration of the validator for the next
+// epoch boundary, if any, in line with its stake and delegations after they
+// changed, and ranks it again. A validator that withdrew its stake, i.e.
+// [stake] is nil, is deregistered.
+func (v *ValidatorRecord) UpdateRegistration(nodeID ids.NodeID, stake *Stake, h *Header, loadActiveSet ActiveSetLoader, stakingConfig StakingConfig, epochTracker EpochTracker) error {
+	if v.RegisteredEpoch != h.Epoch(epochTracker.EpochLength)+1 {
+		return nil
+	}
+	s, err := h.activeSet(loadActiveSet, epochTracker)
+	if err != nil {
+		return err
+	}
+	if stake == nil {
+		s.remove(h, nodeID)
+		v.clearRegistration()
+		return nil
+	}
+
+	stakedAmount := stake.StakedAmount - min(v.SlashedAmount, stake.StakedAmount)
+	v.RegisteredStake = stakedAmount + v.DelegatedAmount
+	v.RegisteredDelegatedWeight = v.DelegatedWeight
+	v.RegisteredWeight = stakingConfig.StakeWeight(stakedAmount, stake.StakeStartBlock, stake.StakeEndBlock, stakingConfig.MaxValidatorStakeDuration) + v.DelegatedWeight
+	entry := &ActiveSetEntry{
+		NodeID:    nodeID,
+		RankStake: max(v.RankStake, v.RegisteredStake),
+		Stake:     v.RegisteredStake,
+		Weight:    v.RegisteredWeight,
+	}
+	s.put(h, entry, epochTracker.MaxActiveValidators)
+	v.RankStake = entry.RankStake
+	return nil
+}
+
+// DelegationCapacity returns the stake that can still be delegated to the
+// validator, given its [stake], under [stakingConfig]. Every delegation counts
+// towards it until it is undelegated.
+func (v *ValidatorRecord) DelegationCapacity(stake *Stake, stakingConfig StakingConfig) uint64 {
+	if stake == nil {
+		return 0
+	}
+	return stakingConfig.DelegationCapacity(stake.StakedAmount-min(v.SlashedAmount, stake.StakedAmount), v.DelegatedAmount)
+}
+
+// IsRegistered returns whether the validator is registered for the next
+// epoch boundary.
+func (v *ValidatorRecord) IsRegistered(h *Header, epochTracker EpochTracker) bool {
+	return v.RegisteredEpoch > 0 && v.RegisteredEpoch == h.Epoch(epochTracker.EpochLength)+1
+}
+
+func (v *ValidatorRecord) clearRegistration() {
+	v.RegisteredEpoch = 0
+	v.RankStake = 0
+	v.RegisteredStake = 0
+	v.RegisteredWeight = 0
+	v.RegisteredDelegatedWeight = 0
+}
+
+// ClaimRewards pays out the rewards accumulated by the validator and returns
+// them.
+func (v *ValidatorRecord) ClaimRewards(h *Header) uint64 {
+	rewardAmount := v.AccumulatedStakedReward
+	v.AccumulatedStakedReward = 0
+	v.RewardHeight = h.ProcessedHeight
+	h.pay(rewardAmount)
+	return rewardAmount
+}
+
+// Unpaid returns the rewards accumulated by the validator and its delegators
+// that were not paid out yet.
+func (v *ValidatorRecord) Unpaid() uint64 {
+	return v.AccumulatedStakedReward + v.AccumulatedDelegatedReward + v.WithdrawnReward
+}
+
+// SettleSlash takes the stake slashed from the validator during the last sync
+// off its [stake] and hands it over to the emission account. It returns the
+// slashed stake, which must be taken off the stake in state, and off the
+// supply of NAI held in state, in the same transaction.
+func (v *ValidatorRecord) SettleSlash(stake *Stake, h *Header) uint64 {
+	if stake == nil {
+		return 0
+	}
+	slashedAmount := min(v.SlashedAmount, stake.StakedAmount)
+	v.SlashedAmount = 0
+	stake.StakedAmount -= slashedAmount
+	h.CollectSlash(slashedAmount)
+	return slashedAmount
+}
+
+// Withdraw pays out the rewards of a validator that withdraws its stake in the
+// block after [lastBlockHeight], deregisters it and returns the rewards. Its
+// delegations are set aside: they stop earning and no longer count towards
+// its delegated amount and weight, and they can be undelegated right away.
+// Delegations left from a stake it withdrew before forfeit their rewards to
+// the emission account.
+func (v *ValidatorRecord) Withdraw(nodeID ids.NodeID, lastBlockHeight uint64, h *Header, loadActiveSet ActiveSetLoader, stakingConfig StakingConfig, epochTracker EpochTracker) (uint64, error) {
+	if err := v.UpdateRegistration(nodeID, nil, h, loadActiveSet, stakingConfig, epochTracker); err != nil {
+		return 0, err
+	}
+	rewardAmount := v.ClaimRewards(h)
+	h.EmissionAccount.AccumulatedReward += v.WithdrawnReward
+
+	v.ForfeitedHeight = v.WithdrawnHeight
+	v.WithdrawnHeight = lastBlockHeight
+	v.WithdrawnIndex = v.RewardIndex
+	v.WithdrawnReward = v.AccumulatedDelegatedReward
+	v.WithdrawnDelegations = v.Delegations
+	v.AccumulatedDelegatedReward = 0
+	v.DelegatedAmount = 0
+	v.DelegatedWeight = 0
+	v.Delegations = 0
+	return rewardAmount, nil
+}
+
+// Close returns whether the record of a validator that withdrew its stake can
+// be removed from state, i.e. it has no delegations left. Rewards that are left
+// over because of rounding are then kept by the emission account so that they
+// remain part of the total supply.
+func (v *ValidatorRecord) Close(h *Header) bool {
+	if v.Delegations > 0 || v.WithdrawnDelegations > 0 {
+		return false
+	}
+	h.EmissionAccount.AccumulatedReward += v.Unpaid()
+	v.AccumulatedStakedReward = 0
+	v.AccumulatedDelegatedReward = 0
+	v.WithdrawnReward = 0
+	return true
+}
+
+// ceilDiv returns a/b rounded up.
+func ceilDiv(a, b uint64) uint64 {
+	if a == 0 {
+		return 0
+	}
+	return (a-1)/b + 1
+}
+
+func (v *ValidatorRecord) Marshal() ([]byte, error) {
+	p := codec.NewWriter(ValidatorRecordLen, ValidatorRecordLen)
+	p.PackByte(validatorRecordVersion)
+	p.PackUint64(v.DelegatedAmount)
+	p.PackUint64(v.DelegatedWeight)
+	p.PackUint64(v.Delegations)
+	p.PackUint64(v.AccumulatedStakedReward)
+	p.PackUint64(v.AccumulatedDelegatedReward)
+	p.PackFixedBytes(v.RewardIndex[:])
+	p.PackUint64(v.RewardHeight)
+	p.PackUint64(v.JailedUntil)
+	p.PackUint64(v.Offences)
+	p.PackUint64(v.SlashedAmount)
+	p.PackUint64(v.HeartbeatEpoch)
+	p.PackUint64(v.HeartbeatSlots)
+	p.PackUint64(v.LastHeartbeatSlot)
+	p.PackUint64(v.RegisteredEpoch)
+	p.PackUint64(v.RankStake)
+	p.PackUint64(v.RegisteredStake)
+	p.PackUint64(v.RegisteredWeight)
+	p.PackUint64(v.RegisteredDelegatedWeight)
+	p.PackUint64(v.SyncedEpoch)
+	p.PackUint64(v.WithdrawnHeight)
+	p.PackFixedBytes(v.WithdrawnIndex[:])
+	p.PackUint64(v.WithdrawnReward)
+	p.PackUint64(v.WithdrawnDelegations)
+	p.PackUint64(v.ForfeitedHeight)
+	return p.Bytes(), p.Err()
+}
+
+func UnmarshalValidatorRecord(b []byte) (*ValidatorRecord, error) {
+	p := codec.NewReader(b, ValidatorRecordLen)
+	if version := p.UnpackByte(); p.Err() == nil && version != validatorRecordVersion {
+		return nil, ErrInvalidRecord
+	}
+	v := &ValidatorRecord{}
+	v.DelegatedAmount = p.UnpackUint64(false)
+	v.DelegatedWeight = p.UnpackUint64(false)
+	v.Delegations = p.UnpackUint64(false)
+	v.AccumulatedStakedReward = p.UnpackUint64(false)
+	v.AccumulatedDelegatedReward = p.UnpackUint64(false)
+	rewardIndex := make([]byte, RewardIndexLen)
+	p.UnpackFixedBytes(RewardIndexLen, &rewardIndex)
+	copy(v.RewardIndex[:], rewardIndex)
+	v.RewardHeight = p.UnpackUint64(false)
+	v.JailedUntil = p.UnpackUint64(false)
+	v.Offences = p.UnpackUint64(false)
+	v.SlashedAmount = p.UnpackUint64(false)
+	v.HeartbeatEpoch = p.UnpackUint64(false)
+	v.HeartbeatSlots = p.UnpackUint64(false)
+	v.LastHeartbeatSlot = p.UnpackUint64(false)
+	v.RegisteredEpoch = p.UnpackUint64(false)
+	v.RankStake = p.UnpackUint64(false)
+	v.RegisteredStake = p.UnpackUint64(false)
+	v.RegisteredWeight = p.UnpackUint64(false)
+	v.RegisteredDelegatedWeight = p.UnpackUint64(false)
+	v.SyncedEpoch = p.UnpackUint64(false)
+	v.WithdrawnHeight = p.UnpackUint64(false)
+	withdrawnIndex := make([]byte, RewardIndexLen)
+	p.UnpackFixedBytes(RewardIndexLen, &withdrawnIndex)
+	copy(v.WithdrawnIndex[:], withdrawnIndex)
+	v.WithdrawnReward = p.UnpackUint64(false)
+	v.WithdrawnDelegations = p.UnpackUint64(false)
+	v.ForfeitedHeight = p.UnpackUint64(false)
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	if !p.Empty() {
+		return nil, ErrInvalidRecord
+	}
+	return v, nil
+}
@@ -6,6 +6,7 @@ package genesis
 import "errors"
 
 var (
-	ErrInvalidHRP    = errors.New("invalid HRP")
-	ErrInvalidTarget = errors.New("invalid target")
+	ErrInvalidHRP     = errors.New("invalid HRP")
+	ErrInvalidTarget  = errors.New("invalid target")
+	ErrInvalidUpgrade = errors.New("invalid upgrade")
 )
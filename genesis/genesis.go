@@ -31,8 +31,9 @@ type CustomAllocation struct {
 }
 
 type EmissionBalancer struct {
-	MaxSupply       uint64 `json:"maxSupply"`       // Max supply of NAI
-	EmissionAddress string `json:"emissionAddress"` // Emission address
+	MaxSupply       uint64            `json:"maxSupply"`       // Max supply of NAI
+	EmissionAddress string            `json:"emissionAddress"` // Emission address
+	FeeSplit        emission.FeeSplit `json:"feeSplit"`        // Split of the transaction fees between the emission account, validators and burn
 }
 
 type Genesis struct {
@@ -69,6 +70,13 @@ type Genesis struct {
 
 	// Emission Balancer Info
 	EmissionBalancer EmissionBalancer `json:"emissionBalancer"`
+
+	// Staking Parameters
+	StakingConfig emission.StakingConfig `json:"stakingConfig"`
+	EpochTracker  emission.EpochTracker  `json:"epochTracker"`
+
+	// Parameters changes scheduled through upgradeBytes
+	upgrades []*Upgrade
 }
 
 func Default() *Genesis {
@@ -106,19 +114,38 @@ func Default() *Genesis {
 		StorageValueWriteUnits:    3,
 
 		EmissionBalancer: EmissionBalancer{
-			MaxSupply:       emission.GetStakingConfig().RewardConfig.SupplyCap,       // 10 billion NAI,
-			EmissionAddress: emission.GetStakingConfig().RewardConfig.EmissionAddress, // NAI emission address(If you don't pass this address, it will be set to the default address)
+			MaxSupply:       emission.DefaultStakingConfig().RewardConfig.SupplyCap,       // 10 billion NAI,
+			EmissionAddress: emission.DefaultStakingConfig().RewardConfig.EmissionAddress, // NAI emission address(If you don't pass this address, it will be set to the default address)
+			FeeSplit:        emission.DefaultFeeSplit(),
 		},
+
+		// Staking Parameters
+		StakingConfig: emission.DefaultStakingConfig(),
+		EpochTracker:  emission.DefaultEpochTracker(),
 	}
 }
 
-func New(b []byte, _ []byte /* upgradeBytes */) (*Genesis, error) {
+func New(b []byte, upgradeBytes []byte) (*Genesis, error) {
 	g := Default()
 	if len(b) > 0 {
 		if err := json.Unmarshal(b, g); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal config %s: %w", string(b), err)
 		}
 	}
+	if err := g.EmissionBalancer.FeeSplit.Verify(); err != nil {
+		return nil, err
+	}
+	if err := g.StakingConfig.Verify(); err != nil {
+		return nil, err
+	}
+	if err := g.EpochTracker.Verify(); err != nil {
+		return nil, err
+	}
+	upgrades, err := parseUpgrades(upgradeBytes, g.StakingConfig, g.EpochTracker)
+	if err != nil {
+		return nil, err
+	}
+	g.upgrades = upgrades
 	return g, nil
 }
 
@@ -145,7 +172,7 @@ func (g *Genesis) Load(ctx context.Context, tracer trace.Tracer, mu state.Mutabl
 		}
 	}
 
-	return storage.SetAsset(
+	if err := storage.SetAsset(
 		ctx,
 		mu,
 		ids.Empty,
@@ -153,9 +180,33 @@ func (g *Genesis) Load(ctx context.Context, tracer trace.Tracer, mu state.Mutabl
 		consts.Decimals,
 		[]byte(consts.Name),
 		supply,
+		g.EmissionBalancer.MaxSupply,
 		codec.EmptyAddress,
 		false,
-	)
+		false,
+	); err != nil {
+		return err
+	}
+
+	// The emission balancer starts without holding any NAI, and processes its
+	// first block when it is first used
+	emissionAddr, err := codec.ParseAddressBech32(consts.HRP, g.EmissionBalancer.EmissionAddress)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, g.EmissionBalancer.EmissionAddress)
+	}
+	header, err := emission.NewHeader(g.EmissionBalancer.MaxSupply, emissionAddr).Marshal()
+	if err != nil {
+		return err
+	}
+	if err := storage.SetEmission(ctx, mu, header); err != nil {
+		return err
+	}
+	for shard := uint8(0); shard < storage.NumFeeShards; shard++ {
+		if err := storage.SetFeeShard(ctx, mu, shard, 0, 0); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (g *Genesis) GetStateBranchFactor() merkledb.BranchFactor {
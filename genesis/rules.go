@@ -6,6 +6,8 @@ package genesis
 import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/hypersdk/chain"
+
+	"github.com/nuklai/nuklaivm/emission"
 	"github.com/nuklai/nuklaivm/storage"
 )
 
@@ -16,11 +18,29 @@ type Rules struct {
 
 	networkID uint32
 	chainID   ids.ID
-}
 
-// TODO: use upgradeBytes
-func (g *Genesis) Rules(_ int64, networkID uint32, chainID ids.ID) *Rules {
-	return &Rules{g, networkID, chainID}
+	stakingConfig emission.StakingConfig
+	epochTracker  emission.EpochTracker
+}
+
+// Rules returns the rules in effect at [t], including any parameters changed
+// by upgrades scheduled at or before [t].
+func (g *Genesis) Rules(t int64, networkID uint32, chainID ids.ID) *Rules {
+	r := &Rules{
+		g:             g,
+		networkID:     networkID,
+		chainID:       chainID,
+		stakingConfig: g.StakingConfig,
+		epochTracker:  g.EpochTracker,
+	}
+	for _, upgrade := range g.upgrades {
+		if upgrade.Timestamp > t {
+			break
+		}
+		r.stakingConfig = upgrade.stakingConfig
+		r.epochTracker = upgrade.epochTracker
+	}
+	return r
 }
 
 func (*Rules) GetWarpConfig(ids.ID) (bool, uint64, uint64) {
@@ -72,7 +92,7 @@ func (r *Rules) GetOutgoingWarpComputeUnits() uint64 {
 }
 
 func (*Rules) GetSponsorStateKeysMaxChunks() []uint16 {
-	return []uint16{storage.BalanceChunks}
+	return []uint16{storage.BalanceChunks, storage.FeeShardChunks}
 }
 
 func (r *Rules) GetStorageKeyReadUnits() uint64 {
@@ -111,6 +131,23 @@ func (r *Rules) GetWindowTargetUnits() chain.Dimensions {
 	return r.g.WindowTargetUnits
 }
 
-func (*Rules) FetchCustom(string) (any, bool) {
-	return nil, false
+func (r *Rules) GetStakingConfig() emission.StakingConfig {
+	return r.stakingConfig
+}
+
+func (r *Rules) GetEpochTracker() emission.EpochTracker {
+	return r.epochTracker
+}
+
+func (r *Rules) FetchCustom(key string) (any, bool) {
+	switch key {
+	case emission.StakingConfigKey:
+		return r.stakingConfig, true
+	case emission.EpochTrackerKey:
+		return r.epochTracker, true
+	case emission.FeeSplitKey:
+		return r.g.EmissionBalancer.FeeSplit, true
+	default:
+		return nil, false
+	}
 }
@@ -0,0 +1,88 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package genesis
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/nuklai/nuklaivm/emission"
+)
+
+// Upgrade schedules new staking parameters to take effect at [Timestamp].
+// Only the fields set in [StakingConfig] and [EpochTracker] change, the
+// omitted ones keep the value they had before the upgrade. The epoch length
+// can't be changed, as epochs are counted from the genesis block.
+type Upgrade struct {
+	Timestamp     int64           `json:"timestamp"` // ms
+	StakingConfig json.RawMessage `json:"stakingConfig,omitempty"`
+	EpochTracker  json.RawMessage `json:"epochTracker,omitempty"`
+
+	// Parameters in effect from [Timestamp] onwards
+	stakingConfig emission.StakingConfig
+	epochTracker  emission.EpochTracker
+}
+
+type Upgrades struct {
+	Upgrades []*Upgrade `json:"upgrades"`
+}
+
+// parseUpgrades parses the upgrades in [b] and resolves the parameters in
+// effect after each of them, starting from [stakingConfig] and [epochTracker]
+// of the genesis.
+func parseUpgrades(
+	b []byte,
+	stakingConfig emission.StakingConfig,
+	epochTracker emission.EpochTracker,
+) ([]*Upgrade, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	var u Upgrades
+	if err := json.Unmarshal(b, &u); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal upgrades %s: %w", string(b), err)
+	}
+	sort.SliceStable(u.Upgrades, func(i, j int) bool {
+		return u.Upgrades[i].Timestamp < u.Upgrades[j].Timestamp
+	})
+	for i, upgrade := range u.Upgrades {
+		if upgrade.Timestamp <= 0 {
+			return nil, fmt.Errorf("%w: timestamp must be over 0", ErrInvalidUpgrade)
+		}
+		if i > 0 && upgrade.Timestamp == u.Upgrades[i-1].Timestamp {
+			return nil, fmt.Errorf("%w: duplicate timestamp %d", ErrInvalidUpgrade, upgrade.Timestamp)
+		}
+
+		// Unmarshalling over the previous parameters only overwrites the
+		// fields that are set. The lockup tiers are cloned first so that
+		// replacing them doesn't modify the previous parameters.
+		upgrade.stakingConfig = stakingConfig
+		upgrade.stakingConfig.LockupTiers = slices.Clone(stakingConfig.LockupTiers)
+		if len(upgrade.StakingConfig) > 0 {
+			if err := json.Unmarshal(upgrade.StakingConfig, &upgrade.stakingConfig); err != nil {
+				return nil, fmt.Errorf("%w: failed to unmarshal staking config at %d: %w", ErrInvalidUpgrade, upgrade.Timestamp, err)
+			}
+			if err := upgrade.stakingConfig.Verify(); err != nil {
+				return nil, err
+			}
+		}
+		upgrade.epochTracker = epochTracker
+		if len(upgrade.EpochTracker) > 0 {
+			if err := json.Unmarshal(upgrade.EpochTracker, &upgrade.epochTracker); err != nil {
+				return nil, fmt.Errorf("%w: failed to unmarshal epoch tracker at %d: %w", ErrInvalidUpgrade, upgrade.Timestamp, err)
+			}
+			if err := upgrade.epochTracker.Verify(); err != nil {
+				return nil, err
+			}
+			if upgrade.epochTracker.EpochLength != epochTracker.EpochLength {
+				return nil, fmt.Errorf("%w: epoch length can't be changed", ErrInvalidUpgrade)
+			}
+		}
+		stakingConfig = upgrade.stakingConfig
+		epochTracker = upgrade.epochTracker
+	}
+	return u.Upgrades, nil
+}
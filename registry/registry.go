@@ -36,6 +36,23 @@ func init() {
 		nconsts.ActionRegistry.Register((&actions.DelegateUserStake{}).GetTypeID(), actions.UnmarshalDelegateUserStake, false),
 		nconsts.ActionRegistry.Register((&actions.ClaimDelegationStakeRewards{}).GetTypeID(), actions.UnmarshalClaimDelegationStakeRewards, false),
 		nconsts.ActionRegistry.Register((&actions.UndelegateUserStake{}).GetTypeID(), actions.UnmarshalUndelegateUserStake, false),
+		nconsts.ActionRegistry.Register((&actions.ClaimEmissionFees{}).GetTypeID(), actions.UnmarshalClaimEmissionFees, false),
+		nconsts.ActionRegistry.Register((&actions.ValidatorHeartbeat{}).GetTypeID(), actions.UnmarshalValidatorHeartbeat, false),
+		nconsts.ActionRegistry.Register((&actions.ReleaseUnbondedStake{}).GetTypeID(), actions.UnmarshalReleaseUnbondedStake, false),
+		nconsts.ActionRegistry.Register((&actions.RedelegateUserStake{}).GetTypeID(), actions.UnmarshalRedelegateUserStake, false),
+		nconsts.ActionRegistry.Register((&actions.IncreaseDelegation{}).GetTypeID(), actions.UnmarshalIncreaseDelegation, false),
+		nconsts.ActionRegistry.Register((&actions.DecreaseDelegation{}).GetTypeID(), actions.UnmarshalDecreaseDelegation, false),
+		nconsts.ActionRegistry.Register((&actions.IncreaseValidatorStake{}).GetTypeID(), actions.UnmarshalIncreaseValidatorStake, false),
+		nconsts.ActionRegistry.Register((&actions.DecreaseValidatorStake{}).GetTypeID(), actions.UnmarshalDecreaseValidatorStake, false),
+		nconsts.ActionRegistry.Register((&actions.UpdateValidatorStake{}).GetTypeID(), actions.UnmarshalUpdateValidatorStake, false),
+		nconsts.ActionRegistry.Register((&actions.UpdateAsset{}).GetTypeID(), actions.UnmarshalUpdateAsset, false),
+		nconsts.ActionRegistry.Register((&actions.CreateNFTCollection{}).GetTypeID(), actions.UnmarshalCreateNFTCollection, false),
+		nconsts.ActionRegistry.Register((&actions.MintNFT{}).GetTypeID(), actions.UnmarshalMintNFT, false),
+		nconsts.ActionRegistry.Register((&actions.TransferNFT{}).GetTypeID(), actions.UnmarshalTransferNFT, false),
+		nconsts.ActionRegistry.Register((&actions.BurnNFT{}).GetTypeID(), actions.UnmarshalBurnNFT, false),
+		nconsts.ActionRegistry.Register((&actions.ApproveAsset{}).GetTypeID(), actions.UnmarshalApproveAsset, false),
+		nconsts.ActionRegistry.Register((&actions.TransferFrom{}).GetTypeID(), actions.UnmarshalTransferFrom, false),
+		nconsts.ActionRegistry.Register((&actions.MultiTransfer{}).GetTypeID(), actions.UnmarshalMultiTransfer, false),
 
 		// When registering new auth, ALWAYS make sure to append at the end.
 		nconsts.AuthRegistry.Register((&auth.ED25519{}).GetTypeID(), auth.UnmarshalED25519, false),
@@ -12,19 +12,33 @@ import (
 	"github.com/ava-labs/hypersdk/codec"
 	"github.com/nuklai/nuklaivm/emission"
 	"github.com/nuklai/nuklaivm/genesis"
+	"github.com/nuklai/nuklaivm/storage"
 )
 
 type Controller interface {
 	Genesis() *genesis.Genesis
 	Tracer() trace.Tracer
 	GetTransaction(context.Context, ids.ID) (bool, int64, bool, chain.Dimensions, uint64, error)
-	GetAssetFromState(context.Context, ids.ID) (bool, []byte, uint8, []byte, uint64, codec.Address, bool, error)
+	GetAssetFromState(context.Context, ids.ID) (bool, []byte, uint8, []byte, uint64, uint64, codec.Address, bool, bool, error)
 	GetBalanceFromState(context.Context, codec.Address, ids.ID) (uint64, error)
 	GetLoanFromState(context.Context, ids.ID, ids.ID) (uint64, error)
+	GetAllowanceFromState(context.Context, codec.Address, codec.Address, ids.ID) (uint64, uint64, bool, error)
+	GetNFTCollectionFromState(context.Context, ids.ID) (bool, []byte, []byte, uint64, codec.Address, error)
+	GetNFTFromState(context.Context, ids.ID, uint64) (bool, codec.Address, []byte, error)
+	GetNFTsOfOwner(owner codec.Address, start *storage.OwnedNFT, limit int) ([]*storage.OwnedNFT, *storage.OwnedNFT, error)
 
-	GetEmissionInfo() (uint64, uint64, uint64, uint64, uint64, emission.EmissionAccount, emission.EpochTracker, error)
+	GetEmissionInfo(ctx context.Context) (uint64, uint64, uint64, uint64, uint64, emission.EmissionAccount, emission.EpochTracker, error)
 	GetValidators(ctx context.Context, staked bool) ([]*emission.Validator, error)
-	GetStakedValidatorInfo(nodeID ids.NodeID) (*emission.Validator, error)
+	GetStakedValidatorInfo(ctx context.Context, nodeID ids.NodeID) (*emission.Validator, error)
+	GetSlashHistory(ctx context.Context, nodeID ids.NodeID) ([]*emission.SlashEvent, error)
+	GetLockupMultiplier(stakeStartBlock, stakeEndBlock uint64, delegated bool) uint64
+	GetDelegationCapacity(ctx context.Context, nodeID ids.NodeID) (uint64, error)
+	InvariantCheckEnabled() bool
+	GetSupplyInfo(ctx context.Context) (uint64, uint64, uint64, *storage.NAIHoldings, error)
+	GetRewardHistory(ctx context.Context, nodeID ids.NodeID, fromEpoch, toEpoch uint64) ([]*emission.EpochReward, error)
+	GetDelegatorRewardHistory(ctx context.Context, owner codec.Address, fromEpoch, toEpoch uint64) ([]*emission.DelegatorEpochReward, error)
+	GetPendingValidatorRewards(ctx context.Context, nodeID ids.NodeID) (*emission.PendingRewards, error)
+	GetPendingDelegationRewards(ctx context.Context, nodeID ids.NodeID, owner codec.Address) (*emission.PendingRewards, error)
 	GetValidatorStakeFromState(ctx context.Context, nodeID ids.NodeID) (
 		bool, // exists
 		uint64, // StakeStartBlock
@@ -35,6 +49,8 @@ type Controller interface {
 		codec.Address, // OwnerAddress
 		error,
 	)
+	GetUnbondingFromState(ctx context.Context, owner codec.Address) ([]*storage.UnbondingEntry, error)
+	GetValidatorUnbondingFromState(ctx context.Context, nodeID ids.NodeID) ([]*storage.ValidatorUnbondingEntry, error)
 	GetDelegatedUserStakeFromState(ctx context.Context, owner codec.Address, nodeID ids.NodeID) (
 		bool, // exists
 		uint64, // StakeStartBlock
@@ -9,9 +9,18 @@ var (
 	ErrTxNotFound    = errors.New("tx not found")
 	ErrAssetNotFound = errors.New("asset not found")
 
+	// nft
+	ErrNFTCollectionNotFound = errors.New("nft collection not found")
+	ErrNFTNotFound           = errors.New("nft not found")
+
 	// register_validator_stake
 	ErrValidatorStakeNotFound = errors.New("validator stake not found")
 
 	// delegate_user_stake
 	ErrUserStakeNotFound = errors.New("user stake not found")
+
+	// emission
+	ErrInvalidEpochRange      = errors.New("from epoch must not be after to epoch")
+	ErrEpochRangeTooLarge     = errors.New("epoch range too large")
+	ErrInvariantCheckDisabled = errors.New("invariant check is disabled")
 )
@@ -21,6 +21,7 @@ import (
 	"github.com/nuklai/nuklaivm/emission"
 	"github.com/nuklai/nuklaivm/genesis"
 	_ "github.com/nuklai/nuklaivm/registry" // ensure registry populated
+	"github.com/nuklai/nuklaivm/storage"
 )
 
 type JSONRPCClient struct {
@@ -88,12 +89,12 @@ func (cli *JSONRPCClient) Asset(
 	ctx context.Context,
 	asset ids.ID,
 	useCache bool,
-) (bool, []byte, uint8, []byte, uint64, string, bool, error) {
+) (bool, []byte, uint8, []byte, uint64, uint64, string, bool, bool, error) {
 	cli.assetsL.Lock()
 	r, ok := cli.assets[asset]
 	cli.assetsL.Unlock()
 	if ok && useCache {
-		return true, r.Symbol, r.Decimals, r.Metadata, r.Supply, r.Owner, r.Warp, nil
+		return true, r.Symbol, r.Decimals, r.Metadata, r.Supply, r.MaxSupply, r.Owner, r.Frozen, r.Warp, nil
 	}
 	resp := new(AssetReply)
 	err := cli.requester.SendRequest(
@@ -108,14 +109,14 @@ func (cli *JSONRPCClient) Asset(
 	// We use string parsing here because the JSON-RPC library we use may not
 	// allows us to perform errors.Is.
 	case err != nil && strings.Contains(err.Error(), ErrAssetNotFound.Error()):
-		return false, nil, 0, nil, 0, "", false, nil
+		return false, nil, 0, nil, 0, 0, "", false, false, nil
 	case err != nil:
-		return false, nil, 0, nil, 0, "", false, err
+		return false, nil, 0, nil, 0, 0, "", false, false, err
 	}
 	cli.assetsL.Lock()
 	cli.assets[asset] = resp
 	cli.assetsL.Unlock()
-	return true, resp.Symbol, resp.Decimals, resp.Metadata, resp.Supply, resp.Owner, resp.Warp, nil
+	return true, resp.Symbol, resp.Decimals, resp.Metadata, resp.Supply, resp.MaxSupply, resp.Owner, resp.Frozen, resp.Warp, nil
 }
 
 func (cli *JSONRPCClient) Balance(ctx context.Context, addr string, asset ids.ID) (uint64, error) {
@@ -150,6 +151,101 @@ func (cli *JSONRPCClient) Loan(
 	return resp.Amount, err
 }
 
+func (cli *JSONRPCClient) Allowance(
+	ctx context.Context,
+	owner string,
+	spender string,
+	asset ids.ID,
+) (uint64, uint64, bool, error) {
+	resp := new(AllowanceReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"allowance",
+		&AllowanceArgs{
+			Owner:   owner,
+			Spender: spender,
+			Asset:   asset,
+		},
+		resp,
+	)
+	return resp.Amount, resp.ExpiryBlock, resp.Expired, err
+}
+
+func (cli *JSONRPCClient) NFTCollection(
+	ctx context.Context,
+	collection ids.ID,
+) (bool, []byte, []byte, uint64, string, error) {
+	resp := new(NFTCollectionReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"nftCollection",
+		&NFTCollectionArgs{
+			Collection: collection,
+		},
+		resp,
+	)
+	switch {
+	// We use string parsing here because the JSON-RPC library we use may not
+	// allows us to perform errors.Is.
+	case err != nil && strings.Contains(err.Error(), ErrNFTCollectionNotFound.Error()):
+		return false, nil, nil, 0, "", nil
+	case err != nil:
+		return false, nil, nil, 0, "", err
+	}
+	return true, resp.Symbol, resp.Metadata, resp.Supply, resp.Owner, nil
+}
+
+func (cli *JSONRPCClient) NFT(
+	ctx context.Context,
+	collection ids.ID,
+	id uint64,
+) (bool, string, []byte, error) {
+	resp := new(NFTReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"nft",
+		&NFTArgs{
+			Collection: collection,
+			ID:         id,
+		},
+		resp,
+	)
+	switch {
+	// We use string parsing here because the JSON-RPC library we use may not
+	// allows us to perform errors.Is.
+	case err != nil && strings.Contains(err.Error(), ErrNFTNotFound.Error()):
+		return false, "", nil, nil
+	case err != nil:
+		return false, "", nil, err
+	}
+	return true, resp.Owner, resp.Metadata, nil
+}
+
+// NFTsOfOwner returns a page of up to [limit] NFTs held by [addr] starting at
+// [start], and the start of the next page (nil on the last page).
+func (cli *JSONRPCClient) NFTsOfOwner(
+	ctx context.Context,
+	addr string,
+	start *storage.OwnedNFT,
+	limit int,
+) ([]*storage.OwnedNFT, *storage.OwnedNFT, error) {
+	resp := new(NFTsOfOwnerReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"nftsOfOwner",
+		&NFTsOfOwnerArgs{
+			Address: addr,
+			Start:   start,
+			Limit:   limit,
+		},
+		resp,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.NFTs, resp.Next, nil
+}
+
 func (cli *JSONRPCClient) EmissionInfo(ctx context.Context) (uint64, uint64, uint64, uint64, uint64, emission.EmissionAccount, emission.EpochTracker, error) {
 	resp := new(EmissionReply)
 	err := cli.requester.SendRequest(
@@ -193,23 +289,26 @@ func (cli *JSONRPCClient) StakedValidators(ctx context.Context) ([]*emission.Val
 	return resp.Validators, err
 }
 
-func (cli *JSONRPCClient) ValidatorStake(ctx context.Context, nodeID ids.NodeID) (uint64, uint64, uint64, uint64, codec.Address, codec.Address, error) {
+// ValidatorStake returns the stake of [nodeID]. [owner] is only used to find
+// the unbonding stakes once the stake has been withdrawn and may be left empty.
+func (cli *JSONRPCClient) ValidatorStake(ctx context.Context, nodeID ids.NodeID, owner codec.Address) (uint64, uint64, uint64, uint64, codec.Address, codec.Address, uint64, uint64, []*storage.UnbondingEntry, error) {
 	resp := new(ValidatorStakeReply)
 	err := cli.requester.SendRequest(
 		ctx,
 		"validatorStake",
 		&ValidatorStakeArgs{
 			NodeID: nodeID,
+			Owner:  owner,
 		},
 		resp,
 	)
 	if err != nil {
-		return 0, 0, 0, 0, codec.EmptyAddress, codec.EmptyAddress, err
+		return 0, 0, 0, 0, codec.EmptyAddress, codec.EmptyAddress, 0, 0, nil, err
 	}
-	return resp.StakeStartBlock, resp.StakeEndBlock, resp.StakedAmount, resp.DelegationFeeRate, resp.RewardAddress, resp.OwnerAddress, err
+	return resp.StakeStartBlock, resp.StakeEndBlock, resp.StakedAmount, resp.DelegationFeeRate, resp.RewardAddress, resp.OwnerAddress, resp.LockupMultiplier, resp.DelegationCapacity, resp.Unbonding, err
 }
 
-func (cli *JSONRPCClient) UserStake(ctx context.Context, owner codec.Address, nodeID ids.NodeID) (uint64, uint64, uint64, codec.Address, codec.Address, error) {
+func (cli *JSONRPCClient) UserStake(ctx context.Context, owner codec.Address, nodeID ids.NodeID) (uint64, uint64, uint64, codec.Address, codec.Address, uint64, []*storage.UnbondingEntry, error) {
 	resp := new(UserStakeReply)
 	err := cli.requester.SendRequest(
 		ctx,
@@ -221,9 +320,108 @@ func (cli *JSONRPCClient) UserStake(ctx context.Context, owner codec.Address, no
 		resp,
 	)
 	if err != nil {
-		return 0, 0, 0, codec.EmptyAddress, codec.EmptyAddress, err
+		return 0, 0, 0, codec.EmptyAddress, codec.EmptyAddress, 0, nil, err
+	}
+	return resp.StakeStartBlock, resp.StakeEndBlock, resp.StakedAmount, resp.RewardAddress, resp.OwnerAddress, resp.LockupMultiplier, resp.Unbonding, err
+}
+
+func (cli *JSONRPCClient) SlashHistory(ctx context.Context, nodeID ids.NodeID) ([]*emission.SlashEvent, error) {
+	resp := new(SlashHistoryReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"slashHistory",
+		&SlashHistoryArgs{
+			NodeID: nodeID,
+		},
+		resp,
+	)
+	if err != nil {
+		return []*emission.SlashEvent{}, err
+	}
+	return resp.Slashes, err
+}
+
+func (cli *JSONRPCClient) RewardHistory(ctx context.Context, nodeID ids.NodeID, fromEpoch, toEpoch uint64) ([]*emission.EpochReward, error) {
+	resp := new(RewardHistoryReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"rewardHistory",
+		&RewardHistoryArgs{
+			NodeID:    nodeID,
+			FromEpoch: fromEpoch,
+			ToEpoch:   toEpoch,
+		},
+		resp,
+	)
+	if err != nil {
+		return []*emission.EpochReward{}, err
+	}
+	return resp.Rewards, err
+}
+
+func (cli *JSONRPCClient) DelegatorRewardHistory(ctx context.Context, owner codec.Address, fromEpoch, toEpoch uint64) ([]*emission.DelegatorEpochReward, error) {
+	resp := new(DelegatorRewardHistoryReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"delegatorRewardHistory",
+		&DelegatorRewardHistoryArgs{
+			Owner:     owner,
+			FromEpoch: fromEpoch,
+			ToEpoch:   toEpoch,
+		},
+		resp,
+	)
+	if err != nil {
+		return []*emission.DelegatorEpochReward{}, err
+	}
+	return resp.Rewards, err
+}
+
+func (cli *JSONRPCClient) PendingValidatorRewards(ctx context.Context, nodeID ids.NodeID) (uint64, uint64, []*emission.PendingEpochReward, error) {
+	resp := new(PendingRewardsReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"pendingValidatorRewards",
+		&PendingValidatorRewardsArgs{
+			NodeID: nodeID,
+		},
+		resp,
+	)
+	if err != nil {
+		return 0, 0, []*emission.PendingEpochReward{}, err
+	}
+	return resp.TotalReward, resp.ClaimableHeight, resp.EpochRewards, err
+}
+
+func (cli *JSONRPCClient) PendingDelegationRewards(ctx context.Context, nodeID ids.NodeID, owner codec.Address) (uint64, uint64, []*emission.PendingEpochReward, error) {
+	resp := new(PendingRewardsReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"pendingDelegationRewards",
+		&PendingDelegationRewardsArgs{
+			NodeID: nodeID,
+			Owner:  owner,
+		},
+		resp,
+	)
+	if err != nil {
+		return 0, 0, []*emission.PendingEpochReward{}, err
+	}
+	return resp.TotalReward, resp.ClaimableHeight, resp.EpochRewards, err
+}
+
+func (cli *JSONRPCClient) InvariantCheck(ctx context.Context) (*InvariantCheckReply, error) {
+	resp := new(InvariantCheckReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"invariantCheck",
+		nil,
+		resp,
+	)
+	if err != nil {
+		return nil, err
 	}
-	return resp.StakeStartBlock, resp.StakeEndBlock, resp.StakedAmount, resp.RewardAddress, resp.OwnerAddress, err
+	return resp, err
 }
 
 func (cli *JSONRPCClient) WaitForBalance(
@@ -232,7 +430,7 @@ func (cli *JSONRPCClient) WaitForBalance(
 	asset ids.ID,
 	min uint64,
 ) error {
-	exists, symbol, decimals, _, _, _, _, err := cli.Asset(ctx, asset, true)
+	exists, symbol, decimals, _, _, _, _, _, _, err := cli.Asset(ctx, asset, true)
 	if err != nil {
 		return err
 	}
@@ -4,6 +4,9 @@
 package rpc
 
 import (
+	"context"
+	"fmt"
+	"math"
 	"net/http"
 
 	"github.com/ava-labs/avalanchego/ids"
@@ -14,6 +17,7 @@ import (
 	nconsts "github.com/nuklai/nuklaivm/consts"
 	"github.com/nuklai/nuklaivm/emission"
 	"github.com/nuklai/nuklaivm/genesis"
+	"github.com/nuklai/nuklaivm/storage"
 )
 
 type JSONRPCServer struct {
@@ -67,19 +71,21 @@ type AssetArgs struct {
 }
 
 type AssetReply struct {
-	Symbol   []byte `json:"symbol"`
-	Decimals uint8  `json:"decimals"`
-	Metadata []byte `json:"metadata"`
-	Supply   uint64 `json:"supply"`
-	Owner    string `json:"owner"`
-	Warp     bool   `json:"warp"`
+	Symbol    []byte `json:"symbol"`
+	Decimals  uint8  `json:"decimals"`
+	Metadata  []byte `json:"metadata"`
+	Supply    uint64 `json:"supply"`
+	MaxSupply uint64 `json:"maxSupply"`
+	Owner     string `json:"owner"`
+	Frozen    bool   `json:"frozen"`
+	Warp      bool   `json:"warp"`
 }
 
 func (j *JSONRPCServer) Asset(req *http.Request, args *AssetArgs, reply *AssetReply) error {
 	ctx, span := j.c.Tracer().Start(req.Context(), "Server.Asset")
 	defer span.End()
 
-	exists, symbol, decimals, metadata, supply, owner, warp, err := j.c.GetAssetFromState(ctx, args.Asset)
+	exists, symbol, decimals, metadata, supply, maxSupply, owner, frozen, warp, err := j.c.GetAssetFromState(ctx, args.Asset)
 	if err != nil {
 		return err
 	}
@@ -90,7 +96,9 @@ func (j *JSONRPCServer) Asset(req *http.Request, args *AssetArgs, reply *AssetRe
 	reply.Decimals = decimals
 	reply.Metadata = metadata
 	reply.Supply = supply
+	reply.MaxSupply = maxSupply
 	reply.Owner = codec.MustAddressBech32(nconsts.HRP, owner)
+	reply.Frozen = frozen
 	reply.Warp = warp
 	return err
 }
@@ -141,6 +149,134 @@ func (j *JSONRPCServer) Loan(req *http.Request, args *LoanArgs, reply *LoanReply
 	return nil
 }
 
+type AllowanceArgs struct {
+	Owner   string `json:"owner"`
+	Spender string `json:"spender"`
+	Asset   ids.ID `json:"asset"`
+}
+
+type AllowanceReply struct {
+	Amount      uint64 `json:"amount"`
+	ExpiryBlock uint64 `json:"expiryBlock"`
+	Expired     bool   `json:"expired"`
+}
+
+func (j *JSONRPCServer) Allowance(req *http.Request, args *AllowanceArgs, reply *AllowanceReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.Allowance")
+	defer span.End()
+
+	owner, err := codec.ParseAddressBech32(nconsts.HRP, args.Owner)
+	if err != nil {
+		return err
+	}
+	spender, err := codec.ParseAddressBech32(nconsts.HRP, args.Spender)
+	if err != nil {
+		return err
+	}
+	amount, expiryBlock, expired, err := j.c.GetAllowanceFromState(ctx, owner, spender, args.Asset)
+	if err != nil {
+		return err
+	}
+	reply.Amount = amount
+	reply.ExpiryBlock = expiryBlock
+	reply.Expired = expired
+	return nil
+}
+
+type NFTCollectionArgs struct {
+	Collection ids.ID `json:"collection"`
+}
+
+type NFTCollectionReply struct {
+	Symbol   []byte `json:"symbol"`
+	Metadata []byte `json:"metadata"`
+	Supply   uint64 `json:"supply"`
+	Owner    string `json:"owner"`
+}
+
+// The JSON-RPC codec only capitalizes the first letter of the requested method,
+// so the NFT methods are spelled Nft to be reachable as nftCollection, nft and
+// nftsOfOwner.
+func (j *JSONRPCServer) NftCollection(req *http.Request, args *NFTCollectionArgs, reply *NFTCollectionReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.NftCollection")
+	defer span.End()
+
+	exists, symbol, metadata, supply, owner, err := j.c.GetNFTCollectionFromState(ctx, args.Collection)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrNFTCollectionNotFound
+	}
+	reply.Symbol = symbol
+	reply.Metadata = metadata
+	reply.Supply = supply
+	reply.Owner = codec.MustAddressBech32(nconsts.HRP, owner)
+	return nil
+}
+
+type NFTArgs struct {
+	Collection ids.ID `json:"collection"`
+	ID         uint64 `json:"id"`
+}
+
+type NFTReply struct {
+	Owner    string `json:"owner"`
+	Metadata []byte `json:"metadata"`
+}
+
+func (j *JSONRPCServer) Nft(req *http.Request, args *NFTArgs, reply *NFTReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.Nft")
+	defer span.End()
+
+	exists, owner, metadata, err := j.c.GetNFTFromState(ctx, args.Collection, args.ID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrNFTNotFound
+	}
+	reply.Owner = codec.MustAddressBech32(nconsts.HRP, owner)
+	reply.Metadata = metadata
+	return nil
+}
+
+// MaxNFTsOfOwnerLimit is the maximum number of NFTs returned by a single
+// nftsOfOwner call.
+const MaxNFTsOfOwnerLimit = 256
+
+type NFTsOfOwnerArgs struct {
+	Address string            `json:"address"`
+	Start   *storage.OwnedNFT `json:"start"` // NFT to start the page at, the first one if omitted
+	Limit   int               `json:"limit"` // Defaults to, and is capped at, MaxNFTsOfOwnerLimit
+}
+
+type NFTsOfOwnerReply struct {
+	NFTs []*storage.OwnedNFT `json:"nfts"`
+	Next *storage.OwnedNFT   `json:"next"` // Start of the next page, omitted on the last page
+}
+
+func (j *JSONRPCServer) NftsOfOwner(req *http.Request, args *NFTsOfOwnerArgs, reply *NFTsOfOwnerReply) error {
+	_, span := j.c.Tracer().Start(req.Context(), "Server.NftsOfOwner")
+	defer span.End()
+
+	addr, err := codec.ParseAddressBech32(nconsts.HRP, args.Address)
+	if err != nil {
+		return err
+	}
+	limit := args.Limit
+	if limit <= 0 || limit > MaxNFTsOfOwnerLimit {
+		limit = MaxNFTsOfOwnerLimit
+	}
+	nfts, next, err := j.c.GetNFTsOfOwner(addr, args.Start, limit)
+	if err != nil {
+		return err
+	}
+	reply.NFTs = nfts
+	reply.Next = next
+	return nil
+}
+
 type EmissionReply struct {
 	CurrentBlockHeight uint64                   `json:"currentBlockHeight"`
 	TotalSupply        uint64                   `json:"totalSupply"`
@@ -152,10 +288,10 @@ type EmissionReply struct {
 }
 
 func (j *JSONRPCServer) EmissionInfo(req *http.Request, _ *struct{}, reply *EmissionReply) (err error) {
-	_, span := j.c.Tracer().Start(req.Context(), "Server.EmissionInfo")
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.EmissionInfo")
 	defer span.End()
 
-	currentBlockHeight, totalSupply, maxSupply, totalStaked, rewardsPerEpoch, emissionAccount, epochTracker, err := j.c.GetEmissionInfo()
+	currentBlockHeight, totalSupply, maxSupply, totalStaked, rewardsPerEpoch, emissionAccount, epochTracker, err := j.c.GetEmissionInfo(ctx)
 	if err != nil {
 		return err
 	}
@@ -198,16 +334,20 @@ func (j *JSONRPCServer) StakedValidators(req *http.Request, _ *struct{}, reply *
 }
 
 type ValidatorStakeArgs struct {
-	NodeID ids.NodeID `json:"nodeID"`
+	NodeID ids.NodeID    `json:"nodeID"`
+	Owner  codec.Address `json:"owner"` // Owner to show unbonding stakes for once the stake is withdrawn
 }
 
 type ValidatorStakeReply struct {
-	StakeStartBlock   uint64        `json:"stakeStartBlock"`   // Start block of the stake
-	StakeEndBlock     uint64        `json:"stakeEndBlock"`     // End block of the stake
-	StakedAmount      uint64        `json:"stakedAmount"`      // Amount of NAI staked
-	DelegationFeeRate uint64        `json:"delegationFeeRate"` // Delegation fee rate
-	RewardAddress     codec.Address `json:"rewardAddress"`     // Address to receive rewards
-	OwnerAddress      codec.Address `json:"ownerAddress"`      // Address of the owner who registered the validator
+	StakeStartBlock    uint64                    `json:"stakeStartBlock"`    // Start block of the stake
+	StakeEndBlock      uint64                    `json:"stakeEndBlock"`      // End block of the stake
+	StakedAmount       uint64                    `json:"stakedAmount"`       // Amount of NAI staked
+	DelegationFeeRate  uint64                    `json:"delegationFeeRate"`  // Delegation fee rate
+	RewardAddress      codec.Address             `json:"rewardAddress"`      // Address to receive rewards
+	OwnerAddress       codec.Address             `json:"ownerAddress"`       // Address of the owner who registered the validator
+	LockupMultiplier   uint64                    `json:"lockupMultiplier"`   // Reward weight multiplier of the stake, in basis points
+	DelegationCapacity uint64                    `json:"delegationCapacity"` // Stake that can still be delegated to the validator
+	Unbonding          []*storage.UnbondingEntry `json:"unbonding"`          // Withdrawn stakes of the owner on the validator that are unbonding
 }
 
 func (j *JSONRPCServer) ValidatorStake(req *http.Request, args *ValidatorStakeArgs, reply *ValidatorStakeReply) (err error) {
@@ -219,15 +359,30 @@ func (j *JSONRPCServer) ValidatorStake(req *http.Request, args *ValidatorStakeAr
 		return err
 	}
 	if !exists {
+		ownerAddress = args.Owner
+	}
+	unbonding, err := j.getValidatorUnbonding(ctx, ownerAddress, args.NodeID)
+	if err != nil {
+		return err
+	}
+	if !exists && len(unbonding) == 0 {
 		return ErrValidatorStakeNotFound
 	}
 
+	reply.Unbonding = unbonding
 	reply.StakeStartBlock = stakeStartBlock
 	reply.StakeEndBlock = stakeEndBlock
 	reply.StakedAmount = stakedAmount
 	reply.DelegationFeeRate = delegationFeeRate
 	reply.RewardAddress = rewardAddress
 	reply.OwnerAddress = ownerAddress
+	if exists {
+		reply.LockupMultiplier = j.c.GetLockupMultiplier(stakeStartBlock, stakeEndBlock, false)
+		reply.DelegationCapacity, err = j.c.GetDelegationCapacity(ctx, args.NodeID)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -237,11 +392,13 @@ type UserStakeArgs struct {
 }
 
 type UserStakeReply struct {
-	StakeStartBlock uint64        `json:"stakeStartBlock"` // Start block of the stake
-	StakeEndBlock   uint64        `json:"stakeEndBlock"`   // End block of the stake
-	StakedAmount    uint64        `json:"stakedAmount"`    // Amount of NAI staked
-	RewardAddress   codec.Address `json:"rewardAddress"`   // Address to receive rewards
-	OwnerAddress    codec.Address `json:"ownerAddress"`    // Address of the owner who delegated
+	StakeStartBlock  uint64                    `json:"stakeStartBlock"`  // Start block of the stake
+	StakeEndBlock    uint64                    `json:"stakeEndBlock"`    // End block of the stake
+	StakedAmount     uint64                    `json:"stakedAmount"`     // Amount of NAI staked
+	RewardAddress    codec.Address             `json:"rewardAddress"`    // Address to receive rewards
+	OwnerAddress     codec.Address             `json:"ownerAddress"`     // Address of the owner who delegated
+	LockupMultiplier uint64                    `json:"lockupMultiplier"` // Reward weight multiplier of the stake, in basis points
+	Unbonding        []*storage.UnbondingEntry `json:"unbonding"`        // Undelegated stakes of the owner on the validator that are unbonding
 }
 
 func (j *JSONRPCServer) UserStake(req *http.Request, args *UserStakeArgs, reply *UserStakeReply) (err error) {
@@ -252,14 +409,238 @@ func (j *JSONRPCServer) UserStake(req *http.Request, args *UserStakeArgs, reply
 	if err != nil {
 		return err
 	}
-	if !exists {
+	unbonding, err := j.getUnbonding(ctx, args.Owner, args.NodeID)
+	if err != nil {
+		return err
+	}
+	if !exists && len(unbonding) == 0 {
 		return ErrUserStakeNotFound
 	}
 
+	reply.Unbonding = unbonding
+
 	reply.StakeStartBlock = stakeStartBlock
 	reply.StakeEndBlock = stakeEndBlock
 	reply.StakedAmount = stakedAmount
 	reply.RewardAddress = rewardAddress
 	reply.OwnerAddress = ownerAddress
+	if exists {
+		reply.LockupMultiplier = j.c.GetLockupMultiplier(stakeStartBlock, stakeEndBlock, true)
+	}
+	return nil
+}
+
+// getUnbonding returns the stakes of [owner] on [nodeID] that are unbonding.
+func (j *JSONRPCServer) getUnbonding(ctx context.Context, owner codec.Address, nodeID ids.NodeID) ([]*storage.UnbondingEntry, error) {
+	if owner == codec.EmptyAddress {
+		return nil, nil
+	}
+	entries, err := j.c.GetUnbondingFromState(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	unbonding := make([]*storage.UnbondingEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.NodeID == nodeID {
+			unbonding = append(unbonding, entry)
+		}
+	}
+	return unbonding, nil
+}
+
+// getValidatorUnbonding returns the stakes [owner] took off [nodeID] that are
+// unbonding.
+func (j *JSONRPCServer) getValidatorUnbonding(ctx context.Context, owner codec.Address, nodeID ids.NodeID) ([]*storage.UnbondingEntry, error) {
+	if owner == codec.EmptyAddress {
+		return nil, nil
+	}
+	entries, err := j.c.GetValidatorUnbondingFromState(ctx, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	unbonding := make([]*storage.UnbondingEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Owner == owner {
+			unbonding = append(unbonding, &storage.UnbondingEntry{
+				NodeID:       nodeID,
+				Amount:       entry.Amount,
+				ReleaseBlock: entry.ReleaseBlock,
+			})
+		}
+	}
+	return unbonding, nil
+}
+
+type SlashHistoryArgs struct {
+	NodeID ids.NodeID `json:"nodeID"` // Leave empty for the history of all validators
+}
+
+type SlashHistoryReply struct {
+	Slashes []*emission.SlashEvent `json:"slashes"`
+}
+
+func (j *JSONRPCServer) SlashHistory(req *http.Request, args *SlashHistoryArgs, reply *SlashHistoryReply) (err error) {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.SlashHistory")
+	defer span.End()
+
+	slashes, err := j.c.GetSlashHistory(ctx, args.NodeID)
+	if err != nil {
+		return err
+	}
+	reply.Slashes = slashes
+	return nil
+}
+
+// MaxRewardHistoryEpochs is the maximum number of epochs a reward history
+// request can span.
+const MaxRewardHistoryEpochs = 1024
+
+type RewardHistoryArgs struct {
+	NodeID    ids.NodeID `json:"nodeID"`
+	FromEpoch uint64     `json:"fromEpoch"`
+	ToEpoch   uint64     `json:"toEpoch"` // Leave at 0 for MaxRewardHistoryEpochs epochs from FromEpoch onwards
+}
+
+type RewardHistoryReply struct {
+	Rewards []*emission.EpochReward `json:"rewards"`
+}
+
+func (j *JSONRPCServer) RewardHistory(req *http.Request, args *RewardHistoryArgs, reply *RewardHistoryReply) (err error) {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.RewardHistory")
+	defer span.End()
+
+	toEpoch, err := rewardHistoryRange(args.FromEpoch, args.ToEpoch)
+	if err != nil {
+		return err
+	}
+	rewards, err := j.c.GetRewardHistory(ctx, args.NodeID, args.FromEpoch, toEpoch)
+	if err != nil {
+		return err
+	}
+	reply.Rewards = rewards
+	return nil
+}
+
+type DelegatorRewardHistoryArgs struct {
+	Owner     codec.Address `json:"owner"`
+	FromEpoch uint64        `json:"fromEpoch"`
+	ToEpoch   uint64        `json:"toEpoch"` // Leave at 0 for MaxRewardHistoryEpochs epochs from FromEpoch onwards
+}
+
+type DelegatorRewardHistoryReply struct {
+	Rewards []*emission.DelegatorEpochReward `json:"rewards"`
+}
+
+func (j *JSONRPCServer) DelegatorRewardHistory(req *http.Request, args *DelegatorRewardHistoryArgs, reply *DelegatorRewardHistoryReply) (err error) {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.DelegatorRewardHistory")
+	defer span.End()
+
+	toEpoch, err := rewardHistoryRange(args.FromEpoch, args.ToEpoch)
+	if err != nil {
+		return err
+	}
+	rewards, err := j.c.GetDelegatorRewardHistory(ctx, args.Owner, args.FromEpoch, toEpoch)
+	if err != nil {
+		return err
+	}
+	reply.Rewards = rewards
+	return nil
+}
+
+// rewardHistoryRange returns the last epoch of a reward history request from
+// [fromEpoch] to [toEpoch], which spans at most [MaxRewardHistoryEpochs].
+func rewardHistoryRange(fromEpoch, toEpoch uint64) (uint64, error) {
+	if toEpoch == 0 {
+		toEpoch = fromEpoch + min(MaxRewardHistoryEpochs-1, math.MaxUint64-fromEpoch)
+	}
+	if fromEpoch > toEpoch {
+		return 0, ErrInvalidEpochRange
+	}
+	if toEpoch-fromEpoch >= MaxRewardHistoryEpochs {
+		return 0, ErrEpochRangeTooLarge
+	}
+	return toEpoch, nil
+}
+
+type PendingValidatorRewardsArgs struct {
+	NodeID ids.NodeID `json:"nodeID"`
+}
+
+type PendingRewardsReply struct {
+	TotalReward     uint64                         `json:"totalReward"`
+	ClaimableHeight uint64                         `json:"claimableHeight"`
+	EpochRewards    []*emission.PendingEpochReward `json:"epochRewards"`
+}
+
+func (j *JSONRPCServer) PendingValidatorRewards(req *http.Request, args *PendingValidatorRewardsArgs, reply *PendingRewardsReply) (err error) {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.PendingValidatorRewards")
+	defer span.End()
+
+	pendingRewards, err := j.c.GetPendingValidatorRewards(ctx, args.NodeID)
+	if err != nil {
+		return err
+	}
+	reply.TotalReward = pendingRewards.TotalReward
+	reply.ClaimableHeight = pendingRewards.ClaimableHeight
+	reply.EpochRewards = pendingRewards.EpochRewards
+	return nil
+}
+
+type PendingDelegationRewardsArgs struct {
+	NodeID ids.NodeID    `json:"nodeID"`
+	Owner  codec.Address `json:"owner"`
+}
+
+func (j *JSONRPCServer) PendingDelegationRewards(req *http.Request, args *PendingDelegationRewardsArgs, reply *PendingRewardsReply) (err error) {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.PendingDelegationRewards")
+	defer span.End()
+
+	pendingRewards, err := j.c.GetPendingDelegationRewards(ctx, args.NodeID, args.Owner)
+	if err != nil {
+		return err
+	}
+	reply.TotalReward = pendingRewards.TotalReward
+	reply.ClaimableHeight = pendingRewards.ClaimableHeight
+	reply.EpochRewards = pendingRewards.EpochRewards
+	return nil
+}
+
+type InvariantCheckReply struct {
+	TotalSupply      uint64               `json:"totalSupply"`      // Total supply of NAI tracked by the emission balancer
+	NativeSupply     uint64               `json:"nativeSupply"`     // Supply of the NAI asset in state
+	Holdings         *storage.NAIHoldings `json:"holdings"`         // NAI held in state
+	UnclaimedRewards uint64               `json:"unclaimedRewards"` // Rewards and fees not paid out by the emission balancer yet
+	Discrepancies    []string             `json:"discrepancies"`
+}
+
+// InvariantCheck compares the supply of NAI tracked by the emission balancer
+// and stored in state with the NAI actually held. The supply in state must
+// match the NAI held in state, and the total supply must match it plus the
+// NAI held by the emission balancer. Discrepancies may be reported while a
+// block is being accepted. It walks all of state, so it is only served by
+// nodes that enable it in their config.
+func (j *JSONRPCServer) InvariantCheck(req *http.Request, _ *struct{}, reply *InvariantCheckReply) (err error) {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.InvariantCheck")
+	defer span.End()
+
+	if !j.c.InvariantCheckEnabled() {
+		return ErrInvariantCheckDisabled
+	}
+
+	totalSupply, nativeSupply, unclaimedRewards, holdings, err := j.c.GetSupplyInfo(ctx)
+	if err != nil {
+		return err
+	}
+	reply.TotalSupply = totalSupply
+	reply.NativeSupply = nativeSupply
+	reply.Holdings = holdings
+	reply.UnclaimedRewards = unclaimedRewards
+	reply.Discrepancies = []string{}
+	if held := holdings.Total(); nativeSupply != held {
+		reply.Discrepancies = append(reply.Discrepancies, fmt.Sprintf("native supply %d does not match the %d NAI held in state", nativeSupply, held))
+	}
+	if totalSupply != nativeSupply+unclaimedRewards {
+		reply.Discrepancies = append(reply.Discrepancies, fmt.Sprintf("total supply %d does not match native supply %d plus unclaimed rewards %d", totalSupply, nativeSupply, unclaimedRewards))
+	}
 	return nil
 }
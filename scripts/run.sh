@@ -189,6 +189,7 @@ cat <<EOF > "${TMPDIR}"/nuklaivm.config
   "transactionExecutionCores": 2,
   "verifyAuth":true,
   "storeTransactions": ${STORE_TXS},
+  "enableInvariantCheck": true,
   "streamingBacklogSize": 10000000,
   "logLevel": "${LOGLEVEL}",
   "continuousProfilerDir":"${TMPDIR}/nuklaivm-e2e-profiles/*",
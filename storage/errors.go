@@ -7,6 +7,15 @@ import "errors"
 
 var (
 	ErrInvalidBalance = errors.New("invalid balance")
+	ErrInvalidSupply  = errors.New("invalid supply")
+	ErrAssetMissing   = errors.New("asset missing")
+	ErrInvalidAsset   = errors.New("invalid asset record")
 	ErrInvalidStake   = errors.New("invalid stake")
 	ErrStakeNotFound  = errors.New("stake not found")
+
+	ErrUnbondingQueueFull = errors.New("unbonding queue full")
+	ErrInvalidUnbonding   = errors.New("invalid unbonding queue")
+
+	ErrEmissionMissing = errors.New("emission record missing")
+	ErrInvalidFeeShard = errors.New("invalid fee shard")
 )
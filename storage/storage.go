@@ -8,6 +8,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
 
 	"github.com/ava-labs/avalanchego/database"
@@ -26,6 +27,12 @@ type ReadState func(context.Context, [][]byte) ([][]byte, []error)
 // Metadata
 // 0x0/ (tx)
 //   -> [txID] => timestamp
+// 0x1/ (epoch rewards)
+//   -> [nodeID|epoch] => epochReward
+// 0x2/ (slash events)
+//   -> [nodeID|epoch] => slashEvent
+// 0x3/ (delegator rewards)
+//   -> [owner|epoch|nodeID] => delegatorEpochReward
 //
 // State
 // / (height) => store in root
@@ -42,18 +49,43 @@ type ReadState func(context.Context, [][]byte) ([][]byte, []error)
 // 0x5/ (hypersdk-outgoing warp)
 
 // 0x6/ (assets)
-//   -> [asset] => metadataLen|metadata|supply|owner|warp
+//   -> [asset] => version|symbolLen|symbol|decimals|metadataLen|metadata|supply|maxSupply|owner|frozen|warp
+//   -> [asset] => symbolLen|symbol|decimals|metadataLen|metadata|supply|owner|warp (legacy, read only)
 // 0x7/ (loans)
 //   -> [assetID|destination] => amount
 
 // 0x8/ (stake)
-//   -> [nodeID] => stakeStartBlock|stakeEndBlock|stakedAmount|delegationFeeRate|rewardAddress|ownerAddress
+//   -> [nodeID] => stakeStartBlock|stakeEndBlock|stakedAmount|delegationFeeRate|rewardAddress|ownerAddress|pendingDelegationFeeRate|feeRateEffectiveBlock
 // 0x9/ (delegate)
-//   -> [owner|nodeID] => stakeStartBlock|stakedAmount|rewardAddress|ownerAddress
+//   -> [owner|nodeID] => stakeStartBlock|stakeEndBlock|stakedAmount|rewardAddress|ownerAddress|rewardWeight|rewardIndex|rewardHeight
+// 0xa/ (unbonding)
+//   -> [owner] => numEntries|(nodeID|amount|releaseBlock)*
+
+// 0xb/ (nft collections)
+//   -> [collection] => symbolLen|symbol|metadataLen|metadata|supply|owner
+// 0xc/ (nfts)
+//   -> [collection|id] => owner|metadataLen|metadata
+// 0xd/ (nfts of owner)
+//   -> [owner|collection|id] => 0x1
+
+// 0xe/ (allowances)
+//   -> [owner|spender|asset] => amount|expiryBlock
+
+// 0xf/ (emission)
+//   -> [] => version|emissionHeader
+// 0x10/ (fee shards)
+//   -> [shard] => collected|pending
+// 0x11/ (emission validators)
+//   -> [nodeID] => version|validatorRecord
+// 0x12/ (emission active set)
+//   -> [] => epoch|numEntries|(nodeID|rankStake|stake|weight)*
 
 const (
 	// metaDB
-	txPrefix = 0x0
+	txPrefix              = 0x0
+	epochRewardPrefix     = 0x1
+	slashEventPrefix      = 0x2
+	delegatorRewardPrefix = 0x3
 
 	// stateDB
 	balancePrefix = 0x0
@@ -70,6 +102,20 @@ const (
 
 	registerValidatorStakePrefix = 0x8
 	delegateUserStakePrefix      = 0x9
+	unbondingPrefix              = 0xa
+
+	nftCollectionPrefix = 0xb
+	nftPrefix           = 0xc
+	nftOwnerPrefix      = 0xd
+
+	allowancePrefix = 0xe
+
+	emissionPrefix          = 0xf
+	feeShardPrefix          = 0x10
+	validatorRecordPrefix   = 0x11
+	emissionActiveSetPrefix = 0x12
+
+	validatorUnbondingPrefix = 0x13
 )
 
 const (
@@ -78,6 +124,39 @@ const (
 	LoanChunks                   uint16 = 1
 	RegisterValidatorStakeChunks uint16 = 5
 	DelegateUserStakeChunks      uint16 = 3
+	UnbondingChunks              uint16 = 13
+	NFTCollectionChunks          uint16 = 5
+	NFTChunks                    uint16 = 5
+	NFTOwnerChunks               uint16 = 1
+	AllowanceChunks              uint16 = 1
+	EmissionChunks               uint16 = 22
+	FeeShardChunks               uint16 = 1
+	ValidatorRecordChunks        uint16 = 4
+	ActiveSetChunks              uint16 = 89
+	ValidatorUnbondingChunks     uint16 = 15
+)
+
+// MaxUnbondingEntries is the maximum number of stakes an address, or a
+// validator, can have unbonding at the same time.
+const MaxUnbondingEntries = 16
+
+const (
+	unbondingEntryLen          = hconsts.NodeIDLen + 2*hconsts.Uint64Len
+	validatorUnbondingEntryLen = codec.AddressLen + 3*hconsts.Uint64Len
+)
+
+// NumFeeShards is the number of keys transaction fees are collected in. Every
+// sponsor pays its fees into one of them, so that transactions paid for by
+// different sponsors rarely conflict on the same key.
+const NumFeeShards = 16
+
+// assetVersion is the first byte of the asset records written since max
+// supplies were introduced. Records written before then start with the high
+// byte of the symbol length instead, which is always 0x0 since symbols are
+// shorter than 256 bytes, so they are still decoded with the legacy layout.
+const (
+	legacyAssetVersion = byte(0x0)
+	assetVersion       = byte(0x1)
 )
 
 var (
@@ -150,6 +229,174 @@ func GetTransaction(
 	return true, t, success, d, fee, nil
 }
 
+// [epochRewardPrefix] + [nodeID] + [epoch]
+func EpochRewardKey(nodeID ids.NodeID, epoch uint64) (k []byte) {
+	k = make([]byte, 1+hconsts.NodeIDLen+hconsts.Uint64Len)
+	k[0] = epochRewardPrefix
+	copy(k[1:], nodeID.Bytes())
+	binary.BigEndian.PutUint64(k[1+hconsts.NodeIDLen:], epoch)
+	return
+}
+
+// StoreEpochReward persists the serialized reward ledger entry of a validator
+// for an epoch.
+func StoreEpochReward(
+	_ context.Context,
+	db database.KeyValueWriter,
+	nodeID ids.NodeID,
+	epoch uint64,
+	epochReward []byte,
+) error {
+	return db.Put(EpochRewardKey(nodeID, epoch), epochReward)
+}
+
+func GetEpochReward(
+	_ context.Context,
+	db database.KeyValueReader,
+	nodeID ids.NodeID,
+	epoch uint64,
+) (bool, []byte, error) {
+	v, err := db.Get(EpochRewardKey(nodeID, epoch))
+	if errors.Is(err, database.ErrNotFound) {
+		return false, nil, nil
+	}
+	if err != nil {
+		return false, nil, err
+	}
+	return true, v, nil
+}
+
+// GetEpochRewards returns the serialized reward ledger entries of a validator
+// from [fromEpoch] to [toEpoch], both included, oldest first.
+func GetEpochRewards(
+	_ context.Context,
+	db database.Iteratee,
+	nodeID ids.NodeID,
+	fromEpoch uint64,
+	toEpoch uint64,
+) ([][]byte, error) {
+	prefix := EpochRewardKey(nodeID, 0)[:1+hconsts.NodeIDLen]
+	iter := db.NewIteratorWithStartAndPrefix(EpochRewardKey(nodeID, fromEpoch), prefix)
+	defer iter.Release()
+
+	epochRewards := [][]byte{}
+	for iter.Next() {
+		epoch := binary.BigEndian.Uint64(iter.Key()[1+hconsts.NodeIDLen:])
+		if epoch > toEpoch {
+			break
+		}
+		epochRewards = append(epochRewards, slices.Clone(iter.Value()))
+	}
+	return epochRewards, iter.Error()
+}
+
+// [slashEventPrefix] + [nodeID] + [epoch]
+func SlashEventKey(nodeID ids.NodeID, epoch uint64) (k []byte) {
+	k = make([]byte, 1+hconsts.NodeIDLen+hconsts.Uint64Len)
+	k[0] = slashEventPrefix
+	copy(k[1:], nodeID.Bytes())
+	binary.BigEndian.PutUint64(k[1+hconsts.NodeIDLen:], epoch)
+	return
+}
+
+// StoreSlashEvent persists the serialized uptime offence of a validator in an
+// epoch.
+func StoreSlashEvent(
+	_ context.Context,
+	db database.KeyValueWriter,
+	nodeID ids.NodeID,
+	epoch uint64,
+	slashEvent []byte,
+) error {
+	return db.Put(SlashEventKey(nodeID, epoch), slashEvent)
+}
+
+// GetSlashEvents returns the serialized uptime offences of a validator, or of
+// every validator if [nodeID] is empty, ordered by validator and then by epoch.
+func GetSlashEvents(
+	_ context.Context,
+	db database.Iteratee,
+	nodeID ids.NodeID,
+) ([][]byte, error) {
+	prefix := []byte{slashEventPrefix}
+	if nodeID != ids.EmptyNodeID {
+		prefix = SlashEventKey(nodeID, 0)[:1+hconsts.NodeIDLen]
+	}
+	iter := db.NewIteratorWithPrefix(prefix)
+	defer iter.Release()
+
+	slashEvents := [][]byte{}
+	for iter.Next() {
+		slashEvents = append(slashEvents, slices.Clone(iter.Value()))
+	}
+	return slashEvents, iter.Error()
+}
+
+// [delegatorRewardPrefix] + [owner] + [epoch] + [nodeID]
+func DelegatorRewardKey(owner codec.Address, epoch uint64, nodeID ids.NodeID) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen+hconsts.Uint64Len+hconsts.NodeIDLen)
+	k[0] = delegatorRewardPrefix
+	copy(k[1:], owner[:])
+	binary.BigEndian.PutUint64(k[1+codec.AddressLen:], epoch)
+	copy(k[1+codec.AddressLen+hconsts.Uint64Len:], nodeID.Bytes())
+	return
+}
+
+// StoreDelegatorReward persists the serialized rewards paid out to the
+// delegation of [owner] to [nodeID] in an epoch.
+func StoreDelegatorReward(
+	_ context.Context,
+	db database.KeyValueWriter,
+	owner codec.Address,
+	epoch uint64,
+	nodeID ids.NodeID,
+	delegatorReward []byte,
+) error {
+	return db.Put(DelegatorRewardKey(owner, epoch, nodeID), delegatorReward)
+}
+
+func GetDelegatorReward(
+	_ context.Context,
+	db database.KeyValueReader,
+	owner codec.Address,
+	epoch uint64,
+	nodeID ids.NodeID,
+) (bool, []byte, error) {
+	v, err := db.Get(DelegatorRewardKey(owner, epoch, nodeID))
+	if errors.Is(err, database.ErrNotFound) {
+		return false, nil, nil
+	}
+	if err != nil {
+		return false, nil, err
+	}
+	return true, v, nil
+}
+
+// GetDelegatorRewards returns the serialized rewards paid out to the
+// delegations of [owner] from [fromEpoch] to [toEpoch], both included, oldest
+// first.
+func GetDelegatorRewards(
+	_ context.Context,
+	db database.Iteratee,
+	owner codec.Address,
+	fromEpoch uint64,
+	toEpoch uint64,
+) ([][]byte, error) {
+	prefix := DelegatorRewardKey(owner, 0, ids.EmptyNodeID)[:1+codec.AddressLen]
+	iter := db.NewIteratorWithStartAndPrefix(DelegatorRewardKey(owner, fromEpoch, ids.EmptyNodeID), prefix)
+	defer iter.Release()
+
+	delegatorRewards := [][]byte{}
+	for iter.Next() {
+		epoch := binary.BigEndian.Uint64(iter.Key()[1+codec.AddressLen:])
+		if epoch > toEpoch {
+			break
+		}
+		delegatorRewards = append(delegatorRewards, slices.Clone(iter.Value()))
+	}
+	return delegatorRewards, iter.Error()
+}
+
 // [accountPrefix] + [address] + [asset]
 func BalanceKey(addr codec.Address, asset ids.ID) (k []byte) {
 	k = balanceKeyPool.Get().([]byte)
@@ -314,7 +561,7 @@ func GetAssetFromState(
 	ctx context.Context,
 	f ReadState,
 	asset ids.ID,
-) (bool, []byte, uint8, []byte, uint64, codec.Address, bool, error) {
+) (bool, []byte, uint8, []byte, uint64, uint64, codec.Address, bool, bool, error) {
 	values, errs := f(ctx, [][]byte{AssetKey(asset)})
 	return innerGetAsset(values[0], errs[0])
 }
@@ -323,7 +570,7 @@ func GetAsset(
 	ctx context.Context,
 	im state.Immutable,
 	asset ids.ID,
-) (bool, []byte, uint8, []byte, uint64, codec.Address, bool, error) {
+) (bool, []byte, uint8, []byte, uint64, uint64, codec.Address, bool, bool, error) {
 	k := AssetKey(asset)
 	return innerGetAsset(im.GetValue(ctx, k))
 }
@@ -331,25 +578,53 @@ func GetAsset(
 func innerGetAsset(
 	v []byte,
 	err error,
-) (bool, []byte, uint8, []byte, uint64, codec.Address, bool, error) {
+) (bool, []byte, uint8, []byte, uint64, uint64, codec.Address, bool, bool, error) {
 	if errors.Is(err, database.ErrNotFound) {
-		return false, nil, 0, nil, 0, codec.EmptyAddress, false, nil
+		return false, nil, 0, nil, 0, 0, codec.EmptyAddress, false, false, nil
 	}
 	if err != nil {
-		return false, nil, 0, nil, 0, codec.EmptyAddress, false, err
+		return false, nil, 0, nil, 0, 0, codec.EmptyAddress, false, false, err
+	}
+	if len(v) == 0 {
+		return false, nil, 0, nil, 0, 0, codec.EmptyAddress, false, false, ErrInvalidAsset
+	}
+	version := v[0]
+	switch version {
+	case legacyAssetVersion:
+	case assetVersion:
+		v = v[1:]
+	default:
+		return false, nil, 0, nil, 0, 0, codec.EmptyAddress, false, false, ErrInvalidAsset
 	}
 	symbolLen := binary.BigEndian.Uint16(v)
 	symbol := v[hconsts.Uint16Len : hconsts.Uint16Len+symbolLen]
 	decimals := v[hconsts.Uint16Len+symbolLen]
 	metadataLen := binary.BigEndian.Uint16(v[hconsts.Uint16Len+symbolLen+hconsts.Uint8Len:])
 	metadata := v[hconsts.Uint16Len+symbolLen+hconsts.Uint8Len+hconsts.Uint16Len : hconsts.Uint16Len+symbolLen+hconsts.Uint8Len+hconsts.Uint16Len+metadataLen]
-	supply := binary.BigEndian.Uint64(v[hconsts.Uint16Len+symbolLen+hconsts.Uint8Len+hconsts.Uint16Len+metadataLen:])
+	offset := hconsts.Uint16Len + symbolLen + hconsts.Uint8Len + hconsts.Uint16Len + metadataLen
+	supply := binary.BigEndian.Uint64(v[offset:])
+	offset += hconsts.Uint64Len
+
+	// Legacy records have no max supply and can't be frozen
+	var maxSupply uint64
+	if version == assetVersion {
+		maxSupply = binary.BigEndian.Uint64(v[offset:])
+		offset += hconsts.Uint64Len
+	}
 	var addr codec.Address
-	copy(addr[:], v[hconsts.Uint16Len+symbolLen+hconsts.Uint8Len+hconsts.Uint16Len+metadataLen+hconsts.Uint64Len:])
-	warp := v[hconsts.Uint16Len+symbolLen+hconsts.Uint8Len+hconsts.Uint16Len+metadataLen+hconsts.Uint64Len+codec.AddressLen] == 0x1
-	return true, symbol, decimals, metadata, supply, addr, warp, nil
+	copy(addr[:], v[offset:])
+	offset += codec.AddressLen
+	var frozen bool
+	if version == assetVersion {
+		frozen = v[offset] == 0x1
+		offset++
+	}
+	warp := v[offset] == 0x1
+	return true, symbol, decimals, metadata, supply, maxSupply, addr, frozen, warp, nil
 }
 
+// SetAsset stores the record of [asset]. A [maxSupply] of 0 means the supply
+// is only bounded by uint64 and [frozen] disables minting for good.
 func SetAsset(
 	ctx context.Context,
 	mu state.Mutable,
@@ -358,28 +633,210 @@ func SetAsset(
 	decimals uint8,
 	metadata []byte,
 	supply uint64,
+	maxSupply uint64,
 	owner codec.Address,
+	frozen bool,
 	warp bool,
 ) error {
 	k := AssetKey(asset)
 	symbolLen := len(symbol)
 	metadataLen := len(metadata)
-	v := make([]byte, hconsts.Uint16Len+symbolLen+hconsts.Uint8Len+hconsts.Uint16Len+metadataLen+hconsts.Uint64Len+codec.AddressLen+1)
-	binary.BigEndian.PutUint16(v, uint16(symbolLen))
-	copy(v[hconsts.Uint16Len:], symbol)
-	v[hconsts.Uint16Len+symbolLen] = decimals
-	binary.BigEndian.PutUint16(v[hconsts.Uint16Len+symbolLen+hconsts.Uint8Len:], uint16(metadataLen))
-	copy(v[hconsts.Uint16Len+symbolLen+hconsts.Uint8Len+hconsts.Uint16Len:], metadata)
-	binary.BigEndian.PutUint64(v[hconsts.Uint16Len+symbolLen+hconsts.Uint8Len+hconsts.Uint16Len+metadataLen:], supply)
-	copy(v[hconsts.Uint16Len+symbolLen+hconsts.Uint8Len+hconsts.Uint16Len+metadataLen+hconsts.Uint64Len:], owner[:])
+	v := make([]byte, 1+hconsts.Uint16Len+symbolLen+hconsts.Uint8Len+hconsts.Uint16Len+metadataLen+2*hconsts.Uint64Len+codec.AddressLen+2)
+	v[0] = assetVersion
+	binary.BigEndian.PutUint16(v[1:], uint16(symbolLen))
+	copy(v[1+hconsts.Uint16Len:], symbol)
+	v[1+hconsts.Uint16Len+symbolLen] = decimals
+	binary.BigEndian.PutUint16(v[1+hconsts.Uint16Len+symbolLen+hconsts.Uint8Len:], uint16(metadataLen))
+	copy(v[1+hconsts.Uint16Len+symbolLen+hconsts.Uint8Len+hconsts.Uint16Len:], metadata)
+	offset := 1 + hconsts.Uint16Len + symbolLen + hconsts.Uint8Len + hconsts.Uint16Len + metadataLen
+	binary.BigEndian.PutUint64(v[offset:], supply)
+	binary.BigEndian.PutUint64(v[offset+hconsts.Uint64Len:], maxSupply)
+	copy(v[offset+2*hconsts.Uint64Len:], owner[:])
+	f := byte(0x0)
+	if frozen {
+		f = 0x1
+	}
+	v[offset+2*hconsts.Uint64Len+codec.AddressLen] = f
 	b := byte(0x0)
 	if warp {
 		b = 0x1
 	}
-	v[hconsts.Uint16Len+symbolLen+hconsts.Uint8Len+hconsts.Uint16Len+metadataLen+hconsts.Uint64Len+codec.AddressLen] = b
+	v[offset+2*hconsts.Uint64Len+codec.AddressLen+1] = b
 	return mu.Insert(ctx, k, v)
 }
 
+// AddAssetSupply increases the supply of [asset] by [amount].
+func AddAssetSupply(
+	ctx context.Context,
+	mu state.Mutable,
+	asset ids.ID,
+	amount uint64,
+) error {
+	exists, symbol, decimals, metadata, supply, maxSupply, owner, frozen, warp, err := GetAsset(ctx, mu, asset)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrAssetMissing, asset)
+	}
+	nsupply, err := hmath.Add64(supply, amount)
+	if err != nil {
+		return fmt.Errorf("%w: could not add supply (asset=%s, supply=%d, amount=%d)", ErrInvalidSupply, asset, supply, amount)
+	}
+	return SetAsset(ctx, mu, asset, symbol, decimals, metadata, nsupply, maxSupply, owner, frozen, warp)
+}
+
+// SubAssetSupply decreases the supply of [asset] by [amount].
+func SubAssetSupply(
+	ctx context.Context,
+	mu state.Mutable,
+	asset ids.ID,
+	amount uint64,
+) error {
+	exists, symbol, decimals, metadata, supply, maxSupply, owner, frozen, warp, err := GetAsset(ctx, mu, asset)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrAssetMissing, asset)
+	}
+	nsupply, err := hmath.Sub(supply, amount)
+	if err != nil {
+		return fmt.Errorf("%w: could not subtract supply (asset=%s, supply=%d, amount=%d)", ErrInvalidSupply, asset, supply, amount)
+	}
+	return SetAsset(ctx, mu, asset, symbol, decimals, metadata, nsupply, maxSupply, owner, frozen, warp)
+}
+
+// NAI is only counted in the supply of the native asset while it is held in
+// state, i.e. in balances, loans, stakes, unbonding queues and fee shards. The
+// emission balancer keeps track of the rest of the total supply: the minted
+// rewards and swept fees that were not paid out yet. NAI moving between the two
+// must go through [AddNAI] and [SweepFeeShard].
+
+// AddNAI credits [amount] of NAI coming out of the emission balancer (e.g.
+// claimed rewards) to [addr] and adds it to the supply of NAI.
+func AddNAI(
+	ctx context.Context,
+	mu state.Mutable,
+	addr codec.Address,
+	amount uint64,
+) error {
+	if err := AddBalance(ctx, mu, addr, ids.Empty, amount, true); err != nil {
+		return err
+	}
+	return AddAssetSupply(ctx, mu, ids.Empty, amount)
+}
+
+// NAIHoldings is where the NAI counted in the supply of the native asset is
+// held.
+type NAIHoldings struct {
+	Balances  uint64 `json:"balances"`  // Held in balances
+	Loans     uint64 `json:"loans"`     // Exported to other chains
+	Staked    uint64 `json:"staked"`    // Staked by validators and delegators
+	Unbonding uint64 `json:"unbonding"` // Waiting in unbonding queues
+	Fees      uint64 `json:"fees"`      // Collected in fee shards and not swept into the emission balancer yet
+}
+
+// Total returns the NAI held in state.
+func (h *NAIHoldings) Total() uint64 {
+	return h.Balances + h.Loans + h.Staked + h.Unbonding + h.Fees
+}
+
+// GetNAIHoldings adds up all the NAI held in state. It iterates over every
+// balance, loan, stake, unbonding queue and fee shard, so it is only meant to
+// check the supply of NAI.
+func GetNAIHoldings(db database.Iteratee) (*NAIHoldings, error) {
+	holdings := &NAIHoldings{}
+
+	it := db.NewIteratorWithPrefix([]byte{balancePrefix})
+	for it.Next() {
+		k := it.Key()
+		if len(k) != 1+codec.AddressLen+hconsts.IDLen+hconsts.Uint16Len || ids.ID(k[1+codec.AddressLen:1+codec.AddressLen+hconsts.IDLen]) != ids.Empty {
+			continue
+		}
+		holdings.Balances += binary.BigEndian.Uint64(it.Value())
+	}
+	it.Release()
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	it = db.NewIteratorWithPrefix(append([]byte{loanPrefix}, ids.Empty[:]...))
+	for it.Next() {
+		if len(it.Key()) != 1+hconsts.IDLen*2+hconsts.Uint16Len {
+			continue
+		}
+		holdings.Loans += binary.BigEndian.Uint64(it.Value())
+	}
+	it.Release()
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	it = db.NewIteratorWithPrefix([]byte{unbondingPrefix})
+	for it.Next() {
+		if len(it.Key()) != 1+codec.AddressLen+hconsts.Uint16Len {
+			continue
+		}
+		entries, err := innerGetUnbonding(it.Value(), nil)
+		if err != nil {
+			it.Release()
+			return nil, err
+		}
+		for _, entry := range entries {
+			holdings.Unbonding += entry.Amount
+		}
+	}
+	it.Release()
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	it = db.NewIteratorWithPrefix([]byte{validatorUnbondingPrefix})
+	for it.Next() {
+		entries, err := innerGetValidatorUnbonding(it.Value(), nil)
+		if err != nil {
+			it.Release()
+			return nil, err
+		}
+		for _, entry := range entries {
+			holdings.Unbonding += entry.Amount
+		}
+	}
+	it.Release()
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	it = db.NewIteratorWithPrefix([]byte{feeShardPrefix})
+	for it.Next() {
+		collected, _, err := innerGetFeeShard(it.Value(), nil)
+		if err != nil {
+			it.Release()
+			return nil, err
+		}
+		holdings.Fees += collected
+	}
+	it.Release()
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	if err := IterateRegisterValidatorStakes(db, func(_ ids.NodeID, _ uint64, _ uint64, stakedAmount uint64, _ uint64, _ uint64, _ uint64, _ codec.Address, _ codec.Address) error {
+		holdings.Staked += stakedAmount
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if err := IterateDelegateUserStakes(db, func(_ codec.Address, _ ids.NodeID, _ uint64, _ uint64, stakedAmount uint64, _ codec.Address) error {
+		holdings.Staked += stakedAmount
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return holdings, nil
+}
+
 func DeleteAsset(ctx context.Context, mu state.Mutable, asset ids.ID) error {
 	k := AssetKey(asset)
 	return mu.Remove(ctx, k)
@@ -500,6 +957,16 @@ func RegisterValidatorStakeKey(nodeID ids.NodeID) (k []byte) {
 	return
 }
 
+// registerValidatorStakeLen is the length of the stake records written before
+// delegation fee rate increases were kept in state. They are still decoded,
+// without any pending increase.
+const registerValidatorStakeLen = 4*hconsts.Uint64Len + 2*codec.AddressLen
+
+// SetRegisterValidatorStake writes the stake of a validator. A delegation fee
+// rate increase that was not in effect yet when the record was written is kept
+// as [pendingDelegationFeeRate], which replaces [delegationFeeRate] from
+// [feeRateEffectiveBlock] onwards. [feeRateEffectiveBlock] is 0 if there is
+// none.
 func SetRegisterValidatorStake(
 	ctx context.Context,
 	mu state.Mutable,
@@ -508,11 +975,13 @@ func SetRegisterValidatorStake(
 	stakeEndBlock uint64,
 	stakedAmount uint64,
 	delegationFeeRate uint64,
+	pendingDelegationFeeRate uint64,
+	feeRateEffectiveBlock uint64,
 	rewardAddress codec.Address,
 	ownerAddress codec.Address,
 ) error {
 	key := RegisterValidatorStakeKey(nodeID)
-	v := make([]byte, (4*hconsts.Uint64Len)+(2*codec.AddressLen)) // Calculate the length of the encoded data
+	v := make([]byte, registerValidatorStakeLen+2*hconsts.Uint64Len) // Calculate the length of the encoded data
 
 	offset := 0
 	binary.BigEndian.PutUint64(v[offset:], stakeStartBlock)
@@ -528,6 +997,11 @@ func SetRegisterValidatorStake(
 	offset += codec.AddressLen
 
 	copy(v[offset:], ownerAddress[:])
+	offset += codec.AddressLen
+
+	binary.BigEndian.PutUint64(v[offset:], pendingDelegationFeeRate)
+	offset += hconsts.Uint64Len
+	binary.BigEndian.PutUint64(v[offset:], feeRateEffectiveBlock)
 
 	return mu.Insert(ctx, key, v)
 }
@@ -541,6 +1015,8 @@ func GetRegisterValidatorStake(
 	uint64, // StakeEndBlock
 	uint64, // StakedAmount
 	uint64, // DelegationFeeRate
+	uint64, // PendingDelegationFeeRate
+	uint64, // FeeRateEffectiveBlock
 	codec.Address, // RewardAddress
 	codec.Address, // OwnerAddress
 	error,
@@ -560,6 +1036,8 @@ func GetRegisterValidatorStakeFromState(
 	uint64, // StakeEndBlock
 	uint64, // StakedAmount
 	uint64, // DelegationFeeRate
+	uint64, // PendingDelegationFeeRate
+	uint64, // FeeRateEffectiveBlock
 	codec.Address, // RewardAddress
 	codec.Address, // OwnerAddress
 	error,
@@ -574,15 +1052,17 @@ func innerGetRegisterValidatorStake(v []byte, err error) (
 	uint64, // StakeEndBlock
 	uint64, // StakedAmount
 	uint64, // DelegationFeeRate
+	uint64, // PendingDelegationFeeRate
+	uint64, // FeeRateEffectiveBlock
 	codec.Address, // RewardAddress
 	codec.Address, // OwnerAddress
 	error,
 ) {
 	if errors.Is(err, database.ErrNotFound) {
-		return false, 0, 0, 0, 0, codec.Address{}, codec.Address{}, nil
+		return false, 0, 0, 0, 0, 0, 0, codec.Address{}, codec.Address{}, nil
 	}
 	if err != nil {
-		return false, 0, 0, 0, 0, codec.Address{}, codec.Address{}, nil
+		return false, 0, 0, 0, 0, 0, 0, codec.Address{}, codec.Address{}, nil
 	}
 
 	offset := 0
@@ -601,8 +1081,52 @@ func innerGetRegisterValidatorStake(v []byte, err error) (
 
 	var ownerAddress codec.Address
 	copy(ownerAddress[:], v[offset:offset+codec.AddressLen])
+	offset += codec.AddressLen
+
+	var pendingDelegationFeeRate, feeRateEffectiveBlock uint64
+	if len(v) > registerValidatorStakeLen {
+		pendingDelegationFeeRate = binary.BigEndian.Uint64(v[offset : offset+hconsts.Uint64Len])
+		offset += hconsts.Uint64Len
+		feeRateEffectiveBlock = binary.BigEndian.Uint64(v[offset : offset+hconsts.Uint64Len])
+	}
+
+	return true, stakeStartBlock, stakeEndBlock, stakedAmount, delegationFeeRate, pendingDelegationFeeRate, feeRateEffectiveBlock, rewardAddress, ownerAddress, nil
+}
 
-	return true, stakeStartBlock, stakeEndBlock, stakedAmount, delegationFeeRate, rewardAddress, ownerAddress, nil
+// Used to check the supply of NAI
+func IterateRegisterValidatorStakes(
+	db database.Iteratee,
+	f func(
+		nodeID ids.NodeID,
+		stakeStartBlock uint64,
+		stakeEndBlock uint64,
+		stakedAmount uint64,
+		delegationFeeRate uint64,
+		pendingDelegationFeeRate uint64,
+		feeRateEffectiveBlock uint64,
+		rewardAddress codec.Address,
+		ownerAddress codec.Address,
+	) error,
+) error {
+	it := db.NewIteratorWithPrefix([]byte{registerValidatorStakePrefix})
+	defer it.Release()
+
+	for it.Next() {
+		k := it.Key()
+		if len(k) != 1+hconsts.NodeIDLen+hconsts.Uint16Len {
+			continue
+		}
+		var nodeID ids.NodeID
+		copy(nodeID[:], k[1:])
+		_, stakeStartBlock, stakeEndBlock, stakedAmount, delegationFeeRate, pendingDelegationFeeRate, feeRateEffectiveBlock, rewardAddress, ownerAddress, err := innerGetRegisterValidatorStake(it.Value(), nil)
+		if err != nil {
+			return err
+		}
+		if err := f(nodeID, stakeStartBlock, stakeEndBlock, stakedAmount, delegationFeeRate, pendingDelegationFeeRate, feeRateEffectiveBlock, rewardAddress, ownerAddress); err != nil {
+			return err
+		}
+	}
+	return it.Error()
 }
 
 func DeleteRegisterValidatorStake(
@@ -623,6 +1147,15 @@ func DelegateUserStakeKey(owner codec.Address, nodeID ids.NodeID) (k []byte) {
 	return
 }
 
+// delegateUserStakeLen is the length of the delegation records written before
+// their reward checkpoint was kept in state. They are still decoded, with a
+// zero checkpoint.
+const delegateUserStakeLen = 3*hconsts.Uint64Len + 2*codec.AddressLen
+
+// SetDelegateUserStake writes a delegation. Its rewards are tracked by the
+// emission balancer: [rewardWeight] is the weight the delegation was added to
+// its validator with, and [rewardIndex] the reward index of the validator at
+// [rewardHeight], the height its rewards were last settled at.
 func SetDelegateUserStake(
 	ctx context.Context,
 	mu state.Mutable,
@@ -632,9 +1165,12 @@ func SetDelegateUserStake(
 	stakeEndBlock uint64,
 	stakedAmount uint64,
 	rewardAddress codec.Address,
+	rewardWeight uint64,
+	rewardIndex [2 * hconsts.Uint64Len]byte,
+	rewardHeight uint64,
 ) error {
 	key := DelegateUserStakeKey(owner, nodeID)
-	v := make([]byte, 3*hconsts.Uint64Len+2*codec.AddressLen) // Calculate the length of the encoded data
+	v := make([]byte, delegateUserStakeLen+4*hconsts.Uint64Len) // Calculate the length of the encoded data
 
 	offset := 0
 
@@ -648,6 +1184,13 @@ func SetDelegateUserStake(
 	copy(v[offset:], rewardAddress[:])
 	offset += codec.AddressLen
 	copy(v[offset:], owner[:])
+	offset += codec.AddressLen
+
+	binary.BigEndian.PutUint64(v[offset:], rewardWeight)
+	offset += hconsts.Uint64Len
+	copy(v[offset:], rewardIndex[:])
+	offset += len(rewardIndex)
+	binary.BigEndian.PutUint64(v[offset:], rewardHeight)
 
 	return mu.Insert(ctx, key, v)
 }
@@ -663,6 +1206,9 @@ func GetDelegateUserStake(
 	uint64, // StakedAmount
 	codec.Address, // RewardAddress
 	codec.Address, // OwnerAddress
+	uint64, // RewardWeight
+	[2 * hconsts.Uint64Len]byte, // RewardIndex
+	uint64, // RewardHeight
 	error,
 ) {
 	key := DelegateUserStakeKey(owner, nodeID)
@@ -682,6 +1228,9 @@ func GetDelegateUserStakeFromState(
 	uint64, // StakedAmount
 	codec.Address, // RewardAddress
 	codec.Address, // OwnerAddress
+	uint64, // RewardWeight
+	[2 * hconsts.Uint64Len]byte, // RewardIndex
+	uint64, // RewardHeight
 	error,
 ) {
 	values, errs := f(ctx, [][]byte{DelegateUserStakeKey(owner, nodeID)})
@@ -695,13 +1244,17 @@ func innerGetDelegateUserStake(v []byte, err error) (
 	uint64, // StakedAmount
 	codec.Address, // RewardAddress
 	codec.Address, // OwnerAddress
+	uint64, // RewardWeight
+	[2 * hconsts.Uint64Len]byte, // RewardIndex
+	uint64, // RewardHeight
 	error,
 ) {
+	var rewardIndex [2 * hconsts.Uint64Len]byte
 	if errors.Is(err, database.ErrNotFound) {
-		return false, 0, 0, 0, codec.Address{}, codec.Address{}, nil
+		return false, 0, 0, 0, codec.Address{}, codec.Address{}, 0, rewardIndex, 0, nil
 	}
 	if err != nil {
-		return false, 0, 0, 0, codec.Address{}, codec.Address{}, nil
+		return false, 0, 0, 0, codec.Address{}, codec.Address{}, 0, rewardIndex, 0, nil
 	}
 
 	offset := 0
@@ -718,8 +1271,51 @@ func innerGetDelegateUserStake(v []byte, err error) (
 	offset += codec.AddressLen
 	var ownerAddress codec.Address
 	copy(ownerAddress[:], v[offset:offset+codec.AddressLen])
+	offset += codec.AddressLen
+
+	var rewardWeight, rewardHeight uint64
+	if len(v) > delegateUserStakeLen {
+		rewardWeight = binary.BigEndian.Uint64(v[offset : offset+hconsts.Uint64Len])
+		offset += hconsts.Uint64Len
+		copy(rewardIndex[:], v[offset:offset+len(rewardIndex)])
+		offset += len(rewardIndex)
+		rewardHeight = binary.BigEndian.Uint64(v[offset : offset+hconsts.Uint64Len])
+	}
+
+	return true, stakeStartBlock, stakeEndBlock, stakedAmount, rewardAddress, ownerAddress, rewardWeight, rewardIndex, rewardHeight, nil
+}
 
-	return true, stakeStartBlock, stakeEndBlock, stakedAmount, rewardAddress, ownerAddress, nil
+// Used to check the supply of NAI
+func IterateDelegateUserStakes(
+	db database.Iteratee,
+	f func(
+		owner codec.Address,
+		nodeID ids.NodeID,
+		stakeStartBlock uint64,
+		stakeEndBlock uint64,
+		stakedAmount uint64,
+		rewardAddress codec.Address,
+	) error,
+) error {
+	it := db.NewIteratorWithPrefix([]byte{delegateUserStakePrefix})
+	defer it.Release()
+
+	for it.Next() {
+		k := it.Key()
+		if len(k) != 1+codec.AddressLen+hconsts.NodeIDLen+hconsts.Uint16Len {
+			continue
+		}
+		var nodeID ids.NodeID
+		copy(nodeID[:], k[1+codec.AddressLen:])
+		_, stakeStartBlock, stakeEndBlock, stakedAmount, rewardAddress, ownerAddress, _, _, _, err := innerGetDelegateUserStake(it.Value(), nil)
+		if err != nil {
+			return err
+		}
+		if err := f(ownerAddress, nodeID, stakeStartBlock, stakeEndBlock, stakedAmount, rewardAddress); err != nil {
+			return err
+		}
+	}
+	return it.Error()
 }
 
 func DeleteDelegateUserStake(
@@ -731,6 +1327,824 @@ func DeleteDelegateUserStake(
 	return mu.Remove(ctx, DelegateUserStakeKey(owner, nodeID))
 }
 
+// UnbondingEntry is a stake that was withdrawn or undelegated and can be
+// released to its owner by blocks at or after [ReleaseBlock].
+type UnbondingEntry struct {
+	NodeID       ids.NodeID `json:"nodeID"`       // Node ID of the validator the stake was placed on
+	Amount       uint64     `json:"amount"`       // Amount of NAI unbonding
+	ReleaseBlock uint64     `json:"releaseBlock"` // Block height from which the stake can be released
+}
+
+// [unbondingPrefix] + [owner]
+func UnbondingKey(owner codec.Address) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen+hconsts.Uint16Len) // Length of prefix + owner + UnbondingChunks
+	k[0] = unbondingPrefix
+	copy(k[1:], owner[:])
+	binary.BigEndian.PutUint16(k[1+codec.AddressLen:], UnbondingChunks) // Adding UnbondingChunks
+	return
+}
+
+// SetUnbonding replaces the unbonding queue of [owner]. An empty queue is
+// removed.
+func SetUnbonding(
+	ctx context.Context,
+	mu state.Mutable,
+	owner codec.Address,
+	entries []*UnbondingEntry,
+) error {
+	key := UnbondingKey(owner)
+	if len(entries) == 0 {
+		return mu.Remove(ctx, key)
+	}
+	if len(entries) > MaxUnbondingEntries {
+		return ErrUnbondingQueueFull
+	}
+
+	v := make([]byte, hconsts.Uint16Len+len(entries)*unbondingEntryLen)
+	binary.BigEndian.PutUint16(v, uint16(len(entries)))
+	offset := hconsts.Uint16Len
+	for _, entry := range entries {
+		copy(v[offset:], entry.NodeID[:])
+		offset += hconsts.NodeIDLen
+		binary.BigEndian.PutUint64(v[offset:], entry.Amount)
+		offset += hconsts.Uint64Len
+		binary.BigEndian.PutUint64(v[offset:], entry.ReleaseBlock)
+		offset += hconsts.Uint64Len
+	}
+	return mu.Insert(ctx, key, v)
+}
+
+// AddUnbonding appends a stake to the unbonding queue of [owner].
+func AddUnbonding(
+	ctx context.Context,
+	mu state.Mutable,
+	owner codec.Address,
+	nodeID ids.NodeID,
+	amount uint64,
+	releaseBlock uint64,
+) error {
+	entries, err := GetUnbonding(ctx, mu, owner)
+	if err != nil {
+		return err
+	}
+	entries = append(entries, &UnbondingEntry{
+		NodeID:       nodeID,
+		Amount:       amount,
+		ReleaseBlock: releaseBlock,
+	})
+	return SetUnbonding(ctx, mu, owner, entries)
+}
+
+func GetUnbonding(
+	ctx context.Context,
+	im state.Immutable,
+	owner codec.Address,
+) ([]*UnbondingEntry, error) {
+	key := UnbondingKey(owner)
+	v, err := im.GetValue(ctx, key)
+	return innerGetUnbonding(v, err)
+}
+
+// Used to serve RPC queries
+func GetUnbondingFromState(
+	ctx context.Context,
+	f ReadState,
+	owner codec.Address,
+) ([]*UnbondingEntry, error) {
+	values, errs := f(ctx, [][]byte{UnbondingKey(owner)})
+	return innerGetUnbonding(values[0], errs[0])
+}
+
+func innerGetUnbonding(v []byte, err error) ([]*UnbondingEntry, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(v) < hconsts.Uint16Len {
+		return nil, ErrInvalidUnbonding
+	}
+
+	numEntries := int(binary.BigEndian.Uint16(v))
+	if len(v) != hconsts.Uint16Len+numEntries*unbondingEntryLen {
+		return nil, ErrInvalidUnbonding
+	}
+	entries := make([]*UnbondingEntry, 0, numEntries)
+	offset := hconsts.Uint16Len
+	for i := 0; i < numEntries; i++ {
+		entry := &UnbondingEntry{}
+		copy(entry.NodeID[:], v[offset:offset+hconsts.NodeIDLen])
+		offset += hconsts.NodeIDLen
+		entry.Amount = binary.BigEndian.Uint64(v[offset : offset+hconsts.Uint64Len])
+		offset += hconsts.Uint64Len
+		entry.ReleaseBlock = binary.BigEndian.Uint64(v[offset : offset+hconsts.Uint64Len])
+		offset += hconsts.Uint64Len
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// ValidatorUnbondingEntry is a stake that was taken off a validator by its
+// owner and can be released to it by blocks at or after [ReleaseBlock]. Until
+// then it can still be slashed for the epochs it was staked in.
+type ValidatorUnbondingEntry struct {
+	Owner         codec.Address `json:"owner"`         // Address of the owner of the stake
+	Amount        uint64        `json:"amount"`        // Amount of NAI unbonding
+	UnbondedBlock uint64        `json:"unbondedBlock"` // Block height the stake was taken off the validator at
+	ReleaseBlock  uint64        `json:"releaseBlock"`  // Block height from which the stake can be released
+}
+
+// [validatorUnbondingPrefix] + [nodeID]
+func ValidatorUnbondingKey(nodeID ids.NodeID) (k []byte) {
+	k = make([]byte, 1+hconsts.NodeIDLen+hconsts.Uint16Len) // Length of prefix + nodeID + ValidatorUnbondingChunks
+	k[0] = validatorUnbondingPrefix
+	copy(k[1:], nodeID[:])
+	binary.BigEndian.PutUint16(k[1+hconsts.NodeIDLen:], ValidatorUnbondingChunks) // Adding ValidatorUnbondingChunks
+	return
+}
+
+// SetValidatorUnbonding replaces the unbonding queue of the validator with
+// [nodeID]. An empty queue is removed.
+func SetValidatorUnbonding(
+	ctx context.Context,
+	mu state.Mutable,
+	nodeID ids.NodeID,
+	entries []*ValidatorUnbondingEntry,
+) error {
+	key := ValidatorUnbondingKey(nodeID)
+	if len(entries) == 0 {
+		return mu.Remove(ctx, key)
+	}
+	if len(entries) > MaxUnbondingEntries {
+		return ErrUnbondingQueueFull
+	}
+
+	v := make([]byte, hconsts.Uint16Len+len(entries)*validatorUnbondingEntryLen)
+	binary.BigEndian.PutUint16(v, uint16(len(entries)))
+	offset := hconsts.Uint16Len
+	for _, entry := range entries {
+		copy(v[offset:], entry.Owner[:])
+		offset += codec.AddressLen
+		binary.BigEndian.PutUint64(v[offset:], entry.Amount)
+		offset += hconsts.Uint64Len
+		binary.BigEndian.PutUint64(v[offset:], entry.UnbondedBlock)
+		offset += hconsts.Uint64Len
+		binary.BigEndian.PutUint64(v[offset:], entry.ReleaseBlock)
+		offset += hconsts.Uint64Len
+	}
+	return mu.Insert(ctx, key, v)
+}
+
+// AddValidatorUnbonding appends a stake of [owner] to the unbonding queue of
+// the validator with [nodeID].
+func AddValidatorUnbonding(
+	ctx context.Context,
+	mu state.Mutable,
+	nodeID ids.NodeID,
+	owner codec.Address,
+	amount uint64,
+	unbondedBlock uint64,
+	releaseBlock uint64,
+) error {
+	entries, err := GetValidatorUnbonding(ctx, mu, nodeID)
+	if err != nil {
+		return err
+	}
+	entries = append(entries, &ValidatorUnbondingEntry{
+		Owner:         owner,
+		Amount:        amount,
+		UnbondedBlock: unbondedBlock,
+		ReleaseBlock:  releaseBlock,
+	})
+	return SetValidatorUnbonding(ctx, mu, nodeID, entries)
+}
+
+func GetValidatorUnbonding(
+	ctx context.Context,
+	im state.Immutable,
+	nodeID ids.NodeID,
+) ([]*ValidatorUnbondingEntry, error) {
+	key := ValidatorUnbondingKey(nodeID)
+	v, err := im.GetValue(ctx, key)
+	return innerGetValidatorUnbonding(v, err)
+}
+
+// Used to serve RPC queries
+func GetValidatorUnbondingFromState(
+	ctx context.Context,
+	f ReadState,
+	nodeID ids.NodeID,
+) ([]*ValidatorUnbondingEntry, error) {
+	values, errs := f(ctx, [][]byte{ValidatorUnbondingKey(nodeID)})
+	return innerGetValidatorUnbonding(values[0], errs[0])
+}
+
+func innerGetValidatorUnbonding(v []byte, err error) ([]*ValidatorUnbondingEntry, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(v) < hconsts.Uint16Len {
+		return nil, ErrInvalidUnbonding
+	}
+
+	numEntries := int(binary.BigEndian.Uint16(v))
+	if len(v) != hconsts.Uint16Len+numEntries*validatorUnbondingEntryLen {
+		return nil, ErrInvalidUnbonding
+	}
+	entries := make([]*ValidatorUnbondingEntry, 0, numEntries)
+	offset := hconsts.Uint16Len
+	for i := 0; i < numEntries; i++ {
+		entry := &ValidatorUnbondingEntry{}
+		copy(entry.Owner[:], v[offset:offset+codec.AddressLen])
+		offset += codec.AddressLen
+		entry.Amount = binary.BigEndian.Uint64(v[offset : offset+hconsts.Uint64Len])
+		offset += hconsts.Uint64Len
+		entry.UnbondedBlock = binary.BigEndian.Uint64(v[offset : offset+hconsts.Uint64Len])
+		offset += hconsts.Uint64Len
+		entry.ReleaseBlock = binary.BigEndian.Uint64(v[offset : offset+hconsts.Uint64Len])
+		offset += hconsts.Uint64Len
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// [nftCollectionPrefix] + [collection]
+func NFTCollectionKey(collection ids.ID) (k []byte) {
+	k = make([]byte, 1+hconsts.IDLen+hconsts.Uint16Len)
+	k[0] = nftCollectionPrefix
+	copy(k[1:], collection[:])
+	binary.BigEndian.PutUint16(k[1+hconsts.IDLen:], NFTCollectionChunks)
+	return
+}
+
+// Used to serve RPC queries
+func GetNFTCollectionFromState(
+	ctx context.Context,
+	f ReadState,
+	collection ids.ID,
+) (bool, []byte, []byte, uint64, codec.Address, error) {
+	values, errs := f(ctx, [][]byte{NFTCollectionKey(collection)})
+	return innerGetNFTCollection(values[0], errs[0])
+}
+
+func GetNFTCollection(
+	ctx context.Context,
+	im state.Immutable,
+	collection ids.ID,
+) (bool, []byte, []byte, uint64, codec.Address, error) {
+	k := NFTCollectionKey(collection)
+	return innerGetNFTCollection(im.GetValue(ctx, k))
+}
+
+func innerGetNFTCollection(
+	v []byte,
+	err error,
+) (bool, []byte, []byte, uint64, codec.Address, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return false, nil, nil, 0, codec.EmptyAddress, nil
+	}
+	if err != nil {
+		return false, nil, nil, 0, codec.EmptyAddress, err
+	}
+	symbolLen := binary.BigEndian.Uint16(v)
+	symbol := v[hconsts.Uint16Len : hconsts.Uint16Len+symbolLen]
+	metadataLen := binary.BigEndian.Uint16(v[hconsts.Uint16Len+symbolLen:])
+	metadata := v[hconsts.Uint16Len+symbolLen+hconsts.Uint16Len : hconsts.Uint16Len+symbolLen+hconsts.Uint16Len+metadataLen]
+	supply := binary.BigEndian.Uint64(v[hconsts.Uint16Len+symbolLen+hconsts.Uint16Len+metadataLen:])
+	var owner codec.Address
+	copy(owner[:], v[hconsts.Uint16Len+symbolLen+hconsts.Uint16Len+metadataLen+hconsts.Uint64Len:])
+	return true, symbol, metadata, supply, owner, nil
+}
+
+// SetNFTCollection stores the record of [collection]. [supply] is the number
+// of NFTs of the collection in circulation.
+func SetNFTCollection(
+	ctx context.Context,
+	mu state.Mutable,
+	collection ids.ID,
+	symbol []byte,
+	metadata []byte,
+	supply uint64,
+	owner codec.Address,
+) error {
+	k := NFTCollectionKey(collection)
+	symbolLen := len(symbol)
+	metadataLen := len(metadata)
+	v := make([]byte, hconsts.Uint16Len+symbolLen+hconsts.Uint16Len+metadataLen+hconsts.Uint64Len+codec.AddressLen)
+	binary.BigEndian.PutUint16(v, uint16(symbolLen))
+	copy(v[hconsts.Uint16Len:], symbol)
+	binary.BigEndian.PutUint16(v[hconsts.Uint16Len+symbolLen:], uint16(metadataLen))
+	copy(v[hconsts.Uint16Len+symbolLen+hconsts.Uint16Len:], metadata)
+	binary.BigEndian.PutUint64(v[hconsts.Uint16Len+symbolLen+hconsts.Uint16Len+metadataLen:], supply)
+	copy(v[hconsts.Uint16Len+symbolLen+hconsts.Uint16Len+metadataLen+hconsts.Uint64Len:], owner[:])
+	return mu.Insert(ctx, k, v)
+}
+
+// [nftPrefix] + [collection] + [id]
+func NFTKey(collection ids.ID, id uint64) (k []byte) {
+	k = make([]byte, 1+hconsts.IDLen+hconsts.Uint64Len+hconsts.Uint16Len)
+	k[0] = nftPrefix
+	copy(k[1:], collection[:])
+	binary.BigEndian.PutUint64(k[1+hconsts.IDLen:], id)
+	binary.BigEndian.PutUint16(k[1+hconsts.IDLen+hconsts.Uint64Len:], NFTChunks)
+	return
+}
+
+// Used to serve RPC queries
+func GetNFTFromState(
+	ctx context.Context,
+	f ReadState,
+	collection ids.ID,
+	id uint64,
+) (bool, codec.Address, []byte, error) {
+	values, errs := f(ctx, [][]byte{NFTKey(collection, id)})
+	return innerGetNFT(values[0], errs[0])
+}
+
+func GetNFT(
+	ctx context.Context,
+	im state.Immutable,
+	collection ids.ID,
+	id uint64,
+) (bool, codec.Address, []byte, error) {
+	k := NFTKey(collection, id)
+	return innerGetNFT(im.GetValue(ctx, k))
+}
+
+func innerGetNFT(v []byte, err error) (bool, codec.Address, []byte, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return false, codec.EmptyAddress, nil, nil
+	}
+	if err != nil {
+		return false, codec.EmptyAddress, nil, err
+	}
+	var owner codec.Address
+	copy(owner[:], v[:codec.AddressLen])
+	metadataLen := binary.BigEndian.Uint16(v[codec.AddressLen:])
+	metadata := v[codec.AddressLen+hconsts.Uint16Len : codec.AddressLen+hconsts.Uint16Len+metadataLen]
+	return true, owner, metadata, nil
+}
+
+func SetNFT(
+	ctx context.Context,
+	mu state.Mutable,
+	collection ids.ID,
+	id uint64,
+	owner codec.Address,
+	metadata []byte,
+) error {
+	k := NFTKey(collection, id)
+	metadataLen := len(metadata)
+	v := make([]byte, codec.AddressLen+hconsts.Uint16Len+metadataLen)
+	copy(v, owner[:])
+	binary.BigEndian.PutUint16(v[codec.AddressLen:], uint16(metadataLen))
+	copy(v[codec.AddressLen+hconsts.Uint16Len:], metadata)
+	return mu.Insert(ctx, k, v)
+}
+
+func DeleteNFT(ctx context.Context, mu state.Mutable, collection ids.ID, id uint64) error {
+	return mu.Remove(ctx, NFTKey(collection, id))
+}
+
+// OwnedNFT identifies an NFT held by an address.
+type OwnedNFT struct {
+	Collection ids.ID `json:"collection"` // ID of the collection the NFT belongs to
+	ID         uint64 `json:"id"`         // ID of the NFT within its collection
+}
+
+// [nftOwnerPrefix] + [owner] + [collection] + [id]
+func NFTOwnerKey(owner codec.Address, collection ids.ID, id uint64) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen+hconsts.IDLen+hconsts.Uint64Len+hconsts.Uint16Len)
+	k[0] = nftOwnerPrefix
+	copy(k[1:], owner[:])
+	copy(k[1+codec.AddressLen:], collection[:])
+	binary.BigEndian.PutUint64(k[1+codec.AddressLen+hconsts.IDLen:], id)
+	binary.BigEndian.PutUint16(k[1+codec.AddressLen+hconsts.IDLen+hconsts.Uint64Len:], NFTOwnerChunks)
+	return
+}
+
+// AddNFTToOwner records that [owner] holds the NFT [id] of [collection].
+func AddNFTToOwner(
+	ctx context.Context,
+	mu state.Mutable,
+	owner codec.Address,
+	collection ids.ID,
+	id uint64,
+) error {
+	return mu.Insert(ctx, NFTOwnerKey(owner, collection, id), []byte{successByte})
+}
+
+// RemoveNFTFromOwner removes the NFT [id] of [collection] from the NFTs held
+// by [owner].
+func RemoveNFTFromOwner(
+	ctx context.Context,
+	mu state.Mutable,
+	owner codec.Address,
+	collection ids.ID,
+	id uint64,
+) error {
+	return mu.Remove(ctx, NFTOwnerKey(owner, collection, id))
+}
+
+// GetNFTsOfOwner is used to serve RPC queries. It returns up to [limit] of the NFTs held by [owner], ordered by
+// collection and ID, starting at [start] (or at the first one if nil). It also
+// returns the NFT the next page starts at, which is nil once all of them were
+// returned.
+func GetNFTsOfOwner(
+	db database.Iteratee,
+	owner codec.Address,
+	start *OwnedNFT,
+	limit int,
+) ([]*OwnedNFT, *OwnedNFT, error) {
+	prefix := make([]byte, 1+codec.AddressLen)
+	prefix[0] = nftOwnerPrefix
+	copy(prefix[1:], owner[:])
+	var startKey []byte
+	if start != nil {
+		startKey = NFTOwnerKey(owner, start.Collection, start.ID)
+	}
+	it := db.NewIteratorWithStartAndPrefix(startKey, prefix)
+	defer it.Release()
+
+	nfts := []*OwnedNFT{}
+	for it.Next() {
+		k := it.Key()
+		if len(k) != 1+codec.AddressLen+hconsts.IDLen+hconsts.Uint64Len+hconsts.Uint16Len {
+			continue
+		}
+		nft := &OwnedNFT{}
+		copy(nft.Collection[:], k[1+codec.AddressLen:])
+		nft.ID = binary.BigEndian.Uint64(k[1+codec.AddressLen+hconsts.IDLen:])
+		if len(nfts) == limit {
+			return nfts, nft, it.Error()
+		}
+		nfts = append(nfts, nft)
+	}
+	return nfts, nil, it.Error()
+}
+
+// [allowancePrefix] + [owner] + [spender] + [asset]
+func AllowanceKey(owner codec.Address, spender codec.Address, asset ids.ID) (k []byte) {
+	k = make([]byte, 1+2*codec.AddressLen+hconsts.IDLen+hconsts.Uint16Len)
+	k[0] = allowancePrefix
+	copy(k[1:], owner[:])
+	copy(k[1+codec.AddressLen:], spender[:])
+	copy(k[1+2*codec.AddressLen:], asset[:])
+	binary.BigEndian.PutUint16(k[1+2*codec.AddressLen+hconsts.IDLen:], AllowanceChunks)
+	return
+}
+
+// GetAllowance returns how much of [asset] [spender] can still transfer out of
+// the balance of [owner] and the block from which it can no longer do so (0 if
+// the allowance does not expire).
+func GetAllowance(
+	ctx context.Context,
+	im state.Immutable,
+	owner codec.Address,
+	spender codec.Address,
+	asset ids.ID,
+) (uint64, uint64, error) {
+	k := AllowanceKey(owner, spender, asset)
+	return innerGetAllowance(im.GetValue(ctx, k))
+}
+
+// Used to serve RPC queries
+func GetAllowanceFromState(
+	ctx context.Context,
+	f ReadState,
+	owner codec.Address,
+	spender codec.Address,
+	asset ids.ID,
+) (uint64, uint64, error) {
+	values, errs := f(ctx, [][]byte{AllowanceKey(owner, spender, asset)})
+	return innerGetAllowance(values[0], errs[0])
+}
+
+func innerGetAllowance(v []byte, err error) (uint64, uint64, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	amount := binary.BigEndian.Uint64(v)
+	expiryBlock := binary.BigEndian.Uint64(v[hconsts.Uint64Len:])
+	return amount, expiryBlock, nil
+}
+
+// SetAllowance replaces the allowance of [spender] on the [asset] balance of
+// [owner]. An allowance of 0 is removed.
+func SetAllowance(
+	ctx context.Context,
+	mu state.Mutable,
+	owner codec.Address,
+	spender codec.Address,
+	asset ids.ID,
+	amount uint64,
+	expiryBlock uint64,
+) error {
+	k := AllowanceKey(owner, spender, asset)
+	if amount == 0 {
+		return mu.Remove(ctx, k)
+	}
+	v := make([]byte, 2*hconsts.Uint64Len)
+	binary.BigEndian.PutUint64(v, amount)
+	binary.BigEndian.PutUint64(v[hconsts.Uint64Len:], expiryBlock)
+	return mu.Insert(ctx, k, v)
+}
+
+// [emissionPrefix]
+func EmissionKey() (k []byte) {
+	k = make([]byte, 1+hconsts.Uint16Len)
+	k[0] = emissionPrefix
+	binary.BigEndian.PutUint16(k[1:], EmissionChunks)
+	return
+}
+
+// GetEmission returns the serialized header of the emission balancer.
+func GetEmission(
+	ctx context.Context,
+	im state.Immutable,
+) ([]byte, error) {
+	v, err := im.GetValue(ctx, EmissionKey())
+	return innerGetEmission(v, err)
+}
+
+// Used to serve RPC queries
+func GetEmissionFromState(
+	ctx context.Context,
+	f ReadState,
+) ([]byte, error) {
+	values, errs := f(ctx, [][]byte{EmissionKey()})
+	return innerGetEmission(values[0], errs[0])
+}
+
+func innerGetEmission(v []byte, err error) ([]byte, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, ErrEmissionMissing
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// SetEmission writes the serialized header of the emission balancer.
+func SetEmission(
+	ctx context.Context,
+	mu state.Mutable,
+	header []byte,
+) error {
+	return mu.Insert(ctx, EmissionKey(), header)
+}
+
+// [validatorRecordPrefix] + [nodeID]
+func ValidatorRecordKey(nodeID ids.NodeID) (k []byte) {
+	k = make([]byte, 1+hconsts.NodeIDLen+hconsts.Uint16Len)
+	k[0] = validatorRecordPrefix
+	copy(k[1:], nodeID[:])
+	binary.BigEndian.PutUint16(k[1+hconsts.NodeIDLen:], ValidatorRecordChunks)
+	return
+}
+
+// GetValidatorRecord returns the serialized record the emission balancer
+// keeps for [nodeID], if there is one.
+func GetValidatorRecord(
+	ctx context.Context,
+	im state.Immutable,
+	nodeID ids.NodeID,
+) (bool, []byte, error) {
+	v, err := im.GetValue(ctx, ValidatorRecordKey(nodeID))
+	return innerGetOptional(v, err)
+}
+
+// Used to serve RPC queries
+func GetValidatorRecordFromState(
+	ctx context.Context,
+	f ReadState,
+	nodeID ids.NodeID,
+) (bool, []byte, error) {
+	values, errs := f(ctx, [][]byte{ValidatorRecordKey(nodeID)})
+	return innerGetOptional(values[0], errs[0])
+}
+
+func innerGetOptional(v []byte, err error) (bool, []byte, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return false, nil, nil
+	}
+	if err != nil {
+		return false, nil, err
+	}
+	return true, v, nil
+}
+
+func SetValidatorRecord(
+	ctx context.Context,
+	mu state.Mutable,
+	nodeID ids.NodeID,
+	record []byte,
+) error {
+	return mu.Insert(ctx, ValidatorRecordKey(nodeID), record)
+}
+
+func DeleteValidatorRecord(
+	ctx context.Context,
+	mu state.Mutable,
+	nodeID ids.NodeID,
+) error {
+	return mu.Remove(ctx, ValidatorRecordKey(nodeID))
+}
+
+// IterateValidatorRecords calls [f] with the node ID and the serialized
+// record of every validator the emission balancer keeps a record for.
+func IterateValidatorRecords(
+	db database.Iteratee,
+	f func(nodeID ids.NodeID, record []byte) error,
+) error {
+	it := db.NewIteratorWithPrefix([]byte{validatorRecordPrefix})
+	defer it.Release()
+	for it.Next() {
+		k := it.Key()
+		if len(k) != 1+hconsts.NodeIDLen+hconsts.Uint16Len {
+			continue
+		}
+		if err := f(ids.NodeID(k[1:1+hconsts.NodeIDLen]), it.Value()); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+// [emissionActiveSetPrefix]
+func ActiveSetKey() (k []byte) {
+	k = make([]byte, 1+hconsts.Uint16Len)
+	k[0] = emissionActiveSetPrefix
+	binary.BigEndian.PutUint16(k[1:], ActiveSetChunks)
+	return
+}
+
+// GetActiveSet returns the serialized active set registered for the next
+// epoch boundary, if there is one.
+func GetActiveSet(
+	ctx context.Context,
+	im state.Immutable,
+) (bool, []byte, error) {
+	v, err := im.GetValue(ctx, ActiveSetKey())
+	return innerGetOptional(v, err)
+}
+
+// Used to serve RPC queries
+func GetActiveSetFromState(
+	ctx context.Context,
+	f ReadState,
+) (bool, []byte, error) {
+	values, errs := f(ctx, [][]byte{ActiveSetKey()})
+	return innerGetOptional(values[0], errs[0])
+}
+
+func SetActiveSet(
+	ctx context.Context,
+	mu state.Mutable,
+	set []byte,
+) error {
+	return mu.Insert(ctx, ActiveSetKey(), set)
+}
+
+// FeeShard returns the fee shard the fees sponsored by [addr] are collected
+// in.
+func FeeShard(addr codec.Address) uint8 {
+	return addr[codec.AddressLen-1] % NumFeeShards
+}
+
+// [feeShardPrefix] + [shard]
+func FeeShardKey(shard uint8) (k []byte) {
+	k = make([]byte, 2+hconsts.Uint16Len)
+	k[0] = feeShardPrefix
+	k[1] = shard
+	binary.BigEndian.PutUint16(k[2:], FeeShardChunks)
+	return
+}
+
+// GetFeeShard returns the fees collected in [shard] that were not swept into
+// the emission balancer yet, and the part of them that was collected from the
+// last transaction paying into it, which may still be refunded.
+func GetFeeShard(
+	ctx context.Context,
+	im state.Immutable,
+	shard uint8,
+) (uint64, uint64, error) {
+	v, err := im.GetValue(ctx, FeeShardKey(shard))
+	return innerGetFeeShard(v, err)
+}
+
+func innerGetFeeShard(v []byte, err error) (uint64, uint64, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(v) != 2*hconsts.Uint64Len {
+		return 0, 0, ErrInvalidFeeShard
+	}
+	return binary.BigEndian.Uint64(v), binary.BigEndian.Uint64(v[hconsts.Uint64Len:]), nil
+}
+
+func SetFeeShard(
+	ctx context.Context,
+	mu state.Mutable,
+	shard uint8,
+	collected uint64,
+	pending uint64,
+) error {
+	v := make([]byte, 2*hconsts.Uint64Len)
+	binary.BigEndian.PutUint64(v, collected)
+	binary.BigEndian.PutUint64(v[hconsts.Uint64Len:], pending)
+	return mu.Insert(ctx, FeeShardKey(shard), v)
+}
+
+// ChargeFee debits the max fee of a transaction from [addr] and collects it
+// in the fee shard of [addr]. Collected fees stay in the supply of NAI until
+// they are swept into the emission balancer.
+func ChargeFee(
+	ctx context.Context,
+	mu state.Mutable,
+	addr codec.Address,
+	amount uint64,
+) error {
+	if err := SubBalance(ctx, mu, addr, ids.Empty, amount); err != nil {
+		return err
+	}
+	shard := FeeShard(addr)
+	collected, _, err := GetFeeShard(ctx, mu, shard)
+	if err != nil {
+		return err
+	}
+	ncollected, err := hmath.Add64(collected, amount)
+	if err != nil {
+		return fmt.Errorf("%w: could not add fee (shard=%d, collected=%d, amount=%d)", ErrInvalidFeeShard, shard, collected, amount)
+	}
+	return SetFeeShard(ctx, mu, shard, ncollected, amount)
+}
+
+// RefundFee credits the unused part of the fee last charged to [addr] back to
+// it. When [addr] has no balance anymore, nothing is refunded.
+func RefundFee(
+	ctx context.Context,
+	mu state.Mutable,
+	addr codec.Address,
+	amount uint64,
+) error {
+	_, _, exists, err := getBalance(ctx, mu, addr, ids.Empty)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+	shard := FeeShard(addr)
+	collected, _, err := GetFeeShard(ctx, mu, shard)
+	if err != nil {
+		return err
+	}
+	ncollected, err := hmath.Sub(collected, amount)
+	if err != nil {
+		return fmt.Errorf("%w: could not refund fee (shard=%d, collected=%d, amount=%d)", ErrInvalidFeeShard, shard, collected, amount)
+	}
+	if err := SetFeeShard(ctx, mu, shard, ncollected, 0); err != nil {
+		return err
+	}
+	return AddBalance(ctx, mu, addr, ids.Empty, amount, false)
+}
+
+// SweepFeeShard empties [shard], except for the fee of the last transaction
+// paying into it, which may still be refunded. The amount swept is taken off
+// the supply of NAI and returned, so that the caller can hand it over to the
+// emission balancer. The caller must declare the keys of the shard and of the
+// NAI asset.
+func SweepFeeShard(
+	ctx context.Context,
+	mu state.Mutable,
+	shard uint8,
+) (uint64, error) {
+	collected, pending, err := GetFeeShard(ctx, mu, shard)
+	if err != nil {
+		return 0, err
+	}
+	if collected == pending {
+		return 0, nil
+	}
+	swept := collected - pending
+	if err := SetFeeShard(ctx, mu, shard, pending, pending); err != nil {
+		return 0, err
+	}
+	return swept, SubAssetSupply(ctx, mu, ids.Empty, swept)
+}
+
 func HeightKey() (k []byte) {
 	return heightKey
 }
@@ -803,7 +803,7 @@ var _ = ginkgo.Describe("[Test]", func() {
 			)
 			gomega.Ω(err).Should(gomega.BeNil())
 			gomega.Ω(aNewSenderBalance).Should(gomega.Equal(uint64(0)))
-			exists, symbol, decimals, metadata, supply, owner, warp, err := instancesB[0].ncli.Asset(context.Background(), newAsset, false)
+			exists, symbol, decimals, metadata, supply, _, owner, _, warp, err := instancesB[0].ncli.Asset(context.Background(), newAsset, false)
 			gomega.Ω(err).Should(gomega.BeNil())
 			gomega.Ω(exists).Should(gomega.BeTrue())
 			gomega.Ω(string(symbol)).Should(gomega.Equal(nconsts.Symbol))
@@ -896,7 +896,7 @@ var _ = ginkgo.Describe("[Test]", func() {
 			otherBalance, err := instancesB[0].ncli.Balance(context.Background(), aother, newAsset)
 			gomega.Ω(err).Should(gomega.BeNil())
 			gomega.Ω(otherBalance).Should(gomega.Equal(uint64(2900)))
-			exists, symbol, decimals, metadata, supply, owner, warp, err := instancesB[0].ncli.Asset(context.Background(), newAsset, false)
+			exists, symbol, decimals, metadata, supply, _, owner, _, warp, err := instancesB[0].ncli.Asset(context.Background(), newAsset, false)
 			gomega.Ω(err).Should(gomega.BeNil())
 			gomega.Ω(exists).Should(gomega.BeTrue())
 			gomega.Ω(string(symbol)).Should(gomega.Equal(nconsts.Symbol))
@@ -1064,7 +1064,7 @@ var _ = ginkgo.Describe("[Test]", func() {
 			otherBalance, err := instancesB[0].ncli.Balance(context.Background(), aother, newAsset)
 			gomega.Ω(err).Should(gomega.BeNil())
 			gomega.Ω(otherBalance).Should(gomega.Equal(uint64(0)))
-			exists, _, _, _, _, _, _, err := instancesB[0].ncli.Asset(context.Background(), newAsset, false)
+			exists, _, _, _, _, _, _, _, _, err := instancesB[0].ncli.Asset(context.Background(), newAsset, false)
 			gomega.Ω(err).Should(gomega.BeNil())
 			gomega.Ω(exists).Should(gomega.BeFalse())
 		})
@@ -7,11 +7,13 @@ import (
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -46,8 +48,10 @@ import (
 	"github.com/nuklai/nuklaivm/auth"
 	nconsts "github.com/nuklai/nuklaivm/consts"
 	"github.com/nuklai/nuklaivm/controller"
+	"github.com/nuklai/nuklaivm/emission"
 	"github.com/nuklai/nuklaivm/genesis"
 	nrpc "github.com/nuklai/nuklaivm/rpc"
+	"github.com/nuklai/nuklaivm/storage"
 )
 
 var (
@@ -102,6 +106,14 @@ var (
 	rsender2 codec.Address
 	sender2  string
 
+	priv3    ed25519.PrivateKey
+	factory3 *auth.ED25519Factory
+	rsender3 codec.Address
+	sender3  string
+
+	// node signer keys of the VMs, which stake NAI as validators
+	validatorKeys []*bls.SecretKey
+
 	asset1         []byte
 	asset1Symbol   []byte
 	asset1Decimals uint8
@@ -122,6 +134,14 @@ var (
 
 	networkID uint32
 	gen       *genesis.Genesis
+
+	// Validators of the subnet, as the P-Chain reports them. They are only
+	// set once the staking specs start, so that transactions are gossiped to
+	// every VM until then, as they are when the P-Chain can't be reached.
+	validatorSet     map[ids.NodeID]*validators.GetValidatorOutput
+	validatorSetLock sync.RWMutex
+
+	errValidatorsUnavailable = errors.New("validators unavailable")
 )
 
 type instance struct {
@@ -163,6 +183,23 @@ var _ = ginkgo.BeforeSuite(func() {
 		zap.String("pk", hex.EncodeToString(priv2[:])),
 	)
 
+	priv3, err = ed25519.GeneratePrivateKey()
+	gomega.Ω(err).Should(gomega.BeNil())
+	factory3 = auth.NewED25519Factory(priv3)
+	rsender3 = auth.NewED25519Address(priv3.PublicKey())
+	sender3 = codec.MustAddressBech32(nconsts.HRP, rsender3)
+	log.Debug(
+		"generated key",
+		zap.String("addr", sender3),
+		zap.String("pk", hex.EncodeToString(priv3[:])),
+	)
+
+	validatorKeys = make([]*bls.SecretKey, vms)
+	for i := range validatorKeys {
+		validatorKeys[i], err = bls.NewSecretKey()
+		gomega.Ω(err).Should(gomega.BeNil())
+	}
+
 	asset1 = []byte("1")
 	asset1Symbol = []byte("s1")
 	asset1Decimals = uint8(1)
@@ -184,11 +221,24 @@ var _ = ginkgo.BeforeSuite(func() {
 			Address: sender,
 			Balance: 10_000_000,
 		},
+		{
+			Address: sender3,
+			Balance: 10_000_000,
+		},
+		{
+			Address: codec.MustAddressBech32(nconsts.HRP, auth.NewBLSAddress(bls.PublicFromSecretKey(validatorKeys[0]))),
+			Balance: 100_000_000,
+		},
 	}
 	gen.EmissionBalancer = genesis.EmissionBalancer{
 		MaxSupply:       10_000_000_000,
 		EmissionAddress: sender,
+		FeeSplit:        emission.DefaultFeeSplit(),
 	}
+	gen.StakingConfig.MinValidatorStake = 1_000_000
+	gen.StakingConfig.MinDelegatorStake = 100_000
+	gen.StakingConfig.UnbondingPeriod = 10
+	gen.EpochTracker.EpochLength = 10
 	genesisBytes, err = json.Marshal(gen)
 	gomega.Ω(err).Should(gomega.BeNil())
 
@@ -199,23 +249,27 @@ var _ = ginkgo.BeforeSuite(func() {
 	app := &appSender{}
 	for i := range instances {
 		nodeID := ids.GenerateTestNodeID()
-		sk, err := bls.NewSecretKey()
-		gomega.Ω(err).Should(gomega.BeNil())
+		sk := validatorKeys[i]
 		l, err := logFactory.Make(nodeID.String())
 		gomega.Ω(err).Should(gomega.BeNil())
 		dname, err := os.MkdirTemp("", fmt.Sprintf("%s-chainData", nodeID.String()))
 		gomega.Ω(err).Should(gomega.BeNil())
 		snowCtx := &snow.Context{
-			NetworkID:      networkID,
-			SubnetID:       subnetID,
-			ChainID:        chainID,
-			NodeID:         nodeID,
-			Log:            l,
-			ChainDataDir:   dname,
-			Metrics:        metrics.NewOptionalGatherer(),
-			PublicKey:      bls.PublicFromSecretKey(sk),
-			WarpSigner:     warp.NewSigner(sk, networkID, chainID),
-			ValidatorState: &validators.TestState{},
+			NetworkID:    networkID,
+			SubnetID:     subnetID,
+			ChainID:      chainID,
+			NodeID:       nodeID,
+			Log:          l,
+			ChainDataDir: dname,
+			Metrics:      metrics.NewOptionalGatherer(),
+			PublicKey:    bls.PublicFromSecretKey(sk),
+			WarpSigner:   warp.NewSigner(sk, networkID, chainID),
+			ValidatorState: &validators.TestState{
+				GetCurrentHeightF: func(context.Context) (uint64, error) {
+					return 1, nil
+				},
+				GetValidatorSetF: getValidatorSet,
+			},
 		}
 
 		toEngine := make(chan common.Message, 1)
@@ -229,7 +283,7 @@ var _ = ginkgo.BeforeSuite(func() {
 			genesisBytes,
 			nil,
 			[]byte(
-				`{"parallelism":3, "testMode":true, "logLevel":"debug", "trackedPairs":["*"]}`,
+				`{"parallelism":3, "testMode":true, "logLevel":"debug", "trackedPairs":["*"], "enableInvariantCheck":true}`,
 			),
 			toEngine,
 			nil,
@@ -274,7 +328,7 @@ var _ = ginkgo.BeforeSuite(func() {
 			gomega.Ω(balance).Should(gomega.Equal(alloc.Balance))
 			csupply += alloc.Balance
 		}
-		exists, symbol, decimals, metadata, supply, owner, warp, err := ncli.Asset(context.Background(), ids.Empty, false)
+		exists, symbol, decimals, metadata, supply, _, owner, _, warp, err := ncli.Asset(context.Background(), ids.Empty, false)
 		gomega.Ω(err).Should(gomega.BeNil())
 		gomega.Ω(exists).Should(gomega.BeTrue())
 		gomega.Ω(string(symbol)).Should(gomega.Equal(nconsts.Symbol))
@@ -438,22 +492,22 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 			//
 			// bandwidth: tx size
 			// compute: 5 for signature, 1 for base, 1 for transfer
-			// read: 2 keys reads, 1 had 0 chunks
+			// read: 3 keys reads (including the fee shard), 1 had 0 chunks
 			// allocate: 1 key created
-			// write: 1 key modified, 1 key new
-			transferTxConsumed := chain.Dimensions{227, 7, 12, 25, 26}
+			// write: 2 keys modified (including the fee shard), 1 key new
+			transferTxConsumed := chain.Dimensions{227, 7, 19, 25, 39}
 			gomega.Ω(results[0].Consumed).Should(gomega.Equal(transferTxConsumed))
 
 			// Fee explanation
 			//
 			// Multiply all unit consumption by 1 and sum
-			gomega.Ω(results[0].Fee).Should(gomega.Equal(uint64(297)))
+			gomega.Ω(results[0].Fee).Should(gomega.Equal(uint64(317)))
 		})
 
 		ginkgo.By("ensure balance is updated", func() {
 			balance, err := instances[1].ncli.Balance(context.Background(), sender, ids.Empty)
 			gomega.Ω(err).To(gomega.BeNil())
-			gomega.Ω(balance).To(gomega.Equal(uint64(9899703)))
+			gomega.Ω(balance).To(gomega.Equal(uint64(9899683)))
 			balance2, err := instances[1].ncli.Balance(context.Background(), sender2, ids.Empty)
 			gomega.Ω(err).To(gomega.BeNil())
 			gomega.Ω(balance2).To(gomega.Equal(uint64(100000)))
@@ -814,7 +868,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		gomega.Ω(string(result.Output)).
 			Should(gomega.ContainSubstring("asset missing"))
 
-		exists, _, _, _, _, _, _, err := instances[0].ncli.Asset(context.TODO(), assetID, false)
+		exists, _, _, _, _, _, _, _, _, err := instances[0].ncli.Asset(context.TODO(), assetID, false)
 		gomega.Ω(err).Should(gomega.BeNil())
 		gomega.Ω(exists).Should(gomega.BeFalse())
 	})
@@ -938,7 +992,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		gomega.Ω(err).Should(gomega.BeNil())
 		gomega.Ω(balance).Should(gomega.Equal(uint64(0)))
 
-		exists, symbol, decimals, metadata, supply, owner, warp, err := instances[0].ncli.Asset(context.TODO(), asset1ID, false)
+		exists, symbol, decimals, metadata, supply, _, owner, _, warp, err := instances[0].ncli.Asset(context.TODO(), asset1ID, false)
 		gomega.Ω(err).Should(gomega.BeNil())
 		gomega.Ω(exists).Should(gomega.BeTrue())
 		gomega.Ω(symbol).Should(gomega.Equal(asset1Symbol))
@@ -977,7 +1031,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		gomega.Ω(err).Should(gomega.BeNil())
 		gomega.Ω(balance).Should(gomega.Equal(uint64(0)))
 
-		exists, symbol, decimals, metadata, supply, owner, warp, err := instances[0].ncli.Asset(context.TODO(), asset1ID, false)
+		exists, symbol, decimals, metadata, supply, _, owner, _, warp, err := instances[0].ncli.Asset(context.TODO(), asset1ID, false)
 		gomega.Ω(err).Should(gomega.BeNil())
 		gomega.Ω(exists).Should(gomega.BeTrue())
 		gomega.Ω(symbol).Should(gomega.Equal(asset1Symbol))
@@ -1014,7 +1068,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		gomega.Ω(string(result.Output)).
 			Should(gomega.ContainSubstring("wrong owner"))
 
-		exists, symbol, decimals, metadata, supply, owner, warp, err := instances[0].ncli.Asset(context.TODO(), asset1ID, false)
+		exists, symbol, decimals, metadata, supply, _, owner, _, warp, err := instances[0].ncli.Asset(context.TODO(), asset1ID, false)
 		gomega.Ω(err).Should(gomega.BeNil())
 		gomega.Ω(exists).Should(gomega.BeTrue())
 		gomega.Ω(symbol).Should(gomega.Equal(asset1Symbol))
@@ -1052,7 +1106,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		gomega.Ω(err).Should(gomega.BeNil())
 		gomega.Ω(balance).Should(gomega.Equal(uint64(0)))
 
-		exists, symbol, decimals, metadata, supply, owner, warp, err := instances[0].ncli.Asset(context.TODO(), asset1ID, false)
+		exists, symbol, decimals, metadata, supply, _, owner, _, warp, err := instances[0].ncli.Asset(context.TODO(), asset1ID, false)
 		gomega.Ω(err).Should(gomega.BeNil())
 		gomega.Ω(exists).Should(gomega.BeTrue())
 		gomega.Ω(symbol).Should(gomega.Equal(asset1Symbol))
@@ -1086,7 +1140,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		gomega.Ω(string(result.Output)).
 			Should(gomega.ContainSubstring("invalid balance"))
 
-		exists, symbol, decimals, metadata, supply, owner, warp, err := instances[0].ncli.Asset(context.TODO(), asset1ID, false)
+		exists, symbol, decimals, metadata, supply, _, owner, _, warp, err := instances[0].ncli.Asset(context.TODO(), asset1ID, false)
 		gomega.Ω(err).Should(gomega.BeNil())
 		gomega.Ω(exists).Should(gomega.BeTrue())
 		gomega.Ω(symbol).Should(gomega.Equal(asset1Symbol))
@@ -1160,7 +1214,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		gomega.Ω(err).Should(gomega.BeNil())
 		gomega.Ω(balance).Should(gomega.Equal(uint64(0)))
 
-		exists, symbol, decimals, metadata, supply, owner, warp, err := instances[0].ncli.Asset(context.TODO(), asset1ID, false)
+		exists, symbol, decimals, metadata, supply, _, owner, _, warp, err := instances[0].ncli.Asset(context.TODO(), asset1ID, false)
 		gomega.Ω(err).Should(gomega.BeNil())
 		gomega.Ω(exists).Should(gomega.BeTrue())
 		gomega.Ω(symbol).Should(gomega.Equal(asset1Symbol))
@@ -1171,6 +1225,102 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		gomega.Ω(warp).Should(gomega.BeFalse())
 	})
 
+	ginkgo.It("rejects mint beyond max supply and after freezing", func() {
+		parser, err := instances[0].ncli.Parser(context.Background())
+		gomega.Ω(err).Should(gomega.BeNil())
+		submit, tx, _, err := instances[0].hcli.GenerateTransaction(
+			context.Background(),
+			parser,
+			nil,
+			&actions.CreateAsset{
+				Symbol:    []byte("CAP"),
+				Decimals:  0,
+				Metadata:  []byte("capped"),
+				MaxSupply: 20,
+			},
+			factory,
+		)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(submit(context.Background())).Should(gomega.BeNil())
+		accept := expectBlk(instances[0])
+		results := accept(false)
+		gomega.Ω(results).Should(gomega.HaveLen(1))
+		gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+		cappedID := tx.ID()
+
+		mint := func(value uint64) *chain.Result {
+			submit, _, _, err := instances[0].hcli.GenerateTransaction(
+				context.Background(),
+				parser,
+				nil,
+				&actions.MintAsset{
+					To:    rsender2,
+					Asset: cappedID,
+					Value: value,
+				},
+				factory,
+			)
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(submit(context.Background())).Should(gomega.BeNil())
+			accept := expectBlk(instances[0])
+			results := accept(false)
+			gomega.Ω(results).Should(gomega.HaveLen(1))
+			return results[0]
+		}
+
+		gomega.Ω(mint(15).Success).Should(gomega.BeTrue())
+		result := mint(10)
+		gomega.Ω(result.Success).Should(gomega.BeFalse())
+		gomega.Ω(string(result.Output)).
+			Should(gomega.ContainSubstring("max supply exceeded"))
+
+		submit, _, _, err = instances[0].hcli.GenerateTransaction(
+			context.Background(),
+			parser,
+			nil,
+			&actions.UpdateAsset{
+				Asset:      cappedID,
+				Owner:      rsender,
+				FreezeMint: true,
+			},
+			factory,
+		)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(submit(context.Background())).Should(gomega.BeNil())
+		accept = expectBlk(instances[0])
+		results = accept(false)
+		gomega.Ω(results).Should(gomega.HaveLen(1))
+		gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+
+		result = mint(1)
+		gomega.Ω(result.Success).Should(gomega.BeFalse())
+		gomega.Ω(string(result.Output)).
+			Should(gomega.ContainSubstring("minting is frozen"))
+
+		exists, _, _, metadata, supply, maxSupply, owner, frozen, _, err := instances[0].ncli.Asset(context.TODO(), cappedID, false)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(exists).Should(gomega.BeTrue())
+		gomega.Ω(metadata).Should(gomega.Equal([]byte("capped")))
+		gomega.Ω(supply).Should(gomega.Equal(uint64(15)))
+		gomega.Ω(maxSupply).Should(gomega.Equal(uint64(20)))
+		gomega.Ω(owner).Should(gomega.Equal(sender))
+		gomega.Ω(frozen).Should(gomega.BeTrue())
+	})
+
+	ginkgo.It("reports the max supply and frozen flag of assets", func() {
+		exists, _, _, _, _, maxSupply, _, frozen, _, err := instances[0].ncli.Asset(context.TODO(), ids.Empty, false)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(exists).Should(gomega.BeTrue())
+		gomega.Ω(maxSupply).Should(gomega.Equal(gen.EmissionBalancer.MaxSupply))
+		gomega.Ω(frozen).Should(gomega.BeFalse())
+
+		exists, _, _, _, _, maxSupply, _, frozen, _, err = instances[0].ncli.Asset(context.TODO(), asset1ID, false)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(exists).Should(gomega.BeTrue())
+		gomega.Ω(maxSupply).Should(gomega.Equal(uint64(0)))
+		gomega.Ω(frozen).Should(gomega.BeFalse())
+	})
+
 	ginkgo.It("rejects mint of native token", func() {
 		other, err := ed25519.GeneratePrivateKey()
 		gomega.Ω(err).Should(gomega.BeNil())
@@ -1293,6 +1443,358 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		gomega.Ω(balance).Should(gomega.Equal(uint64(10)))
 	})
 
+	ginkgo.It("mint, transfer and burn an nft", func() {
+		parser, err := instances[0].ncli.Parser(context.Background())
+		gomega.Ω(err).Should(gomega.BeNil())
+		send := func(action chain.Action, factory chain.AuthFactory) (*chain.Result, ids.ID) {
+			submit, tx, _, err := instances[0].hcli.GenerateTransaction(
+				context.Background(),
+				parser,
+				nil,
+				action,
+				factory,
+			)
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(submit(context.Background())).Should(gomega.BeNil())
+			accept := expectBlk(instances[0])
+			results := accept(false)
+			gomega.Ω(results).Should(gomega.HaveLen(1))
+			return results[0], tx.ID()
+		}
+
+		result, collectionID := send(&actions.CreateNFTCollection{
+			Symbol:   []byte("DATA"),
+			Metadata: []byte("datasets"),
+		}, factory)
+		gomega.Ω(result.Success).Should(gomega.BeTrue())
+
+		result, _ = send(&actions.MintNFT{
+			Collection: collectionID,
+			ID:         1,
+			To:         rsender,
+			Metadata:   []byte("dataset 1"),
+		}, factory2)
+		gomega.Ω(result.Success).Should(gomega.BeFalse())
+		gomega.Ω(string(result.Output)).Should(gomega.ContainSubstring("wrong owner"))
+
+		result, _ = send(&actions.MintNFT{
+			Collection: collectionID,
+			ID:         1,
+			To:         rsender,
+			Metadata:   []byte("dataset 1"),
+		}, factory)
+		gomega.Ω(result.Success).Should(gomega.BeTrue())
+
+		result, _ = send(&actions.MintNFT{
+			Collection: collectionID,
+			ID:         1,
+			To:         rsender2,
+			Metadata:   []byte("dataset 1 again"),
+		}, factory)
+		gomega.Ω(result.Success).Should(gomega.BeFalse())
+		gomega.Ω(string(result.Output)).Should(gomega.ContainSubstring("nft already exists"))
+
+		exists, owner, metadata, err := instances[0].ncli.NFT(context.TODO(), collectionID, 1)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(exists).Should(gomega.BeTrue())
+		gomega.Ω(owner).Should(gomega.Equal(sender))
+		gomega.Ω(metadata).Should(gomega.Equal([]byte("dataset 1")))
+
+		result, _ = send(&actions.TransferNFT{
+			Collection: collectionID,
+			ID:         1,
+			To:         rsender2,
+		}, factory)
+		gomega.Ω(result.Success).Should(gomega.BeTrue())
+
+		nfts, _, err := instances[0].ncli.NFTsOfOwner(context.TODO(), sender, nil, 0)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(nfts).Should(gomega.BeEmpty())
+		nfts, _, err = instances[0].ncli.NFTsOfOwner(context.TODO(), sender2, nil, 0)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(nfts).Should(gomega.HaveLen(1))
+		gomega.Ω(nfts[0].Collection).Should(gomega.Equal(collectionID))
+		gomega.Ω(nfts[0].ID).Should(gomega.Equal(uint64(1)))
+
+		result, _ = send(&actions.BurnNFT{
+			Collection: collectionID,
+			ID:         1,
+		}, factory)
+		gomega.Ω(result.Success).Should(gomega.BeFalse())
+		gomega.Ω(string(result.Output)).Should(gomega.ContainSubstring("wrong owner"))
+
+		result, _ = send(&actions.BurnNFT{
+			Collection: collectionID,
+			ID:         1,
+		}, factory2)
+		gomega.Ω(result.Success).Should(gomega.BeTrue())
+
+		exists, _, _, err = instances[0].ncli.NFT(context.TODO(), collectionID, 1)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(exists).Should(gomega.BeFalse())
+		nfts, _, err = instances[0].ncli.NFTsOfOwner(context.TODO(), sender2, nil, 0)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(nfts).Should(gomega.BeEmpty())
+
+		exists, symbol, metadata, supply, owner, err := instances[0].ncli.NFTCollection(context.TODO(), collectionID)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(exists).Should(gomega.BeTrue())
+		gomega.Ω(symbol).Should(gomega.Equal([]byte("DATA")))
+		gomega.Ω(metadata).Should(gomega.Equal([]byte("datasets")))
+		gomega.Ω(supply).Should(gomega.Equal(uint64(0)))
+		gomega.Ω(owner).Should(gomega.Equal(sender))
+	})
+
+	ginkgo.It("pages the nfts of an owner", func() {
+		parser, err := instances[0].ncli.Parser(context.Background())
+		gomega.Ω(err).Should(gomega.BeNil())
+		send := func(action chain.Action) (*chain.Result, ids.ID) {
+			submit, tx, _, err := instances[0].hcli.GenerateTransaction(
+				context.Background(),
+				parser,
+				nil,
+				action,
+				factory,
+			)
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(submit(context.Background())).Should(gomega.BeNil())
+			accept := expectBlk(instances[0])
+			results := accept(false)
+			gomega.Ω(results).Should(gomega.HaveLen(1))
+			return results[0], tx.ID()
+		}
+
+		result, collectionID := send(&actions.CreateNFTCollection{
+			Symbol:   []byte("PAGE"),
+			Metadata: []byte("paged"),
+		})
+		gomega.Ω(result.Success).Should(gomega.BeTrue())
+		for id := uint64(1); id <= 3; id++ {
+			result, _ = send(&actions.MintNFT{
+				Collection: collectionID,
+				ID:         id,
+				To:         rsender,
+				Metadata:   []byte("page"),
+			})
+			gomega.Ω(result.Success).Should(gomega.BeTrue())
+		}
+
+		nfts, next, err := instances[0].ncli.NFTsOfOwner(context.TODO(), sender, nil, 2)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(nfts).Should(gomega.HaveLen(2))
+		gomega.Ω(nfts[0].ID).Should(gomega.Equal(uint64(1)))
+		gomega.Ω(nfts[1].ID).Should(gomega.Equal(uint64(2)))
+		gomega.Ω(next).ShouldNot(gomega.BeNil())
+		gomega.Ω(next.Collection).Should(gomega.Equal(collectionID))
+		gomega.Ω(next.ID).Should(gomega.Equal(uint64(3)))
+
+		nfts, next, err = instances[0].ncli.NFTsOfOwner(context.TODO(), sender, next, 2)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(nfts).Should(gomega.HaveLen(1))
+		gomega.Ω(nfts[0].ID).Should(gomega.Equal(uint64(3)))
+		gomega.Ω(next).Should(gomega.BeNil())
+	})
+
+	ginkgo.It("sends several transfers at once", func() {
+		parser, err := instances[0].ncli.Parser(context.Background())
+		gomega.Ω(err).Should(gomega.BeNil())
+		send := func(action chain.Action, factory chain.AuthFactory) (*chain.Result, ids.ID) {
+			submit, tx, _, err := instances[0].hcli.GenerateTransaction(
+				context.Background(),
+				parser,
+				nil,
+				action,
+				factory,
+			)
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(submit(context.Background())).Should(gomega.BeNil())
+			accept := expectBlk(instances[0])
+			results := accept(false)
+			gomega.Ω(results).Should(gomega.HaveLen(1))
+			return results[0], tx.ID()
+		}
+
+		result, assetID := send(&actions.CreateAsset{
+			Symbol:   []byte("MLT"),
+			Decimals: 0,
+			Metadata: []byte("multi transfer"),
+		}, factory)
+		gomega.Ω(result.Success).Should(gomega.BeTrue())
+		result, _ = send(&actions.MintAsset{
+			To:    rsender,
+			Asset: assetID,
+			Value: 100,
+		}, factory)
+		gomega.Ω(result.Success).Should(gomega.BeTrue())
+
+		nativeBalance, err := instances[0].ncli.Balance(context.TODO(), sender2, ids.Empty)
+		gomega.Ω(err).Should(gomega.BeNil())
+
+		// The last transfer exceeds the balance, so none of them is applied
+		result, _ = send(&actions.MultiTransfer{
+			Transfers: []*actions.TransferEntry{
+				{To: rsender2, Asset: assetID, Value: 60},
+				{To: rsender2, Asset: ids.Empty, Value: 1},
+				{To: rsender2, Asset: assetID, Value: 41},
+			},
+		}, factory)
+		gomega.Ω(result.Success).Should(gomega.BeFalse())
+		balance, err := instances[0].ncli.Balance(context.TODO(), sender2, assetID)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(balance).Should(gomega.Equal(uint64(0)))
+
+		result, _ = send(&actions.MultiTransfer{
+			Transfers: []*actions.TransferEntry{
+				{To: rsender2, Asset: assetID, Value: 60, Memo: []byte("first")},
+				{To: rsender2, Asset: ids.Empty, Value: 1},
+				{To: rsender2, Asset: assetID, Value: 40},
+			},
+		}, factory)
+		gomega.Ω(result.Success).Should(gomega.BeTrue())
+		balance, err = instances[0].ncli.Balance(context.TODO(), sender, assetID)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(balance).Should(gomega.Equal(uint64(0)))
+		balance, err = instances[0].ncli.Balance(context.TODO(), sender2, assetID)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(balance).Should(gomega.Equal(uint64(100)))
+		balance, err = instances[0].ncli.Balance(context.TODO(), sender2, ids.Empty)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(balance).Should(gomega.Equal(nativeBalance + 1))
+	})
+
+	ginkgo.It("spends an allowance with transfer from", func() {
+		parser, err := instances[0].ncli.Parser(context.Background())
+		gomega.Ω(err).Should(gomega.BeNil())
+		send := func(action chain.Action, factory chain.AuthFactory) (*chain.Result, ids.ID) {
+			submit, tx, _, err := instances[0].hcli.GenerateTransaction(
+				context.Background(),
+				parser,
+				nil,
+				action,
+				factory,
+			)
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(submit(context.Background())).Should(gomega.BeNil())
+			accept := expectBlk(instances[0])
+			results := accept(false)
+			gomega.Ω(results).Should(gomega.HaveLen(1))
+			return results[0], tx.ID()
+		}
+
+		result, assetID := send(&actions.CreateAsset{
+			Symbol:   []byte("ALW"),
+			Decimals: 0,
+			Metadata: []byte("allowance"),
+		}, factory)
+		gomega.Ω(result.Success).Should(gomega.BeTrue())
+		result, _ = send(&actions.MintAsset{
+			To:    rsender,
+			Asset: assetID,
+			Value: 100,
+		}, factory)
+		gomega.Ω(result.Success).Should(gomega.BeTrue())
+
+		result, _ = send(&actions.TransferFrom{
+			From:  rsender,
+			To:    rsender2,
+			Asset: assetID,
+			Value: 10,
+		}, factory2)
+		gomega.Ω(result.Success).Should(gomega.BeFalse())
+		gomega.Ω(string(result.Output)).Should(gomega.ContainSubstring("allowance exceeded"))
+
+		result, _ = send(&actions.ApproveAsset{
+			Spender: rsender,
+			Asset:   assetID,
+			Value:   10,
+		}, factory)
+		gomega.Ω(result.Success).Should(gomega.BeFalse())
+		gomega.Ω(string(result.Output)).Should(gomega.ContainSubstring("cannot approve self"))
+
+		result, _ = send(&actions.ApproveAsset{
+			Spender: rsender2,
+			Asset:   assetID,
+			Value:   30,
+		}, factory)
+		gomega.Ω(result.Success).Should(gomega.BeTrue())
+
+		result, _ = send(&actions.TransferFrom{
+			From:  rsender,
+			To:    rsender2,
+			Asset: assetID,
+			Value: 20,
+		}, factory2)
+		gomega.Ω(result.Success).Should(gomega.BeTrue())
+
+		amount, expiryBlock, expired, err := instances[0].ncli.Allowance(context.TODO(), sender, sender2, assetID)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(amount).Should(gomega.Equal(uint64(10)))
+		gomega.Ω(expiryBlock).Should(gomega.Equal(uint64(0)))
+		gomega.Ω(expired).Should(gomega.BeFalse())
+		balance, err := instances[0].ncli.Balance(context.TODO(), sender, assetID)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(balance).Should(gomega.Equal(uint64(80)))
+		balance, err = instances[0].ncli.Balance(context.TODO(), sender2, assetID)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(balance).Should(gomega.Equal(uint64(20)))
+
+		result, _ = send(&actions.TransferFrom{
+			From:  rsender,
+			To:    rsender2,
+			Asset: assetID,
+			Value: 11,
+		}, factory2)
+		gomega.Ω(result.Success).Should(gomega.BeFalse())
+		gomega.Ω(string(result.Output)).Should(gomega.ContainSubstring("allowance exceeded"))
+
+		// The next block is built on top of the last accepted one, so an
+		// allowance expiring at its height could never be used.
+		_, acceptedHeight, _, err := instances[0].hcli.Accepted(context.TODO())
+		gomega.Ω(err).Should(gomega.BeNil())
+		result, _ = send(&actions.ApproveAsset{
+			Spender:     rsender2,
+			Asset:       assetID,
+			Value:       30,
+			ExpiryBlock: acceptedHeight + 1,
+		}, factory)
+		gomega.Ω(result.Success).Should(gomega.BeFalse())
+		gomega.Ω(string(result.Output)).Should(gomega.ContainSubstring("expiry block must be in the future"))
+
+		// An allowance expiring the block after the approval lapses before
+		// the spender's transaction is included.
+		_, acceptedHeight, _, err = instances[0].hcli.Accepted(context.TODO())
+		gomega.Ω(err).Should(gomega.BeNil())
+		result, _ = send(&actions.ApproveAsset{
+			Spender:     rsender2,
+			Asset:       assetID,
+			Value:       30,
+			ExpiryBlock: acceptedHeight + 2,
+		}, factory)
+		gomega.Ω(result.Success).Should(gomega.BeTrue())
+
+		result, _ = send(&actions.TransferFrom{
+			From:  rsender,
+			To:    rsender2,
+			Asset: assetID,
+			Value: 5,
+		}, factory2)
+		gomega.Ω(result.Success).Should(gomega.BeFalse())
+		gomega.Ω(string(result.Output)).Should(gomega.ContainSubstring("allowance expired"))
+		_, _, expired, err = instances[0].ncli.Allowance(context.TODO(), sender, sender2, assetID)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(expired).Should(gomega.BeTrue())
+
+		result, _ = send(&actions.ApproveAsset{
+			Spender: rsender2,
+			Asset:   assetID,
+			Value:   0,
+		}, factory)
+		gomega.Ω(result.Success).Should(gomega.BeTrue())
+		amount, _, _, err = instances[0].ncli.Allowance(context.TODO(), sender, sender2, assetID)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(amount).Should(gomega.Equal(uint64(0)))
+	})
+
 	ginkgo.It("import warp message with nil when expected", func() {
 		tx := chain.NewTx(
 			&chain.Base{
@@ -1546,6 +2048,223 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		gomega.Ω(result.Success).Should(gomega.BeFalse())
 		gomega.Ω(string(result.Output)).Should(gomega.ContainSubstring("not warp asset"))
 	})
+
+	var (
+		validatorFactory   *auth.BLSFactory
+		validatorAddress   codec.Address
+		stakeEndBlock      uint64
+		delegationEndBlock uint64
+		releaseBlock       uint64
+	)
+	ginkgo.It("registers a validator stake", func() {
+		// The VMs become validators of the subnet, so that their node signer
+		// keys can stake
+		validatorSetLock.Lock()
+		validatorSet = map[ids.NodeID]*validators.GetValidatorOutput{}
+		for i, inst := range instances {
+			validatorSet[inst.nodeID] = &validators.GetValidatorOutput{
+				NodeID:    inst.nodeID,
+				PublicKey: bls.PublicFromSecretKey(validatorKeys[i]),
+				Weight:    1,
+			}
+		}
+		validatorSetLock.Unlock()
+
+		validatorFactory = auth.NewBLSFactory(validatorKeys[0])
+		validatorAddress = auth.NewBLSAddress(bls.PublicFromSecretKey(validatorKeys[0]))
+
+		_, height, _, err := instances[0].hcli.Accepted(context.Background())
+		gomega.Ω(err).Should(gomega.BeNil())
+		stakeEndBlock = height + 1 + 60
+		stakeInfo := &actions.ValidatorStakeInfo{
+			NodeID:            instances[0].nodeID.Bytes(),
+			StakeStartBlock:   height + 1,
+			StakeEndBlock:     stakeEndBlock,
+			StakedAmount:      1_000_000,
+			DelegationFeeRate: 10,
+			RewardAddress:     validatorAddress,
+		}
+		stakeInfoBytes, err := stakeInfo.Marshal()
+		gomega.Ω(err).Should(gomega.BeNil())
+		signature, err := validatorFactory.Sign(stakeInfoBytes)
+		gomega.Ω(err).Should(gomega.BeNil())
+		signaturePacker := codec.NewWriter(signature.Size(), signature.Size())
+		signature.Marshal(signaturePacker)
+
+		result := expectTx(&actions.RegisterValidatorStake{
+			StakeInfo:     stakeInfoBytes,
+			AuthSignature: signaturePacker.Bytes(),
+		}, validatorFactory)
+		gomega.Ω(result.Success).Should(gomega.BeTrue())
+
+		_, endBlock, stakedAmount, _, _, owner, _, _, _, err := instances[0].ncli.ValidatorStake(context.Background(), instances[0].nodeID, codec.EmptyAddress)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(endBlock).Should(gomega.Equal(stakeEndBlock))
+		gomega.Ω(stakedAmount).Should(gomega.Equal(uint64(1_000_000)))
+		gomega.Ω(owner).Should(gomega.Equal(validatorAddress))
+		expectNoDiscrepancies()
+	})
+
+	ginkgo.It("delegates to the validator", func() {
+		_, height, _, err := instances[0].hcli.Accepted(context.Background())
+		gomega.Ω(err).Should(gomega.BeNil())
+		delegationEndBlock = height + 1 + 40
+		result := expectTx(&actions.DelegateUserStake{
+			NodeID:          instances[0].nodeID.Bytes(),
+			StakeStartBlock: height + 1,
+			StakeEndBlock:   delegationEndBlock,
+			StakedAmount:    1_000_000,
+			RewardAddress:   rsender3,
+		}, factory3)
+		gomega.Ω(result.Success).Should(gomega.BeTrue())
+
+		_, endBlock, stakedAmount, _, owner, _, _, err := instances[0].ncli.UserStake(context.Background(), rsender3, instances[0].nodeID)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(endBlock).Should(gomega.Equal(delegationEndBlock))
+		gomega.Ω(stakedAmount).Should(gomega.Equal(uint64(1_000_000)))
+		gomega.Ω(owner).Should(gomega.Equal(rsender3))
+		expectNoDiscrepancies()
+	})
+
+	ginkgo.It("shares the fees with the delegations of a validator registered by its heartbeats", func() {
+		// The validator registers for every epoch boundary with its first
+		// heartbeat of the epoch, and its delegation earns a share of the fees
+		// distributed at the boundary
+		_, height, _, err := instances[0].hcli.Accepted(context.Background())
+		gomega.Ω(err).Should(gomega.BeNil())
+		epochLength := gen.EpochTracker.EpochLength
+		expectHeartbeats(validatorFactory, (height/epochLength+3)*epochLength+1)
+
+		pendingReward, _, _, err := instances[0].ncli.PendingDelegationRewards(context.Background(), instances[0].nodeID, rsender3)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(pendingReward).Should(gomega.BeNumerically(">", 0))
+		balance, err := instances[0].ncli.Balance(context.Background(), sender3, ids.Empty)
+		gomega.Ω(err).Should(gomega.BeNil())
+
+		result := expectTx(&actions.ClaimDelegationStakeRewards{
+			NodeID:           instances[0].nodeID.Bytes(),
+			UserStakeAddress: rsender3,
+		}, factory3)
+		gomega.Ω(result.Success).Should(gomega.BeTrue())
+		_, output, err := actions.SplitEmissionOutput(result.Output)
+		gomega.Ω(err).Should(gomega.BeNil())
+		claim, err := actions.UnmarshalClaimRewardsResult(output)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(claim.RewardAmount).Should(gomega.Equal(pendingReward))
+
+		newBalance, err := instances[0].ncli.Balance(context.Background(), sender3, ids.Empty)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(newBalance).Should(gomega.Equal(balance + claim.RewardAmount - result.Fee))
+		expectNoDiscrepancies()
+	})
+
+	ginkgo.It("undelegates once the delegation ends", func() {
+		result := expectTx(&actions.UndelegateUserStake{
+			NodeID:        instances[0].nodeID.Bytes(),
+			RewardAddress: rsender3,
+		}, factory3)
+		gomega.Ω(result.Success).Should(gomega.BeFalse())
+		gomega.Ω(string(result.Output)).Should(gomega.ContainSubstring("stake not ended"))
+
+		expectHeartbeats(validatorFactory, delegationEndBlock)
+		result = expectTx(&actions.UndelegateUserStake{
+			NodeID:        instances[0].nodeID.Bytes(),
+			RewardAddress: rsender3,
+		}, factory3)
+		gomega.Ω(result.Success).Should(gomega.BeTrue())
+		_, output, err := actions.SplitEmissionOutput(result.Output)
+		gomega.Ω(err).Should(gomega.BeNil())
+		undelegation, err := actions.UnmarshalUndelegateUserStakeResult(output)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(undelegation.StakedAmount).Should(gomega.Equal(uint64(1_000_000)))
+
+		_, _, stakedAmount, _, _, _, unbonding, err := instances[0].ncli.UserStake(context.Background(), rsender3, instances[0].nodeID)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(stakedAmount).Should(gomega.BeZero())
+		gomega.Ω(unbonding).Should(gomega.HaveLen(1))
+		gomega.Ω(unbonding[0].Amount).Should(gomega.Equal(uint64(1_000_000)))
+		releaseBlock = unbonding[0].ReleaseBlock
+		expectNoDiscrepancies()
+	})
+
+	ginkgo.It("releases the undelegated stake after unbonding", func() {
+		result := expectTx(&actions.ReleaseUnbondedStake{}, factory3)
+		gomega.Ω(result.Success).Should(gomega.BeFalse())
+		gomega.Ω(string(result.Output)).Should(gomega.ContainSubstring("no unbonded stake to release"))
+
+		expectHeartbeats(validatorFactory, releaseBlock)
+		balance, err := instances[0].ncli.Balance(context.Background(), sender3, ids.Empty)
+		gomega.Ω(err).Should(gomega.BeNil())
+		result = expectTx(&actions.ReleaseUnbondedStake{}, factory3)
+		gomega.Ω(result.Success).Should(gomega.BeTrue())
+		release, err := actions.UnmarshalReleaseUnbondedStakeResult(result.Output)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(release.ReleasedAmount).Should(gomega.Equal(uint64(1_000_000)))
+
+		newBalance, err := instances[0].ncli.Balance(context.Background(), sender3, ids.Empty)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(newBalance).Should(gomega.Equal(balance + release.ReleasedAmount - result.Fee))
+		expectNoDiscrepancies()
+	})
+
+	ginkgo.It("pays out the rewards of the validator once its stake ends", func() {
+		result := expectTx(&actions.ClaimValidatorStakeRewards{
+			NodeID: instances[0].nodeID.Bytes(),
+		}, validatorFactory)
+		gomega.Ω(result.Success).Should(gomega.BeFalse())
+		gomega.Ω(string(result.Output)).Should(gomega.ContainSubstring("stake not ended"))
+
+		expectHeartbeats(validatorFactory, stakeEndBlock)
+		pendingReward, _, _, err := instances[0].ncli.PendingValidatorRewards(context.Background(), instances[0].nodeID)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(pendingReward).Should(gomega.BeNumerically(">", 0))
+		balance, err := instances[0].ncli.Balance(context.Background(), codec.MustAddressBech32(nconsts.HRP, validatorAddress), ids.Empty)
+		gomega.Ω(err).Should(gomega.BeNil())
+
+		result = expectTx(&actions.ClaimValidatorStakeRewards{
+			NodeID: instances[0].nodeID.Bytes(),
+		}, validatorFactory)
+		gomega.Ω(result.Success).Should(gomega.BeTrue())
+		_, output, err := actions.SplitEmissionOutput(result.Output)
+		gomega.Ω(err).Should(gomega.BeNil())
+		claim, err := actions.UnmarshalClaimRewardsResult(output)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(claim.RewardAmount).Should(gomega.Equal(pendingReward))
+
+		newBalance, err := instances[0].ncli.Balance(context.Background(), codec.MustAddressBech32(nconsts.HRP, validatorAddress), ids.Empty)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(newBalance).Should(gomega.Equal(balance + claim.RewardAmount - result.Fee))
+		expectNoDiscrepancies()
+	})
+
+	ginkgo.It("burns NAI out of the total supply", func() {
+		// Rewards are minted at epoch boundaries, so the burn is kept out of
+		// them
+		_, height, _, err := instances[0].hcli.Accepted(context.Background())
+		gomega.Ω(err).Should(gomega.BeNil())
+		if (height+1)%gen.EpochTracker.EpochLength == 0 {
+			expectHeartbeats(validatorFactory, height+1)
+		}
+		_, totalSupply, _, _, _, _, _, err := instances[0].ncli.EmissionInfo(context.Background())
+		gomega.Ω(err).Should(gomega.BeNil())
+		balance, err := instances[0].ncli.Balance(context.Background(), sender3, ids.Empty)
+		gomega.Ω(err).Should(gomega.BeNil())
+
+		result := expectTx(&actions.BurnAsset{
+			Asset: ids.Empty,
+			Value: 100_000,
+		}, factory3)
+		gomega.Ω(result.Success).Should(gomega.BeTrue())
+
+		// Fees are only burned when the fee split burns a share of them
+		_, newTotalSupply, _, _, _, _, _, err := instances[0].ncli.EmissionInfo(context.Background())
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(newTotalSupply).Should(gomega.Equal(totalSupply - 100_000))
+		newBalance, err := instances[0].ncli.Balance(context.Background(), sender3, ids.Empty)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(newBalance).Should(gomega.Equal(balance - 100_000 - result.Fee))
+		expectNoDiscrepancies()
+	})
 })
 
 func expectBlk(i instance) func(bool) []*chain.Result {
@@ -1617,6 +2336,100 @@ func expectBlkWithContext(i instance) func(bool) []*chain.Result {
 	}
 }
 
+// getValidatorSet returns the validators of the subnet once the staking specs
+// set them.
+func getValidatorSet(context.Context, uint64, ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+	validatorSetLock.RLock()
+	defer validatorSetLock.RUnlock()
+
+	if validatorSet == nil {
+		return nil, errValidatorsUnavailable
+	}
+	return validatorSet, nil
+}
+
+// issuedTxs is the number of transactions generated by [generateTx].
+var issuedTxs uint64
+
+// feeBump raises the max fee of a transaction, so that the same action can be
+// issued again within the second its timestamp is rounded to.
+type feeBump uint64
+
+func (f feeBump) Base(base *chain.Base) {
+	base.MaxFee += uint64(f)
+}
+
+// generateTx generates a transaction of [action], signed with [authFactory],
+// for the first VM.
+func generateTx(action chain.Action, authFactory chain.AuthFactory) (func(context.Context) error, *chain.Transaction) {
+	parser, err := instances[0].ncli.Parser(context.TODO())
+	gomega.Ω(err).Should(gomega.BeNil())
+	issuedTxs++
+	submit, tx, _, err := instances[0].hcli.GenerateTransaction(context.TODO(), parser, nil, action, authFactory, feeBump(issuedTxs))
+	gomega.Ω(err).Should(gomega.BeNil())
+	return submit, tx
+}
+
+// expectTx issues [action], signed with [authFactory], to the first VM and
+// accepts the block that includes it.
+func expectTx(action chain.Action, authFactory chain.AuthFactory) *chain.Result {
+	submit, tx := generateTx(action, authFactory)
+	return acceptTx(submit, tx)
+}
+
+// acceptTx issues [tx] to the first VM and accepts the block that includes it.
+// It checks that the max units of the transaction fit in a block: a
+// transaction that declares more state than a block can hold is never
+// included.
+func acceptTx(submit func(context.Context) error, tx *chain.Transaction) *chain.Result {
+	rules := instances[0].vm.Rules(time.Now().UnixMilli())
+	maxUnits, err := tx.MaxUnits(instances[0].vm.StateManager(), rules)
+	gomega.Ω(err).Should(gomega.BeNil())
+	maxBlockUnits := rules.GetMaxBlockUnits()
+	for i := range maxUnits {
+		gomega.Ω(maxUnits[i]).Should(gomega.BeNumerically("<=", maxBlockUnits[i]))
+	}
+
+	gomega.Ω(submit(context.TODO())).Should(gomega.BeNil())
+	results := expectBlk(instances[0])(false)
+	gomega.Ω(results).Should(gomega.HaveLen(1))
+	return results[0]
+}
+
+// expectHeartbeats sends a heartbeat of the validator with [authFactory] in
+// every block until the block at [height] is accepted. The transaction that
+// processes an epoch boundary sweeps the fee shard picked by its ID, so the
+// heartbeats sent at epoch boundaries are generated until they sweep the
+// shard the validator pays its fees into, for the fees to be distributed.
+func expectHeartbeats(authFactory chain.AuthFactory, height uint64) {
+	for {
+		_, lastAccepted, _, err := instances[0].hcli.Accepted(context.TODO())
+		gomega.Ω(err).Should(gomega.BeNil())
+		if lastAccepted >= height {
+			return
+		}
+		action := &actions.ValidatorHeartbeat{
+			NodeID: instances[0].nodeID.Bytes(),
+		}
+		submit, tx := generateTx(action, authFactory)
+		if (lastAccepted+1)%gen.EpochTracker.EpochLength == 0 {
+			for tx.ID()[0]%storage.NumFeeShards != storage.FeeShard(tx.Auth.Sponsor()) {
+				submit, tx = generateTx(action, authFactory)
+			}
+		}
+		result := acceptTx(submit, tx)
+		gomega.Ω(result.Success).Should(gomega.BeTrue())
+	}
+}
+
+// expectNoDiscrepancies checks that the NAI held in state and by the emission
+// balancer adds up to the supply it tracks.
+func expectNoDiscrepancies() {
+	reply, err := instances[0].ncli.InvariantCheck(context.TODO())
+	gomega.Ω(err).Should(gomega.BeNil())
+	gomega.Ω(reply.Discrepancies).Should(gomega.BeEmpty())
+}
+
 var _ common.AppSender = &appSender{}
 
 type appSender struct {
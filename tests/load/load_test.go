@@ -48,6 +48,7 @@ import (
 	"github.com/nuklai/nuklaivm/auth"
 	nconsts "github.com/nuklai/nuklaivm/consts"
 	"github.com/nuklai/nuklaivm/controller"
+	"github.com/nuklai/nuklaivm/emission"
 	"github.com/nuklai/nuklaivm/genesis"
 	nrpc "github.com/nuklai/nuklaivm/rpc"
 )
@@ -205,6 +206,7 @@ var _ = ginkgo.BeforeSuite(func() {
 	gen.EmissionBalancer = genesis.EmissionBalancer{
 		MaxSupply:       hconsts.MaxUint64,
 		EmissionAddress: sender,
+		FeeSplit:        emission.DefaultFeeSplit(),
 	}
 	genesisBytes, err = json.Marshal(gen)
 	gomega.Ω(err).Should(gomega.BeNil())